@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// SleepContextWith implements SleepContext for a Clock whose Timers are
+// made by newTimer, such as one adapting another package's clock. It
+// returns ctx's error at once if ctx is already done, and nil at once
+// if d is not positive. Otherwise it waits for a Timer from newTimer(d)
+// to fire, returning nil, or for ctx to be done, returning ctx's error,
+// and stops the Timer before returning.
+func SleepContextWith(ctx context.Context, d time.Duration, newTimer func(time.Duration) Timer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+	t := newTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Monotonic implements NowMonotonic for a Clock adapting another
+// package's clock, which has no monotonic clock of its own, as the time
+// that has passed on the other clock since the Monotonic was made. A
+// Clock can embed it to get its NowMonotonic method.
+type Monotonic struct {
+	now   func() time.Time
+	start time.Time
+}
+
+// NewMonotonic returns a Monotonic for the clock whose time now reads.
+func NewMonotonic(now func() time.Time) Monotonic {
+	return Monotonic{now: now, start: now()}
+}
+
+// NowMonotonic returns the time that has passed since m was made.
+func (m Monotonic) NowMonotonic() time.Duration {
+	return m.now().Sub(m.start)
+}
@@ -0,0 +1,46 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSleepContextWith(t *testing.T) {
+	fc := NewFake(WithLeakCheck(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := SleepContextWith(ctx, 0, fc.NewTimer); err != nil {
+		t.Errorf("SleepContextWith(0) = %v, want nil", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- SleepContextWith(ctx, time.Second, fc.NewTimer) }()
+	fc.BlockUntil(1)
+	fc.Add(time.Second)
+	if err := <-done; err != nil {
+		t.Errorf("SleepContextWith after its Timer fired = %v, want nil", err)
+	}
+
+	go func() { done <- SleepContextWith(ctx, time.Second, fc.NewTimer) }()
+	fc.BlockUntil(1)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("SleepContextWith after cancel = %v, want %v", err, context.Canceled)
+	}
+	if err := SleepContextWith(ctx, 0, fc.NewTimer); err != context.Canceled {
+		t.Errorf("SleepContextWith with a done Context = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestMonotonic(t *testing.T) {
+	fc := NewFake()
+	fc.Add(time.Hour)
+	m := NewMonotonic(fc.Now)
+	if got := m.NowMonotonic(); got != 0 {
+		t.Errorf("NowMonotonic() = %v, want 0", got)
+	}
+	fc.Add(time.Minute)
+	if got := m.NowMonotonic(); got != time.Minute {
+		t.Errorf("NowMonotonic() = %v, want %v", got, time.Minute)
+	}
+}
@@ -0,0 +1,88 @@
+package clock
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cached returns a Clock whose Now reads base's time at most once per
+// window and otherwise returns the last reading, for code so hot that
+// reading the time shows up in profiles. Each Now reads only base's
+// NowMonotonic to tell whether the last reading is window old, so,
+// unlike with Coarse, nothing runs while the clock isn't being read,
+// and nothing is left waiting on base.
+//
+// Cached's readings lag base's by less than window, and never go
+// backwards if base's don't. NowMonotonic is read along with the time
+// and cached the same way, and NowUnix, Since and Until are computed
+// from the cached times. Sleep and the clock's Timers and Tickers are
+// base's.
+func Cached(base Clock, window time.Duration) Clock {
+	return &cached{Clock: base, window: window}
+}
+
+type cached struct {
+	Clock
+	window time.Duration
+
+	// last is the last reading, or nil if there hasn't been one.
+	last atomic.Pointer[reading]
+	mu   sync.Mutex
+}
+
+// reading is a reading of both of a Clock's clocks.
+type reading struct {
+	t    time.Time
+	mono time.Duration
+}
+
+// fresh reports whether r is less than window old by base's monotonic
+// clock.
+func (c *cached) fresh(r *reading) bool {
+	return r != nil && c.Clock.NowMonotonic()-r.mono < c.window
+}
+
+// read returns the last reading, first taking a new one if it is too
+// old.
+func (c *cached) read() *reading {
+	if r := c.last.Load(); c.fresh(r) {
+		return r
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r := c.last.Load(); c.fresh(r) {
+		return r
+	}
+	r := &reading{c.Clock.Now(), c.Clock.NowMonotonic()}
+	c.last.Store(r)
+	return r
+}
+
+func (c *cached) Now() time.Time {
+	return c.read().t
+}
+
+func (c *cached) NowUnix() int64 {
+	return c.Now().Unix()
+}
+
+func (c *cached) NowUnixMilli() int64 {
+	return c.Now().UnixMilli()
+}
+
+func (c *cached) NowUnixNano() int64 {
+	return c.Now().UnixNano()
+}
+
+func (c *cached) NowMonotonic() time.Duration {
+	return c.read().mono
+}
+
+func (c *cached) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *cached) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCached(t *testing.T) {
+	fc := NewFake()
+	start := fc.Now()
+	clk := Cached(fc, 10*time.Microsecond)
+	if got := clk.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, want %v", got, start)
+	}
+	fc.Add(9 * time.Microsecond)
+	if got := clk.Now(); !got.Equal(start) {
+		t.Errorf("Now() within the window = %v, want the cached %v", got, start)
+	}
+	if got := clk.NowMonotonic(); got != 0 {
+		t.Errorf("NowMonotonic() within the window = %v, want the cached 0s", got)
+	}
+	fc.Add(time.Microsecond)
+	if got, want := clk.Now(), start.Add(10*time.Microsecond); !got.Equal(want) {
+		t.Errorf("Now() after the window = %v, want %v", got, want)
+	}
+	if got, want := clk.NowMonotonic(), 10*time.Microsecond; got != want {
+		t.Errorf("NowMonotonic() after the window = %v, want %v", got, want)
+	}
+
+	uncached := Cached(fc, 0)
+	fc.Add(time.Microsecond)
+	if got := uncached.Now(); !got.Equal(fc.Now()) {
+		t.Errorf("Cached with no window returned %v, want %v", got, fc.Now())
+	}
+}
+
+func TestCachedSchedulesNothing(t *testing.T) {
+	fc := NewFake(WithLeakCheck(t))
+	clk := Cached(fc, time.Second)
+	for i := 0; i < 3; i++ {
+		clk.Now()
+		clk.NowMonotonic()
+		fc.Add(time.Second)
+	}
+	if n := fc.Waiters(); n != 0 {
+		t.Errorf("got %d waiters on the base clock after reads, want 0", n)
+	}
+}
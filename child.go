@@ -0,0 +1,107 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+func (f *fake) Child(offset time.Duration) Clock {
+	return &child{f: f, tag: timerTag{offset: offset}}
+}
+
+// Labeled returns a Clock that behaves exactly like clk, except that, if
+// clk is a FakeClock or one of its Children, the Timers, Tickers,
+// AfterFunc functions and sleepers made with it carry label. The label
+// is shown by ActiveTimers, AssertNoPending and the watchdog, so that
+// when a test hangs with several things waiting on the clock, it's
+// clear which part of the code under test owns each. Other Clocks are
+// returned as they are.
+func Labeled(clk Clock, label string) Clock {
+	switch c := clk.(type) {
+	case *fake:
+		return &child{f: c, tag: timerTag{label: label}}
+	case *child:
+		l := *c
+		l.tag.label = label
+		return &l
+	}
+	return clk
+}
+
+// child is a Clock offset from a fake and whose timers may be labeled,
+// as returned by the fake's Child method and by Labeled.
+type child struct {
+	f   *fake
+	tag timerTag
+}
+
+func (c *child) Now() time.Time {
+	return c.f.Now().Add(c.tag.offset)
+}
+
+func (c *child) NowUnix() int64 {
+	return c.Now().Unix()
+}
+
+func (c *child) NowUnixMilli() int64 {
+	return c.Now().UnixMilli()
+}
+
+func (c *child) NowUnixNano() int64 {
+	return c.Now().UnixNano()
+}
+
+func (c *child) NowMonotonic() time.Duration {
+	return c.f.NowMonotonic()
+}
+
+func (c *child) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *child) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+func (c *child) Sleep(d time.Duration) {
+	c.f.sleep(d, c.tag)
+}
+
+func (c *child) SleepUntil(t time.Time) {
+	c.f.sleepUntil(t.Add(-c.tag.offset), c.tag)
+}
+
+func (c *child) SleepContext(ctx context.Context, d time.Duration) error {
+	return c.f.sleepContext(ctx, d, c.tag)
+}
+
+func (c *child) After(d time.Duration) <-chan time.Time {
+	return c.f.autoAdvanceTo(c.f.newTimer(TimerWaiter, d, c.tag)).c
+}
+
+func (c *child) AfterAt(t time.Time) <-chan time.Time {
+	return c.f.autoAdvanceTo(c.f.newTimerAt(TimerWaiter, t.Add(-c.tag.offset), c.tag)).c
+}
+
+func (c *child) NewTimer(d time.Duration) Timer {
+	return c.f.newTimer(TimerWaiter, d, c.tag)
+}
+
+func (c *child) NewTimerAt(t time.Time) Timer {
+	return c.f.newTimerAt(TimerWaiter, t.Add(-c.tag.offset), c.tag)
+}
+
+func (c *child) AfterFunc(d time.Duration, fn func()) Timer {
+	return c.f.afterFunc(d, fn, c.tag)
+}
+
+func (c *child) NewTicker(d time.Duration) Ticker {
+	return c.f.newTicker(d, c.tag)
+}
+
+func (c *child) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return c.NewTicker(d).C()
+}
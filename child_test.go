@@ -0,0 +1,94 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChild(t *testing.T) {
+	parent := NewFake()
+	ahead := parent.Child(time.Hour)
+	behind := parent.Child(-time.Minute)
+
+	if got := ahead.Since(parent.Now()); got != time.Hour {
+		t.Errorf("ahead is %v ahead of its parent, want %v", got, time.Hour)
+	}
+	if got := parent.Since(behind.Now()); got != time.Minute {
+		t.Errorf("behind is %v behind its parent, want %v", got, time.Minute)
+	}
+
+	deadline := ahead.Now().Add(10 * time.Second)
+	c := ahead.AfterAt(deadline)
+	tm := behind.NewTimer(10 * time.Second)
+	tk := ahead.NewTicker(5 * time.Second)
+	defer tk.Stop()
+	if got := parent.Waiters(); got != 3 {
+		t.Errorf("children's timers should be waiters on the parent: got %d, want 3", got)
+	}
+
+	parent.Add(10 * time.Second)
+	select {
+	case got := <-c:
+		if !got.Equal(deadline) {
+			t.Errorf("AfterAt sent %v, want %v in the child's time", got, deadline)
+		}
+	default:
+		t.Errorf("AfterAt on a child did not fire when the parent reached its deadline")
+	}
+	select {
+	case got := <-tm.C():
+		if !got.Equal(behind.Now()) {
+			t.Errorf("NewTimer sent %v, want %v in the child's time", got, behind.Now())
+		}
+	default:
+		t.Errorf("NewTimer on a child did not fire")
+	}
+	select {
+	case got := <-tk.C():
+		if want := ahead.Now().Add(-5 * time.Second); !got.Equal(want) {
+			t.Errorf("ticker sent %v, want %v in the child's time", got, want)
+		}
+	default:
+		t.Errorf("ticker on a child did not tick")
+	}
+}
+
+func TestLabeled(t *testing.T) {
+	clk := NewFake()
+	if Labeled(Default(), "x") != Default() {
+		t.Errorf("Labeled should return non-fake Clocks as they are")
+	}
+
+	reconnect := Labeled(clk, "reconnect")
+	tm := reconnect.NewTimer(time.Minute)
+	defer tm.Stop()
+	skewed := Labeled(clk.Child(time.Hour), "skewed")
+	tk := skewed.NewTicker(time.Second)
+	defer tk.Stop()
+	go reconnect.Sleep(time.Hour)
+	clk.BlockUntil(3)
+
+	labels := map[string]int{}
+	for _, info := range clk.ActiveTimers() {
+		labels[info.Label]++
+		if !strings.HasPrefix(info.String(), fmt.Sprintf("%q ", info.Label)) {
+			t.Errorf("TimerInfo.String() = %q, want it to start with its label", info)
+		}
+	}
+	if labels["reconnect"] != 2 || labels["skewed"] != 1 || len(labels) != 2 {
+		t.Errorf("got label counts %v, want 2 reconnect and 1 skewed", labels)
+	}
+
+	clk.Add(time.Second)
+	select {
+	case got := <-tk.C():
+		if want := clk.Now().Add(time.Hour); !got.Equal(want) {
+			t.Errorf("Labeled Child's ticker sent %v, want %v", got, want)
+		}
+	default:
+		t.Errorf("Labeled Child's ticker did not tick")
+	}
+	clk.Add(time.Hour)
+}
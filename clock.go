@@ -12,61 +12,368 @@
 // control how time behaves in your code making them more reliable
 // while also expanding the space of problems you can test.
 //
-// This code intentionally does not attempt to provide an abstraction
-// over time.Ticker and time.Timer because Go does not have the
-// runtime or API hooks available to do reliably. See
+// Timers and tickers are provided through the Timer and Ticker
+// interfaces instead of time.Timer and time.Ticker because Go does
+// not have the runtime or API hooks available to fake those out
+// directly. See
 // https://github.com/golang/go/issues/8869
 //
 // Be sure to test Time equality with time.Time#Equal, not ==.
 package clock
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"testing"
 	"time"
 )
 
 var systemClock Clock = sysClock{}
 
-// Default returns a Clock that matches the actual system time.
+// defaultClock is the Clock set by SetDefaultForTesting, if any.
+var defaultClock atomic.Pointer[Clock]
+
+// monotonicBase is the fixed point that the system Clock's
+// NowMonotonic readings are relative to. Only its monotonic clock
+// reading is ever used.
+var monotonicBase = time.Now()
+
+// Default returns a Clock that matches the actual system time. In
+// programs built with the clockenv build tag, it can be moved and sped
+// up by the environment variables named by EnvNow and EnvRate instead.
+// Inside a testing/synctest bubble, its time is the bubble's; see
+// NewBubble.
 func Default() Clock {
 	// This is a method instead of a public var to prevent folks from
 	// "making things work" by writing to the var instead of passing
 	// in a Clock.
+	if clk := defaultClock.Load(); clk != nil {
+		return *clk
+	}
 	return systemClock
 }
 
+// SetDefaultForTesting makes Default return clk until restore is called,
+// for tests of legacy code that calls Default instead of being passed a
+// Clock. Default can't be changed otherwise for the reasons it's a
+// function rather than a variable, so passing Clocks in is still the
+// way to go, and this is only meant to help migrate code to it. Since
+// Default is shared by the whole program, tests using
+// SetDefaultForTesting must not run in parallel with others that use
+// Default. SetDefaultForTesting panics if not called from a test
+// binary.
+func SetDefaultForTesting(clk Clock) (restore func()) {
+	if !testing.Testing() {
+		panic("clock: SetDefaultForTesting called outside of a test")
+	}
+	prev := defaultClock.Swap(&clk)
+	return func() {
+		defaultClock.Store(prev)
+	}
+}
+
 // Clock is an abstraction over system time. New instances of it can
 // be made with Default and NewFake.
 type Clock interface {
 	// Now returns the Clock's current view of the time. Mutating the
 	// returned Time will not mutate the clock's time.
 	Now() time.Time
-	Sleep(time.Duration)
+
+	// NowUnix returns the Clock's current time as the number of
+	// seconds elapsed since January 1, 1970 UTC.
+	NowUnix() int64
+
+	// NowUnixMilli returns the Clock's current time as the number of
+	// milliseconds elapsed since January 1, 1970 UTC.
+	NowUnixMilli() int64
+
+	// NowUnixNano returns the Clock's current time as the number of
+	// nanoseconds elapsed since January 1, 1970 UTC.
+	NowUnixNano() int64
+
+	// NowMonotonic returns a reading of the Clock's monotonic clock
+	// as the time elapsed since an arbitrary, fixed point. Unlike
+	// Now, it is never affected by changes to the wall clock, so the
+	// difference between two readings is always a true elapsed
+	// duration. Readings are only meaningful compared to other
+	// readings from the same Clock.
+	NowMonotonic() time.Duration
+
+	// Since returns the time elapsed since t according to the Clock.
+	// It is shorthand for clk.Now().Sub(t).
+	Since(t time.Time) time.Duration
+
+	// Until returns the duration until t according to the Clock. It
+	// is shorthand for t.Sub(clk.Now()).
+	Until(t time.Time) time.Duration
+
+	// Sleep pauses the current goroutine for at least the duration
+	// d. A negative or zero duration causes Sleep to return
+	// immediately.
+	Sleep(d time.Duration)
+
+	// SleepUntil pauses the current goroutine until the Clock's time
+	// is at or after t. If t is not in the future, SleepUntil returns
+	// immediately.
+	SleepUntil(t time.Time)
+
+	// SleepContext pauses the current goroutine for at least the
+	// duration d, or until ctx is done, whichever happens first. It
+	// returns ctx.Err() if ctx is done first, and nil otherwise.
+	SleepContext(ctx context.Context, d time.Duration) error
+
+	// After waits for the duration to elapse and then sends the
+	// current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// AfterAt waits until the Clock's time is at or after t and then
+	// sends the current time on the returned channel.
+	AfterAt(t time.Time) <-chan time.Time
+
+	// NewTimer creates a new Timer that will send the current time
+	// on its channel after at least duration d.
+	NewTimer(d time.Duration) Timer
+
+	// NewTimerAt creates a new Timer that will send the current time
+	// on its channel once the Clock's time is at or after t.
+	NewTimerAt(t time.Time) Timer
+
+	// NewTicker returns a new Ticker that sends the current time on
+	// its channel every period d. It panics if d is not positive.
+	NewTicker(d time.Duration) Ticker
+
+	// Tick is a convenience wrapper for NewTicker providing access to
+	// the ticking channel only. It is for callers that never need to
+	// stop the Ticker. Unlike NewTicker, Tick returns nil if d is not
+	// positive.
+	Tick(d time.Duration) <-chan time.Time
+
+	// AfterFunc waits for the duration to elapse and then calls f in
+	// its own goroutine. It returns a Timer that can be used to
+	// cancel the call using its Stop method. The returned Timer's C
+	// method returns nil.
+	AfterFunc(d time.Duration, f func()) Timer
 }
 
+// Timer is an abstraction over time.Timer. Its methods behave like
+// those of time.Timer with the semantics introduced in Go 1.23, but
+// the channel is returned by C instead of being a field.
+type Timer interface {
+	// C returns the channel on which the time is delivered when the
+	// Timer fires.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing. It returns true if the
+	// call stops the timer, false if the timer has already expired
+	// and its time been received, or been stopped. Once Stop returns,
+	// no stale time will be received from C, so callers need not
+	// drain it.
+	Stop() bool
+
+	// Reset changes the timer to expire after duration d. It returns
+	// true if the timer had been active, false if the timer had
+	// expired and its time been received, or been stopped. Once Reset
+	// returns, no time from before the Reset will be received from C.
+	Reset(d time.Duration) bool
+}
+
+// Ticker is an abstraction over time.Ticker. Its methods behave like
+// those of time.Ticker with the semantics introduced in Go 1.23, but
+// the channel is returned by C instead of being a field.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+
+	// Stop turns off the Ticker. No more ticks will be received from
+	// C after Stop returns, including one that was sent before the
+	// call but not yet received. Stop does not close the channel.
+	Stop()
+
+	// Reset stops the Ticker and resets its period to d. The next
+	// tick will arrive after the new period elapses, and no tick
+	// from before the Reset will be received from C. It panics if d
+	// is not positive.
+	Reset(d time.Duration)
+}
+
+// sysClock's timers and tickers are time.Timer and time.Ticker, so they
+// only have the Go 1.23 semantics when the main module's go.mod says
+// go 1.23 or later and the asynctimerchan GODEBUG setting is not used.
 type sysClock struct{}
 
 func (s sysClock) Now() time.Time {
 	return time.Now()
 }
 
+func (s sysClock) NowUnix() int64 {
+	return time.Now().Unix()
+}
+
+func (s sysClock) NowUnixMilli() int64 {
+	return time.Now().UnixMilli()
+}
+
+func (s sysClock) NowUnixNano() int64 {
+	return time.Now().UnixNano()
+}
+
+func (s sysClock) NowMonotonic() time.Duration {
+	return time.Since(monotonicBase)
+}
+
+func (s sysClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (s sysClock) Until(t time.Time) time.Duration {
+	return time.Until(t)
+}
+
 func (s sysClock) Sleep(d time.Duration) {
 	time.Sleep(d)
 }
 
+func (s sysClock) SleepUntil(t time.Time) {
+	time.Sleep(time.Until(t))
+}
+
+func (s sysClock) SleepContext(ctx context.Context, d time.Duration) error {
+	return SleepContextWith(ctx, d, s.NewTimer)
+}
+
+func (s sysClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (s sysClock) AfterAt(t time.Time) <-chan time.Time {
+	return time.After(time.Until(t))
+}
+
+func (s sysClock) NewTimer(d time.Duration) Timer {
+	return sysTimer{time.NewTimer(d)}
+}
+
+func (s sysClock) NewTimerAt(t time.Time) Timer {
+	return sysTimer{time.NewTimer(time.Until(t))}
+}
+
+type sysTimer struct {
+	t *time.Timer
+}
+
+func (s sysTimer) C() <-chan time.Time {
+	return s.t.C
+}
+
+func (s sysTimer) Stop() bool {
+	return s.t.Stop()
+}
+
+func (s sysTimer) Reset(d time.Duration) bool {
+	return s.t.Reset(d)
+}
+
+func (s sysClock) AfterFunc(d time.Duration, f func()) Timer {
+	return sysTimer{time.AfterFunc(d, f)}
+}
+
+func (s sysClock) NewTicker(d time.Duration) Ticker {
+	return sysTicker{time.NewTicker(d)}
+}
+
+func (s sysClock) Tick(d time.Duration) <-chan time.Time {
+	return time.Tick(d)
+}
+
+type sysTicker struct {
+	t *time.Ticker
+}
+
+func (s sysTicker) C() <-chan time.Time {
+	return s.t.C
+}
+
+func (s sysTicker) Stop() {
+	s.t.Stop()
+}
+
+func (s sysTicker) Reset(d time.Duration) {
+	s.t.Reset(d)
+}
+
 // NewFake returns a FakeClock to be used in tests that need to
 // manipulate time. Its initial value is always the unix epoch in the
-// UTC timezone. The FakeClock returned is thread-safe.
-func NewFake() FakeClock {
+// UTC timezone. The FakeClock returned is thread-safe. Options may be
+// given to change how the FakeClock behaves.
+func NewFake(opts ...Option) FakeClock {
 	// We're explicit about this time construction to avoid early user
 	// questions about why the time object doesn't have a Location by
 	// default.
-	return &fake{t: time.Unix(0, 0).UTC()}
+	return NewFakeAt(time.Unix(0, 0).UTC(), opts...)
+}
+
+// NewFakeAt returns a FakeClock like NewFake's, but with t as its
+// initial value, including t's Location.
+func NewFakeAt(t time.Time, opts ...Option) FakeClock {
+	f := &fake{t: t}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.loc != nil {
+		f.t = f.t.In(f.loc)
+	}
+	f.resetLeaps()
+	return f
 }
 
 // FakeClock is a Clock with additional controls. The return value of
 // Now return can be modified with Add. Use NewFake to get a
 // thread-safe FakeClock implementation.
+//
+// A FakeClock's Sleep, SleepUntil and SleepContext block, and its
+// After and Timer channels are sent on, only once the clock's time has
+// been moved forward past their deadline by Add or Set. By default,
+// its Tickers drop ticks while their receiver has yet to take the
+// previous one, just like a time.Ticker whose receiver has fallen
+// behind, so a Ticker with no receiver running ticks once per call to
+// Add or Set, no matter how many periods the call spans. See
+// WithMissedTicks and WithYield to change that.
+//
+// A FakeClock's Timers and Tickers follow the Go 1.23 semantics
+// described on Timer and Ticker: Stop and Reset discard any time that
+// was sent but not yet received. Unlike the Go runtime, a FakeClock
+// keeps its active Timers and Tickers reachable until they are
+// stopped, fire or the FakeClock itself is garbage collected, and their
+// channels have a capacity of one rather than zero.
+//
+// A FakeClock's NowMonotonic starts at zero and is moved forward by
+// Add with a positive duration. It is not changed by Set or by Add with
+// a negative duration, since monotonic time never jumps or goes
+// backwards.
+//
+// When Add or Set moves the clock past the deadlines of several Timers,
+// Tickers and sleepers, they fire one at a time in deadline order, and
+// the clock's time is each one's deadline as it fires. Functions passed
+// to a FakeClock's AfterFunc are each called in a new goroutine, and
+// Add and Set wait for each to return before moving the clock further,
+// so Now called from within one returns its deadline. A function that
+// waits for something the test only does after Add or Set returns will
+// therefore deadlock. See WithSynchronousAfterFunc to call them on the
+// goroutine calling Add or Set instead.
+//
+// Timers created while Add or Set is moving the clock, such as by an
+// AfterFunc function, are fired by that same call if their deadline is
+// before the time it is moving the clock to. A function that
+// reschedules itself with AfterFunc is called as many times as its
+// deadlines fit in the call. That is only guaranteed for AfterFunc
+// functions, since Add and Set do not wait for goroutines receiving
+// from the channels of Timers, Tickers and After.
 type FakeClock interface {
 	Clock
 	// Adjust the time that will be returned by Now.
@@ -74,6 +381,136 @@ type FakeClock interface {
 
 	// Set the Clock's time to exactly the time given.
 	Set(t time.Time)
+
+	// Clone returns a new, independent FakeClock with the same time,
+	// monotonic time and options as this one. Nothing waiting on this
+	// clock is carried over, since the channels and functions of its
+	// Timers and Tickers belong to code that expects them to be
+	// driven by this clock.
+	Clone() FakeClock
+
+	// AssertNoPending reports an error to t, listing what is left,
+	// if any Timers, Tickers, AfterFunc functions or sleepers are
+	// still waiting on the clock. Calling it at the end of a test
+	// catches Timers and Tickers that the code under test never
+	// stopped. See WithLeakCheck to call it automatically.
+	AssertNoPending(t TB)
+
+	// Child returns a Clock whose time is always this clock's time
+	// plus offset, and so moves whenever this clock is moved. Its
+	// Timers, Tickers and sleepers are waiters on this clock, firing
+	// once the Child's time reaches their deadlines, and the times
+	// they send are in the Child's time. Its NowMonotonic is this
+	// clock's. Children let several simulated machines with skewed
+	// clocks share one FakeClock.
+	Child(offset time.Duration) Clock
+
+	// Snapshot captures the clock's time, monotonic time and active
+	// Timers, Tickers and sleepers, with their deadlines and periods,
+	// so that Restore can later roll the clock back to them.
+	Snapshot() Snapshot
+
+	// Restore rolls the clock back to a Snapshot taken from it. Timers,
+	// Tickers and sleepers that were active when the Snapshot was
+	// taken are active again with their deadlines and periods from
+	// then, and all others are stopped. Any time sent on their
+	// channels but not yet received is discarded. Restore is meant to
+	// be called between subtests, not while code is using the clock.
+	// It panics if the Snapshot was taken from another clock.
+	Restore(s Snapshot)
+
+	// NowTAI returns the clock's time in TAI, converted with the table
+	// given to WithLeapSeconds, or with LeapSeconds if there was none,
+	// so that every FakeClock is a TAIClock. During the second
+	// repeated after a leap second, it is the leap second's TAI time.
+	NowTAI() TAI
+
+	// BlockUntil blocks until at least n goroutines are waiting on
+	// the clock. Goroutines count as waiting while they are blocked
+	// in Sleep, SleepUntil or SleepContext, and every active Timer
+	// and Ticker, including those behind After, AfterAt, Tick and
+	// AfterFunc, counts as one waiter. Calling it before Add or Set
+	// ensures the code under test is waiting before time moves.
+	BlockUntil(n int)
+
+	// ActiveTimers describes everything waiting on the clock, as
+	// counted by Waiters, in deadline order.
+	ActiveTimers() []TimerInfo
+
+	// String describes the clock's time and everything waiting on it,
+	// in deadline order, on one line, for logging from a test that is
+	// misbehaving.
+	String() string
+
+	// Dump writes a longer description of the clock than String to w,
+	// including its monotonic time and, if the clock was made with
+	// WithCreationStacks or WithWatchdog, where each thing waiting on
+	// it was created.
+	Dump(w io.Writer)
+
+	// BlockUntilContext is like BlockUntil, but gives up once ctx is
+	// done, returning ctx.Err(). It returns nil once there are at
+	// least n waiters.
+	BlockUntilContext(ctx context.Context, n int) error
+
+	// Waiters returns the number of goroutines and active Timers and
+	// Tickers waiting on the clock, counted the same way as in
+	// BlockUntil.
+	Waiters() int
+
+	// WaiterCounts returns the waiters counted by Waiters broken down
+	// by kind.
+	WaiterCounts() WaiterCounts
+
+	// AdvanceToNextTimer moves the clock forward, as Add does, to the
+	// earliest deadline of its active Timers, Tickers and sleepers,
+	// firing everything due then. It returns the clock's new time and
+	// true, or the current time and false if nothing is waiting on
+	// the clock.
+	AdvanceToNextTimer() (time.Time, bool)
+
+	// RunUntilIdle moves the clock forward, as Add does, from one
+	// deadline to the next, firing everything due at each, until
+	// nothing is waiting on the clock or it has moved forward by max.
+	// If something is still waiting after max, the clock is left at
+	// exactly max past where it started. RunUntilIdle returns how far
+	// the clock moved.
+	RunUntilIdle(max time.Duration) time.Duration
+
+	// AdvanceBy moves the clock forward by total, as a series of calls
+	// to Add of step each, calling between, if it isn't nil, with the
+	// clock's time after each. The last step is shorter if total is
+	// not a multiple of step. AdvanceBy panics if step is not
+	// positive.
+	AdvanceBy(total, step time.Duration, between func(now time.Time))
+
+	// AdvanceUntil moves the clock forward by step, as Add does, until
+	// cond returns true, calling it before the first step and after
+	// each. It returns an error if cond is still false once the clock
+	// has moved forward by max, leaving the clock exactly max past
+	// where it started. Code under test reacting to the clock in other
+	// goroutines may not have run by the time cond is called; see
+	// WithYield. AdvanceUntil panics if step is not positive.
+	AdvanceUntil(cond func() bool, step, max time.Duration) error
+}
+
+// WaiterCounts is a count of a FakeClock's waiters by kind.
+type WaiterCounts struct {
+	// Sleepers is the number of goroutines blocked in Sleep,
+	// SleepUntil and SleepContext.
+	Sleepers int
+
+	// Timers is the number of active Timers, including those behind
+	// After and AfterAt.
+	Timers int
+
+	// Tickers is the number of active Tickers, including those behind
+	// Tick.
+	Tickers int
+
+	// Funcs is the number of functions given to AfterFunc that are
+	// still waiting to be called.
+	Funcs int
 }
 
 // To prevent mistakes with the API, we hide this behind NewFake. It's
@@ -83,26 +520,1060 @@ type FakeClock interface {
 type fake struct {
 	sync.RWMutex
 	t time.Time
+
+	// mono is the monotonic time elapsed on the clock.
+	mono time.Duration
+
+	// timers are the active timers and tickers, including those
+	// backing Sleep and After calls, in no particular order.
+	timers []*fakeTimer
+
+	// blockers are the pending BlockUntil calls.
+	blockers []*blocker
+
+	// seq is the sequence number of the last timer created. Once
+	// Snapshot has been called, created holds every timer made since,
+	// so that Restore can stop those that have fired, too.
+	seq      uint64
+	snapshot bool
+	created  []*fakeTimer
+
+	fakeConfig
+
+	// lastMoved is the real time at which Add or Set was last called.
+	// It is only kept when watchdog is set.
+	lastMoved time.Time
+
+	// firing describes the timers that have fired but that the
+	// WithOnTimerFire hooks have yet to be called for.
+	firing []TimerInfo
+
+	// leapIdx is the index in the WithLeapSeconds table of the next
+	// leap second the clock will step for.
+	leapIdx int
+}
+
+// TB is the part of testing.TB used by FakeClock. A *testing.T,
+// *testing.B or *testing.F can be used as one.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Cleanup(f func())
+}
+
+// Snapshot is the state of a FakeClock captured by its Snapshot method.
+type Snapshot struct {
+	clk    *fake
+	t      time.Time
+	mono   time.Duration
+	seq    uint64
+	timers []timerState
+}
+
+type timerState struct {
+	ft     *fakeTimer
+	until  time.Time
+	period time.Duration
+}
+
+// Time returns the clock time at which the Snapshot was taken.
+func (s Snapshot) Time() time.Time {
+	return s.t
+}
+
+type blocker struct {
+	n    int
+	done chan struct{}
 }
 
 func (f *fake) Now() time.Time {
+	t := f.now()
+	for _, hook := range f.onNow {
+		hook(t)
+	}
+	return t
+}
+
+// now returns the clock's time, moving it along first under
+// WithAutoIncrement.
+func (f *fake) now() time.Time {
+	if f.increment > 0 {
+		f.Lock()
+		defer f.Unlock()
+		t := f.t
+		f.t = f.t.Add(f.increment)
+		f.mono += f.increment
+		return t
+	}
 	f.RLock()
 	defer f.RUnlock()
 	return f.t
 }
 
+func (f *fake) NowUnix() int64 {
+	return f.Now().Unix()
+}
+
+func (f *fake) NowUnixMilli() int64 {
+	return f.Now().UnixMilli()
+}
+
+func (f *fake) NowUnixNano() int64 {
+	return f.Now().UnixNano()
+}
+
+func (f *fake) NowMonotonic() time.Duration {
+	if f.increment > 0 {
+		f.Lock()
+		defer f.Unlock()
+		mono := f.mono
+		f.t = f.t.Add(f.increment)
+		f.mono += f.increment
+		return mono
+	}
+	f.RLock()
+	defer f.RUnlock()
+	return f.mono
+}
+
+func (f *fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *fake) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
+
 func (f *fake) Sleep(d time.Duration) {
-	f.Add(d)
-}	
+	f.sleep(d, timerTag{})
+}
 
-func (f *fake) Add(d time.Duration) {
+func (f *fake) sleep(d time.Duration, tag timerTag) {
+	if d <= 0 {
+		return
+	}
+	ft := f.newTimer(SleepWaiter, d, tag)
+	f.watch(ft, func() string { return fmt.Sprintf("Sleep(%v)", d) })
+	<-f.autoAdvanceTo(ft).c
+}
+
+func (f *fake) SleepUntil(t time.Time) {
+	f.sleepUntil(t, timerTag{})
+}
+
+func (f *fake) sleepUntil(t time.Time, tag timerTag) {
+	ft := f.newTimerAt(SleepWaiter, t, tag)
+	f.watch(ft, func() string { return fmt.Sprintf("SleepUntil(%v)", t.Add(tag.offset)) })
+	<-f.autoAdvanceTo(ft).c
+}
+
+func (f *fake) SleepContext(ctx context.Context, d time.Duration) error {
+	return f.sleepContext(ctx, d, timerTag{})
+}
+
+func (f *fake) sleepContext(ctx context.Context, d time.Duration, tag timerTag) error {
+	return SleepContextWith(ctx, d, func(d time.Duration) Timer {
+		ft := f.newTimer(SleepWaiter, d, tag)
+		f.watch(ft, func() string { return fmt.Sprintf("SleepContext(%v)", d) })
+		return f.autoAdvanceTo(ft)
+	})
+}
+
+func (f *fake) After(d time.Duration) <-chan time.Time {
+	return f.autoAdvanceTo(f.newTimer(TimerWaiter, d, timerTag{})).c
+}
+
+func (f *fake) NewTimer(d time.Duration) Timer {
+	return f.newTimer(TimerWaiter, d, timerTag{})
+}
+
+func (f *fake) AfterAt(t time.Time) <-chan time.Time {
+	return f.autoAdvanceTo(f.newTimerAt(TimerWaiter, t, timerTag{})).c
+}
+
+func (f *fake) NewTimerAt(t time.Time) Timer {
+	return f.newTimerAt(TimerWaiter, t, timerTag{})
+}
+
+// timerTag is what a Child or Labeled clock adds to the timers it
+// creates on its fake.
+type timerTag struct {
+	// offset is added to the times sent on the timers' channels.
+	offset time.Duration
+
+	// label is shown when the timers are described.
+	label string
+}
+
+// newTimer returns a new active timer of the given kind with a deadline
+// d from now.
+func (f *fake) newTimer(kind WaiterKind, d time.Duration, tag timerTag) *fakeTimer {
+	f.Lock()
+	defer f.unlock()
+	// Buffered so that Add and Set never block on a receiver that
+	// has gone away, just like time.Timer.
+	ft := &fakeTimer{clk: f, kind: kind, c: make(chan time.Time, 1), tag: tag}
+	f.schedule(ft, d)
+	return ft
+}
+
+// newTimerAt returns a new active timer of the given kind with the
+// deadline t.
+func (f *fake) newTimerAt(kind WaiterKind, t time.Time, tag timerTag) *fakeTimer {
+	f.Lock()
+	defer f.unlock()
+	ft := &fakeTimer{clk: f, kind: kind, c: make(chan time.Time, 1), tag: tag}
+	f.scheduleAt(ft, t)
+	return ft
+}
+
+// watch starts the watchdog for the sleeper ft, described by the result
+// of describe, if the clock was made with WithWatchdog and ft is still
+// active. describe is only called when there is a watchdog, so that
+// sleeping on other clocks doesn't pay for describing the sleeper.
+func (f *fake) watch(ft *fakeTimer, describe func() string) {
+	if f.watchdog <= 0 {
+		return
+	}
+	what := describe()
+	buf := make([]byte, 8<<10)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	f.Lock()
+	defer f.Unlock()
+	if !f.active(ft) {
+		return
+	}
+	if ft.tag.label != "" {
+		what += fmt.Sprintf(" labeled %q", ft.tag.label)
+	}
+	ft.what = what
+	ft.stack = string(buf)
+	ft.watchedSince = time.Now()
+	ft.dog = time.AfterFunc(f.watchdog, func() { f.checkWatchdog(ft) })
+}
+
+// checkWatchdog reports the blocked sleepers if the sleeper ft has been
+// blocked for the watchdog's duration with no call to Add or Set, and
+// otherwise checks again once it could have been.
+func (f *fake) checkWatchdog(ft *fakeTimer) {
+	f.Lock()
+	if ft.dog == nil {
+		f.Unlock()
+		return
+	}
+	since := ft.watchedSince
+	if f.lastMoved.After(since) {
+		since = f.lastMoved
+	}
+	if idle := time.Since(since); idle < f.watchdog {
+		ft.dog = time.AfterFunc(f.watchdog-idle, func() { f.checkWatchdog(ft) })
+		f.Unlock()
+		return
+	}
+	ft.dog = nil
+
+	var sleepers []*fakeTimer
+	for _, other := range f.timers {
+		if other.what != "" {
+			sleepers = append(sleepers, other)
+		}
+	}
+	sort.Slice(sleepers, func(i, j int) bool {
+		return sleepers[i].watchedSince.Before(sleepers[j].watchedSince)
+	})
+	var b strings.Builder
+	fmt.Fprintf(&b, "clock: goroutine blocked in %s on a FakeClock at %v, with no call to Add or Set in %v of real time\n", ft.what, f.t, f.watchdog)
+	for _, other := range sleepers {
+		fmt.Fprintf(&b, "\n%s, until %v:\n%s", other.what, other.until, other.stack)
+	}
+	msg := b.String()
+	report := f.watchdogReport
+	f.Unlock()
+
+	if report == nil {
+		panic(msg)
+	}
+	report(msg)
+}
+
+// active reports whether ft is an active timer. It must be called with
+// f's lock held.
+func (f *fake) active(ft *fakeTimer) bool {
+	for _, t := range f.timers {
+		if t == ft {
+			return true
+		}
+	}
+	return false
+}
+
+// autoAdvanceTo moves the clock forward to ft's deadline if the clock
+// was made with WithAutoAdvance. It returns ft.
+func (f *fake) autoAdvanceTo(ft *fakeTimer) *fakeTimer {
+	if !f.autoAdvance {
+		return ft
+	}
 	f.Lock()
 	defer f.Unlock()
-	f.t = f.t.Add(d)
+	f.advance(ft.until, true)
+	return ft
+}
+
+func (f *fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.afterFunc(d, fn, timerTag{})
+}
+
+func (f *fake) afterFunc(d time.Duration, fn func(), tag timerTag) Timer {
+	f.Lock()
+	ft := &fakeTimer{clk: f, kind: FuncWaiter, fn: fn, tag: tag}
+	defer f.unlock()
+	f.schedule(ft, d)
+	return ft
+}
+
+func (f *fake) NewTicker(d time.Duration) Ticker {
+	return f.newTicker(d, timerTag{})
+}
+
+// newTicker returns a new ticker with period d.
+func (f *fake) newTicker(d time.Duration, tag timerTag) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	f.Lock()
+	defer f.Unlock()
+	ft := &fakeTimer{clk: f, kind: TickerWaiter, c: make(chan time.Time, 1), period: d, tag: tag}
+	f.schedule(ft, d)
+	return fakeTicker{ft}
+}
+
+func (f *fake) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return f.NewTicker(d).C()
+}
+
+func (f *fake) Add(d time.Duration) {
+	f.Lock()
+	defer f.unlockAdvanced(f.t)
+	f.moved()
+	if d <= 0 {
+		f.rewind("Add", f.t.Add(d))
+		return
+	}
+	f.advance(f.t.Add(d), true)
 }
 
 func (f *fake) Set(t time.Time) {
+	f.Lock()
+	defer f.unlockAdvanced(f.t)
+	f.moved()
+	if !t.After(f.t) {
+		f.rewind("Set", t)
+		return
+	}
+	f.advance(t, false)
+}
+
+func (f *fake) Clone() FakeClock {
+	f.RLock()
+	defer f.RUnlock()
+	return &fake{t: f.t, mono: f.mono, fakeConfig: f.fakeConfig, leapIdx: f.leapIdx}
+}
+
+func (f *fake) AssertNoPending(t TB) {
+	t.Helper()
+	_, _, pending := f.state()
+	if len(pending) == 0 {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "clock: %d left waiting on the FakeClock:", len(pending))
+	for _, info := range pending {
+		fmt.Fprintf(&b, "\n\t%s", info)
+	}
+	t.Errorf("%s", b.String())
+}
+
+func (f *fake) Snapshot() Snapshot {
+	// Snapshot writes f.snapshot, so it needs the write lock.
+	f.Lock()
+	defer f.Unlock()
+	s := Snapshot{clk: f, t: f.t, mono: f.mono, seq: f.seq}
+	f.snapshot = true
+	for _, ft := range f.timers {
+		s.timers = append(s.timers, timerState{ft: ft, until: ft.until, period: ft.period})
+	}
+	return s
+}
+
+func (f *fake) Restore(s Snapshot) {
+	if s.clk != f {
+		panic("clock: Restore called with a Snapshot from another FakeClock")
+	}
+	f.Lock()
+	halt := append([]*fakeTimer(nil), f.timers...)
+	kept := f.created[:0]
+	for _, ft := range f.created {
+		if ft.seq > s.seq {
+			halt = append(halt, ft)
+		} else {
+			kept = append(kept, ft)
+		}
+	}
+	for i := len(kept); i < len(f.created); i++ {
+		f.created[i] = nil
+	}
+	f.created = kept
+	f.Unlock()
+	for _, ts := range s.timers {
+		halt = append(halt, ts.ft)
+	}
+	for _, ft := range halt {
+		ft.halt()
+	}
+
 	f.Lock()
 	defer f.Unlock()
+	f.t = s.t
+	f.mono = s.mono
+	f.resetLeaps()
+	for _, ts := range s.timers {
+		ts.ft.until = ts.until
+		ts.ft.period = ts.period
+		f.timers = append(f.timers, ts.ft)
+	}
+	f.unblock()
+}
+
+func (f *fake) AdvanceToNextTimer() (time.Time, bool) {
+	f.Lock()
+	defer f.unlockAdvanced(f.t)
+	ft := f.next()
+	if ft == nil {
+		return f.t, false
+	}
+	f.advance(ft.until, true)
+	return f.t, true
+}
+
+func (f *fake) RunUntilIdle(max time.Duration) time.Duration {
+	f.Lock()
+	defer f.unlockAdvanced(f.t)
+	start := f.t
+	limit := f.t.Add(max)
+	for {
+		ft := f.next()
+		if ft == nil {
+			break
+		}
+		if ft.until.After(limit) {
+			f.advance(limit, true)
+			break
+		}
+		f.advance(ft.until, true)
+	}
+	return f.t.Sub(start)
+}
+
+func (f *fake) AdvanceBy(total, step time.Duration, between func(now time.Time)) {
+	if step <= 0 {
+		panic("clock: non-positive step for AdvanceBy")
+	}
+	for total > 0 {
+		d := min(step, total)
+		f.Add(d)
+		total -= d
+		if between != nil {
+			between(f.now())
+		}
+	}
+}
+
+func (f *fake) AdvanceUntil(cond func() bool, step, max time.Duration) error {
+	if step <= 0 {
+		panic("clock: non-positive step for AdvanceUntil")
+	}
+	for moved := time.Duration(0); !cond(); {
+		if moved >= max {
+			return fmt.Errorf("clock: condition still false after advancing the FakeClock by %v", max)
+		}
+		d := min(step, max-moved)
+		f.Add(d)
+		moved += d
+	}
+	return nil
+}
+
+func (f *fake) BlockUntil(n int) {
+	f.BlockUntilContext(context.Background(), n)
+}
+
+func (f *fake) BlockUntilContext(ctx context.Context, n int) error {
+	f.Lock()
+	if len(f.timers) >= n {
+		f.Unlock()
+		return nil
+	}
+	b := &blocker{n: n, done: make(chan struct{})}
+	f.blockers = append(f.blockers, b)
+	f.Unlock()
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	for i, other := range f.blockers {
+		if other == b {
+			copy(f.blockers[i:], f.blockers[i+1:])
+			f.blockers[len(f.blockers)-1] = nil
+			f.blockers = f.blockers[:len(f.blockers)-1]
+			return ctx.Err()
+		}
+	}
+	// unblock released b after ctx was done but before we got the
+	// lock, so the waiters did show up.
+	return nil
+}
+
+func (f *fake) Waiters() int {
+	f.RLock()
+	defer f.RUnlock()
+	return len(f.timers)
+}
+
+func (f *fake) WaiterCounts() WaiterCounts {
+	f.RLock()
+	defer f.RUnlock()
+	var wc WaiterCounts
+	for _, ft := range f.timers {
+		switch ft.kind {
+		case SleepWaiter:
+			wc.Sleepers++
+		case TimerWaiter:
+			wc.Timers++
+		case TickerWaiter:
+			wc.Tickers++
+		case FuncWaiter:
+			wc.Funcs++
+		}
+	}
+	return wc
+}
+
+// unblock releases every BlockUntil call whose count of waiters has
+// been reached. It must be called with f's lock held.
+func (f *fake) unblock() {
+	remaining := f.blockers[:0]
+	for _, b := range f.blockers {
+		if len(f.timers) < b.n {
+			remaining = append(remaining, b)
+			continue
+		}
+		close(b.done)
+	}
+	for i := len(remaining); i < len(f.blockers); i++ {
+		f.blockers[i] = nil
+	}
+	f.blockers = remaining
+}
+
+// rewind sets the clock's time to t, which is not after its current
+// time, on behalf of the method op. It panics if that breaks the
+// WithRewindGuard option. It must be called with f's lock held.
+func (f *fake) rewind(op string, t time.Time) {
+	if f.rewindGuard && t.Before(f.t) && len(f.timers) > 0 {
+		panic(fmt.Sprintf("clock: %s would move the FakeClock back from %v to %v with %d waiters", op, f.t, t, len(f.timers)))
+	}
+	f.t = t
+	f.resetLeaps()
+}
+
+// moved records that the clock is being moved, for the watchdog. It
+// must be called with f's lock held.
+func (f *fake) moved() {
+	if f.watchdog > 0 {
+		f.lastMoved = time.Now()
+	}
+}
+
+// advance moves the clock forward to target, stopping at the deadline
+// of each timer on the way, in order, to fire it. The clock's time is
+// the timer's deadline while it fires. If monotonic is set, the
+// clock's monotonic time moves along with it. advance must be called
+// with f's lock held, but releases it while AfterFunc functions run
+// and while yielding for WithYield.
+func (f *fake) advance(target time.Time, monotonic bool) {
+	for {
+		ft := f.next()
+		if at, step, ok := f.nextLeap(target); ok && (ft == nil || !ft.until.Before(at)) {
+			f.moveTo(at, monotonic)
+			f.leap(step)
+			if monotonic {
+				target = target.Add(-step)
+			}
+			continue
+		}
+		if ft == nil || ft.until.After(target) {
+			break
+		}
+		f.moveTo(ft.until, monotonic)
+		f.fire(ft, target)
+		f.notify()
+		if f.yield {
+			f.Unlock()
+			if f.yieldSleep > 0 {
+				time.Sleep(f.yieldSleep)
+			} else {
+				runtime.Gosched()
+			}
+			f.Lock()
+		}
+	}
+	f.moveTo(target, monotonic)
+}
+
+// fired records that ft is firing, for the WithOnTimerFire hooks. It
+// must be called with f's lock held, before ft changes.
+func (f *fake) fired(ft *fakeTimer) {
+	if len(f.onTimerFire) > 0 {
+		info := ft.info()
+		info.Deadline = f.t
+		f.firing = append(f.firing, info)
+	}
+}
+
+// notify calls the WithOnTimerFire hooks for the timers that have fired
+// since it was last called. It must be called with f's lock held, but
+// releases it while the hooks run.
+func (f *fake) notify() {
+	for len(f.firing) > 0 {
+		firing := f.firing
+		f.firing = nil
+		f.Unlock()
+		for _, info := range firing {
+			for _, hook := range f.onTimerFire {
+				hook(info)
+			}
+		}
+		f.Lock()
+	}
+}
+
+// unlock releases f's lock, first calling the WithOnTimerFire hooks for
+// any timers that fired while it was held.
+func (f *fake) unlock() {
+	f.notify()
+	f.Unlock()
+}
+
+// unlockAdvanced is like unlock, but then also calls the WithOnAdvance
+// hooks for the clock having moved from from to its current time.
+func (f *fake) unlockAdvanced(from time.Time) {
+	to := f.t
+	f.unlock()
+	for _, hook := range f.onAdvance {
+		hook(from, to)
+	}
+}
+
+// moveTo sets the clock's time to t if that moves it forward. It must
+// be called with f's lock held.
+func (f *fake) moveTo(t time.Time, monotonic bool) {
+	if !t.After(f.t) {
+		return
+	}
+	if monotonic {
+		f.mono += t.Sub(f.t)
+	}
 	f.t = t
 }
+
+// resetLeaps points leapIdx at the first leap second in the
+// WithLeapSeconds table the clock has yet to step for. It must be
+// called with f's lock held, whenever the clock is set to a time other
+// than by moving it forward.
+func (f *fake) resetLeaps() {
+	if f.leaps == nil {
+		return
+	}
+	f.leapIdx = 1
+	for f.leapIdx < len(f.leaps.leaps) {
+		if at, _ := f.leapAt(f.leapIdx); at.After(f.t) {
+			break
+		}
+		f.leapIdx++
+	}
+}
+
+// leapAt returns when the clock steps for the i'th entry of the
+// WithLeapSeconds table, and by how much its time goes back then: the
+// start of the entry's day for a positive leap second, which repeats
+// the second before it, and a second earlier for a negative one, which
+// skips that second.
+func (f *fake) leapAt(i int) (at time.Time, step time.Duration) {
+	l := f.leaps.leaps[i]
+	step = time.Duration(l.TAIOffset-f.leaps.leaps[i-1].TAIOffset) * time.Second
+	if step < 0 {
+		return l.Time.Add(step), step
+	}
+	return l.Time, step
+}
+
+// nextLeap returns when the clock next steps for a leap second and by
+// how much, if that is no later than target. It must be called with
+// f's lock held.
+func (f *fake) nextLeap(target time.Time) (at time.Time, step time.Duration, ok bool) {
+	if f.leaps == nil || f.leapIdx >= len(f.leaps.leaps) {
+		return time.Time{}, 0, false
+	}
+	at, step = f.leapAt(f.leapIdx)
+	if at.After(target) {
+		return time.Time{}, 0, false
+	}
+	return at, step, true
+}
+
+// leap steps the clock's time back by step for a leap second, without
+// changing its monotonic time, and moves the deadlines of everything
+// waiting on it back too, so that they still fire after waiting as long
+// as they asked to. It must be called with f's lock held.
+func (f *fake) leap(step time.Duration) {
+	f.t = f.t.Add(-step)
+	for _, ft := range f.timers {
+		ft.until = ft.until.Add(-step)
+	}
+	f.leapIdx++
+}
+
+func (f *fake) NowTAI() TAI {
+	t := f.Now()
+	if f.leaps == nil {
+		return LeapSeconds.ToTAI(t)
+	}
+	a := f.leaps.ToTAI(t)
+	f.RLock()
+	i := f.leapIdx - 1
+	f.RUnlock()
+	if i < 1 {
+		return a
+	}
+	// The repeated second reads the same as the one before the leap
+	// second, but comes a second later.
+	if at, step := f.leapAt(i); step > 0 && !t.Before(at.Add(-step)) && t.Before(at) {
+		a = a.Add(step)
+	}
+	return a
+}
+
+// next returns the active timer with the earliest deadline, or nil if
+// there are none. It must be called with f's lock held.
+func (f *fake) next() *fakeTimer {
+	var earliest *fakeTimer
+	for _, ft := range f.timers {
+		if earliest == nil || ft.until.Before(earliest.until) {
+			earliest = ft
+		}
+	}
+	return earliest
+}
+
+// fire fires ft, which has reached its deadline during an advance to
+// target. Timers are made inactive. Tickers tick and are rescheduled
+// for their next period boundary, unless their receiver has yet to take
+// the previous tick, in which case the ticks up to target are dropped,
+// queued under DeliverMissedTicks, or replaced by the last one under
+// ReplaceMissedTicks. fire must be called
+// with f's lock held, but releases it while an AfterFunc function
+// runs, waiting for the function to return.
+func (f *fake) fire(ft *fakeTimer, target time.Time) {
+	f.fired(ft)
+	if ft.period == 0 {
+		f.unschedule(ft)
+		if ft.fn == nil {
+			ft.send(f.t)
+			return
+		}
+		if f.syncFuncs {
+			f.Unlock()
+			defer f.Lock()
+			ft.fn()
+			return
+		}
+		done := make(chan struct{})
+		f.Unlock()
+		go func() {
+			defer close(done)
+			ft.fn()
+		}()
+		<-done
+		f.Lock()
+		return
+	}
+	missed := target.Sub(ft.until) / ft.period
+	switch {
+	case f.missedTicks == DeliverMissedTicks:
+		ft.queue(ft.until, int64(missed)+1)
+	case f.missedTicks == ReplaceMissedTicks && missed == 0:
+		ft.drain()
+		ft.send(f.t)
+	case ft.send(f.t):
+		// The receiver may take the tick before the next period
+		// boundary, so stop there in case it does.
+		ft.until = ft.until.Add(ft.period)
+		return
+	case f.missedTicks == ReplaceMissedTicks:
+		// Skip to the last boundary, whose tick will replace the
+		// one still waiting to be received.
+		ft.until = ft.until.Add(missed * ft.period)
+		return
+	}
+	ft.until = ft.until.Add((missed + 1) * ft.period)
+}
+
+// schedule makes ft active with a deadline d from now, firing it
+// immediately if d is not positive. It must be called with f's lock
+// held.
+func (f *fake) schedule(ft *fakeTimer, d time.Duration) {
+	f.scheduleAt(ft, f.t.Add(d))
+}
+
+// scheduleAt makes ft active with the deadline t, or fires it
+// immediately if t is not after the current time. Functions given to
+// AfterFunc are not waited on when fired this way. It must be called
+// with f's lock held.
+func (f *fake) scheduleAt(ft *fakeTimer, t time.Time) {
+	if ft.seq == 0 {
+		if f.stacks || f.watchdog > 0 {
+			ft.created = callers()
+		}
+		f.seq++
+		ft.seq = f.seq
+		if f.snapshot {
+			f.created = append(f.created, ft)
+		}
+	}
+	ft.until = t
+	if !t.After(f.t) && ft.period == 0 {
+		f.fired(ft)
+		if ft.fn != nil {
+			go ft.fn()
+		} else {
+			ft.send(f.t)
+		}
+		return
+	}
+	f.timers = append(f.timers, ft)
+	f.unblock()
+}
+
+// unschedule removes ft from the active timers, reporting whether it
+// was active. It must be called with f's lock held.
+func (f *fake) unschedule(ft *fakeTimer) bool {
+	if ft.dog != nil {
+		ft.dog.Stop()
+		ft.dog = nil
+	}
+	for i, t := range f.timers {
+		if t == ft {
+			copy(f.timers[i:], f.timers[i+1:])
+			f.timers[len(f.timers)-1] = nil
+			f.timers = f.timers[:len(f.timers)-1]
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clk  *fake
+	seq  uint64
+	kind WaiterKind
+
+	// created is the stack of the call that created the timer.
+	created []uintptr
+
+	until time.Time
+	c     chan time.Time
+
+	// period is how often a ticker ticks. It is zero for timers.
+	period time.Duration
+
+	// tag is set for the timers of Child and Labeled clocks.
+	tag timerTag
+
+	// fn is the function given to AfterFunc. When it is set, c is
+	// nil.
+	fn func()
+
+	// backlogNext and backlogN describe the ticks queued for a ticker
+	// under DeliverMissedTicks: backlogN ticks starting at
+	// backlogNext, one period apart. They are sent by a pump goroutine,
+	// which can be told to stop by closing pumpStop and which closes
+	// pumpDone when it exits. pumpStop is nil when no pump is running.
+	backlogNext time.Time
+	backlogN    int64
+	pumpStop    chan struct{}
+	pumpDone    chan struct{}
+
+	// what, stack and watchedSince describe a sleeper being watched
+	// by the clock's watchdog, and dog is the real timer that checks
+	// on it. dog is nil when it is not being watched.
+	what         string
+	stack        string
+	watchedSince time.Time
+	dog          *time.Timer
+}
+
+// queue adds n ticks, the first at the time next, to the ticker's
+// backlog. It sends the first queued tick immediately if the channel
+// has room, and starts a pump goroutine to send the rest. It must be
+// called with the clock's lock held.
+func (ft *fakeTimer) queue(next time.Time, n int64) {
+	if ft.backlogN == 0 {
+		ft.backlogNext = next
+	}
+	ft.backlogN += n
+	if ft.pumpStop == nil {
+		select {
+		case ft.c <- ft.backlogNext.Add(ft.tag.offset):
+			ft.popBacklog()
+		default:
+		}
+	}
+	if ft.backlogN > 0 && ft.pumpStop == nil {
+		ft.pumpStop = make(chan struct{})
+		ft.pumpDone = make(chan struct{})
+		go ft.pump(ft.pumpStop, ft.pumpDone)
+	}
+}
+
+// popBacklog removes the first tick from the backlog. It must be called
+// with the clock's lock held.
+func (ft *fakeTimer) popBacklog() {
+	if ft.backlogN == 0 {
+		return
+	}
+	ft.backlogNext = ft.backlogNext.Add(ft.period)
+	ft.backlogN--
+}
+
+// pump sends the ticker's backlog on its channel until the backlog is
+// empty or stop is closed.
+func (ft *fakeTimer) pump(stop, done chan struct{}) {
+	defer close(done)
+	for {
+		ft.clk.Lock()
+		if ft.backlogN == 0 {
+			if ft.pumpStop == stop {
+				ft.pumpStop = nil
+			}
+			ft.clk.Unlock()
+			return
+		}
+		next := ft.backlogNext
+		ft.clk.Unlock()
+
+		select {
+		case ft.c <- next.Add(ft.tag.offset):
+			ft.clk.Lock()
+			ft.popBacklog()
+			ft.clk.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// halt deactivates the timer, discarding any backlog and any time that
+// was sent but not yet received, and reports whether it was active.
+// It must be called without the clock's lock held, since it waits for
+// a pump goroutine to exit.
+func (ft *fakeTimer) halt() bool {
+	ft.clk.Lock()
+	active := ft.clk.unschedule(ft)
+	stop, done := ft.pumpStop, ft.pumpDone
+	ft.pumpStop = nil
+	ft.backlogN = 0
+	ft.clk.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	ft.clk.Lock()
+	defer ft.clk.Unlock()
+	return ft.drain() || active
+}
+
+// send delivers now, plus the timer's offset, on the timer's channel,
+// dropping it if a previous value has not yet been received, as
+// time.Timer does. It reports whether it was delivered.
+func (ft *fakeTimer) send(now time.Time) bool {
+	select {
+	case ft.c <- now.Add(ft.tag.offset):
+		return true
+	default:
+		return false
+	}
+}
+
+// drain discards a time sent on the timer's channel that has not been
+// received, reporting whether there was one. With Go 1.23's
+// synchronous timer channels, such a time was never really sent, so
+// the timer counts as still active. It must be called with the
+// clock's lock held.
+func (ft *fakeTimer) drain() bool {
+	select {
+	case <-ft.c:
+		return true
+	default:
+		return false
+	}
+}
+
+func (ft *fakeTimer) C() <-chan time.Time {
+	return ft.c
+}
+
+func (ft *fakeTimer) Stop() bool {
+	return ft.halt()
+}
+
+func (ft *fakeTimer) Reset(d time.Duration) bool {
+	active := ft.halt()
+	ft.clk.Lock()
+	defer ft.clk.unlock()
+	ft.clk.schedule(ft, d)
+	return active
+}
+
+type fakeTicker struct {
+	ft *fakeTimer
+}
+
+func (t fakeTicker) C() <-chan time.Time {
+	return t.ft.c
+}
+
+func (t fakeTicker) Stop() {
+	t.ft.Stop()
+}
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	t.ft.halt()
+	t.ft.clk.Lock()
+	defer t.ft.clk.Unlock()
+	t.ft.period = d
+	t.ft.clk.schedule(t.ft, d)
+}
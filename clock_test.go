@@ -1,7 +1,11 @@
 package clock
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -24,9 +28,1127 @@ func TestFakeClockGoldenPath(t *testing.T) {
 		t.Errorf("clk should have been been set backwards: %#v vs %#v", clk.Now(), second.Now())
 	}
 
-	clk.Sleep(time.Second)
+	clk.Add(time.Second)
 	if clk.Now().Equal(second.Now()) {
-		t.Errorf("clk should have been set forwards (by sleeping): %#v vs %#v", clk.Now(), second.Now())
+		t.Errorf("clk should have been set forwards: %#v vs %#v", clk.Now(), second.Now())
+	}
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	clk := NewFake()
+	clk.BlockUntil(0)
+
+	done := make(chan struct{})
+	go func() {
+		clk.Sleep(time.Second)
+		clk.Sleep(time.Second)
+		close(done)
+	}()
+
+	clk.BlockUntil(1)
+	clk.Add(time.Second)
+	clk.BlockUntil(1)
+	clk.Add(time.Second)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("sleeper did not finish after BlockUntil and Add")
+	}
+
+	tm := clk.NewTimer(time.Second)
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+	clk.BlockUntil(2)
+	tm.Stop()
+
+	blocked := make(chan struct{})
+	go func() {
+		clk.BlockUntil(2)
+		close(blocked)
+	}()
+	select {
+	case <-blocked:
+		t.Fatalf("BlockUntil(2) returned with only one waiter")
+	case <-time.After(10 * time.Millisecond):
+	}
+	clk.After(time.Second)
+	select {
+	case <-blocked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("BlockUntil(2) did not return once there were two waiters")
+	}
+}
+
+func TestFakeClockBlockUntilContext(t *testing.T) {
+	clk := NewFake()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := clk.BlockUntilContext(ctx, 1); err != context.DeadlineExceeded {
+		t.Errorf("BlockUntilContext with no waiters: got %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	clk.After(time.Second)
+	if err := clk.BlockUntilContext(context.Background(), 1); err != nil {
+		t.Errorf("BlockUntilContext with a waiter: got %v, want nil", err)
+	}
+}
+
+func TestFakeClockWaiters(t *testing.T) {
+	clk := NewFake()
+	go clk.Sleep(time.Second)
+	clk.After(time.Second)
+	clk.NewTimer(time.Minute)
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+	clk.AfterFunc(time.Second, func() {})
+	clk.BlockUntil(5)
+
+	if got := clk.Waiters(); got != 5 {
+		t.Errorf("Waiters: got %d, want 5", got)
+	}
+	want := WaiterCounts{Sleepers: 1, Timers: 2, Tickers: 1, Funcs: 1}
+	if got := clk.WaiterCounts(); got != want {
+		t.Errorf("WaiterCounts: got %+v, want %+v", got, want)
+	}
+
+	clk.Add(time.Second)
+	want = WaiterCounts{Timers: 1, Tickers: 1}
+	if got := clk.WaiterCounts(); got != want {
+		t.Errorf("WaiterCounts after Add: got %+v, want %+v", got, want)
+	}
+}
+
+func TestNewFakeAt(t *testing.T) {
+	start := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.FixedZone("X", 3600))
+	clk := NewFakeAt(start, WithAutoIncrement(time.Second))
+	if got := clk.Now(); got != start {
+		t.Errorf("Now: got %v, want %v", got, start)
+	}
+	if got := clk.Now(); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("options were not applied: got %v, want %v", got, start.Add(time.Second))
+	}
+}
+
+func TestNewFakeOptions(t *testing.T) {
+	loc := time.FixedZone("X", -7200)
+	start := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+	clk := NewFake(WithLocation(loc), WithStart(start), WithStrictMonotonic())
+	now := clk.Now()
+	if !now.Equal(start) || now.Location() != loc {
+		t.Errorf("Now: got %v, want %v in %v", now, start, loc)
+	}
+	if clk.Now().Equal(now) {
+		t.Errorf("WithStrictMonotonic readings should never repeat")
+	}
+}
+
+func TestFakeClockClone(t *testing.T) {
+	clk := NewFake(WithAutoIncrement(time.Second))
+	clk.Add(time.Hour)
+	clk.After(time.Minute)
+
+	clone := clk.Clone()
+	if got := clone.Waiters(); got != 0 {
+		t.Errorf("clone has %d waiters, want 0", got)
+	}
+	if got := clone.NowMonotonic(); got != time.Hour {
+		t.Errorf("clone NowMonotonic: got %v, want %v", got, time.Hour)
+	}
+	if got := clone.Since(time.Unix(0, 0)); got != time.Hour+time.Second {
+		t.Errorf("clone's options were not copied: Since got %v, want %v", got, time.Hour+time.Second)
+	}
+
+	clone.Add(time.Hour)
+	if got := clk.Waiters(); got != 1 {
+		t.Errorf("moving the clone fired the original's timer")
+	}
+	if clk.Now().Equal(clone.Now()) {
+		t.Errorf("clone should move independently")
+	}
+}
+
+func TestFakeClockSnapshotRestore(t *testing.T) {
+	clk := NewFake()
+	tk := clk.NewTicker(time.Minute)
+	defer tk.Stop()
+	clk.Add(30 * time.Second)
+	shared := clk.NewTimer(time.Minute)
+	snap := clk.Snapshot()
+
+	for i := 0; i < 2; i++ {
+		// Each branch runs from the same point, whatever the last one
+		// did.
+		later := clk.NewTimer(time.Second)
+		clk.Add(5 * time.Minute)
+		if !shared.Stop() {
+			t.Errorf("branch %d: shared timer did not fire", i)
+		}
+		clk.Restore(snap)
+
+		if !clk.Now().Equal(snap.Time()) {
+			t.Errorf("branch %d: Restore set the time to %v, want %v", i, clk.Now(), snap.Time())
+		}
+		if got := clk.NowMonotonic(); got != 30*time.Second {
+			t.Errorf("branch %d: NowMonotonic after Restore: got %v, want %v", i, got, 30*time.Second)
+		}
+		if later.Stop() {
+			t.Errorf("branch %d: timer made after the Snapshot is still active", i)
+		}
+		select {
+		case <-tk.C():
+			t.Errorf("branch %d: a tick from before Restore was not discarded", i)
+		default:
+		}
+		if got := clk.Waiters(); got != 2 {
+			t.Errorf("branch %d: got %d waiters after Restore, want 2", i, got)
+		}
+	}
+
+	clk.Add(30 * time.Second)
+	select {
+	case <-tk.C():
+	default:
+		t.Errorf("ticker did not tick on its restored period")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Restore with another clock's Snapshot did not panic")
+		}
+	}()
+	NewFake().Restore(snap)
+}
+
+func TestFakeClockSnapshotConcurrent(t *testing.T) {
+	clk := NewFake()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				clk.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// fakeTB is a TB that records what is reported to it.
+type fakeTB struct {
+	errors   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func TestFakeClockAssertNoPending(t *testing.T) {
+	tb := &fakeTB{}
+	clk := NewFake(WithLeakCheck(tb))
+	clk.AssertNoPending(tb)
+	if len(tb.errors) != 0 {
+		t.Fatalf("AssertNoPending with nothing waiting reported %q", tb.errors)
+	}
+
+	clk.NewTicker(time.Second)
+	tm := clk.NewTimer(time.Minute)
+	clk.AfterFunc(time.Hour, func() {})
+	tm.Stop()
+	for _, f := range tb.cleanups {
+		f()
+	}
+	if len(tb.errors) != 1 {
+		t.Fatalf("WithLeakCheck's cleanup reported %d errors, want 1", len(tb.errors))
+	}
+	msg := tb.errors[0]
+	if !strings.Contains(msg, "Ticker every 1s") || !strings.Contains(msg, "AfterFunc at") || strings.Contains(msg, "Timer at") {
+		t.Errorf("report should list the leaked Ticker and AfterFunc but not the stopped Timer:\n%s", msg)
+	}
+}
+
+func TestFakeClockAssertNoPendingConcurrent(t *testing.T) {
+	// AssertNoPending reads the timers while the clock moves them, so
+	// -race catches it reading them without the lock.
+	clk := NewFake()
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			clk.Add(time.Second)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		clk.AssertNoPending(&fakeTB{})
+	}
+	<-done
+}
+
+func TestFakeClockSinceUntil(t *testing.T) {
+	clk := NewFake()
+	start := clk.Now()
+	end := start.Add(time.Hour)
+
+	clk.Add(20 * time.Minute)
+	if got := clk.Since(start); got != 20*time.Minute {
+		t.Errorf("Since: got %v, want %v", got, 20*time.Minute)
+	}
+	if got := clk.Until(end); got != 40*time.Minute {
+		t.Errorf("Until: got %v, want %v", got, 40*time.Minute)
+	}
+}
+
+func TestFakeClockNowUnix(t *testing.T) {
+	clk := NewFake()
+	clk.Add(90*time.Second + 5*time.Millisecond + 7)
+	if got := clk.NowUnix(); got != 90 {
+		t.Errorf("NowUnix: got %d, want 90", got)
+	}
+	if got := clk.NowUnixMilli(); got != 90005 {
+		t.Errorf("NowUnixMilli: got %d, want 90005", got)
+	}
+	if got := clk.NowUnixNano(); got != 90005000007 {
+		t.Errorf("NowUnixNano: got %d, want 90005000007", got)
+	}
+}
+
+func TestFakeClockNowMonotonic(t *testing.T) {
+	clk := NewFake()
+	if got := clk.NowMonotonic(); got != 0 {
+		t.Errorf("initial NowMonotonic: got %v, want 0", got)
+	}
+	clk.Add(time.Minute)
+	clk.Set(clk.Now().Add(time.Hour))
+	clk.Set(time.Unix(0, 0))
+	clk.Add(-time.Second)
+	if got := clk.NowMonotonic(); got != time.Minute {
+		t.Errorf("NowMonotonic should only be moved by Add with a positive duration: got %v, want %v", got, time.Minute)
+	}
+}
+
+func TestFakeClockAutoIncrement(t *testing.T) {
+	clk := NewFake(WithAutoIncrement(time.Nanosecond))
+	start := clk.Now()
+	seen := map[time.Time]bool{start: true}
+	for i := 0; i < 100; i++ {
+		now := clk.Now()
+		if seen[now] {
+			t.Fatalf("Now returned %v twice", now)
+		}
+		seen[now] = true
+	}
+	if got := clk.NowMonotonic(); got != 101*time.Nanosecond {
+		t.Errorf("NowMonotonic: got %v, want %v", got, 101*time.Nanosecond)
+	}
+
+	// Timers made due by the increments fire on the next Add.
+	c := clk.After(time.Nanosecond)
+	clk.Now()
+	clk.Now()
+	select {
+	case <-c:
+		t.Fatalf("timer fired because of an increment")
+	default:
+	}
+	clk.Add(time.Nanosecond)
+	select {
+	case <-c:
+	default:
+		t.Fatalf("timer made due by increments did not fire on Add")
+	}
+}
+
+func TestFakeClockSleep(t *testing.T) {
+	clk := NewFake()
+	done := make(chan struct{})
+	go func() {
+		clk.Sleep(time.Second)
+		close(done)
+	}()
+
+	// Sleep may not have registered yet, so keep nudging the clock
+	// forward by less than the sleep duration until it does. None of
+	// those nudges should be enough to wake it.
+	for i := 0; i < 10; i++ {
+		clk.Add(time.Millisecond)
+		select {
+		case <-done:
+			t.Fatalf("Sleep returned before the clock was advanced past its deadline")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	clk.Add(time.Second)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Sleep did not return after the clock was advanced past its deadline")
+	}
+
+	// Non-positive durations never block.
+	clk.Sleep(0)
+	clk.Sleep(-time.Second)
+}
+
+func TestFakeClockSleepLoop(t *testing.T) {
+	// Sleeping in a loop is driven one iteration at a time by Add.
+	clk := NewFake()
+	start := clk.Now()
+	iterations := make(chan time.Time)
+	go func() {
+		for i := 0; i < 3; i++ {
+			clk.Sleep(time.Minute)
+			iterations <- clk.Now()
+		}
+	}()
+
+	for i := 1; i <= 3; i++ {
+		clk.BlockUntil(1)
+		clk.Add(time.Minute)
+		select {
+		case got := <-iterations:
+			if want := start.Add(time.Duration(i) * time.Minute); !got.Equal(want) {
+				t.Errorf("iteration %d woke at %v, want %v", i, got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("iteration %d did not wake after Add", i)
+		}
+	}
+}
+
+func TestFakeClockSleepUntil(t *testing.T) {
+	clk := NewFake()
+	wake := clk.Now().Add(time.Hour)
+	done := make(chan struct{})
+	go func() {
+		clk.SleepUntil(wake)
+		close(done)
+	}()
+
+	clk.Set(wake.Add(-time.Nanosecond))
+	select {
+	case <-done:
+		t.Fatalf("SleepUntil returned before its deadline")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clk.Set(wake)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("SleepUntil did not return once the clock reached its deadline")
+	}
+
+	// Deadlines in the past never block.
+	clk.SleepUntil(wake.Add(-time.Hour))
+}
+
+func TestFakeClockSleepContext(t *testing.T) {
+	clk := NewFake()
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- clk.SleepContext(ctx, time.Hour) }()
+	cancel()
+	select {
+	case err := <-errs:
+		if err != context.Canceled {
+			t.Errorf("canceled SleepContext: got %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("SleepContext did not return when its context was canceled")
+	}
+
+	go func() { errs <- clk.SleepContext(context.Background(), time.Second) }()
+	for {
+		clk.Add(time.Second)
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Errorf("completed SleepContext: got %v, want nil", err)
+			}
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestFakeClockAutoAdvance(t *testing.T) {
+	clk := NewFake(WithAutoAdvance())
+	start := clk.Now()
+	fired := false
+	clk.AfterFunc(30*time.Second, func() { fired = true })
+	tm := clk.NewTimer(time.Hour)
+	defer tm.Stop()
+
+	// Sleep returns right away, having moved the clock, and fired
+	// everything due on the way.
+	clk.Sleep(time.Minute)
+	if got := clk.Since(start); got != time.Minute {
+		t.Errorf("Sleep moved the clock by %v, want %v", got, time.Minute)
+	}
+	if !fired {
+		t.Errorf("AfterFunc with an earlier deadline was not called")
+	}
+
+	if got := <-clk.After(time.Minute); !got.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("After sent %v, want %v", got, start.Add(2*time.Minute))
+	}
+	clk.SleepUntil(start.Add(3 * time.Minute))
+	if err := clk.SleepContext(context.Background(), time.Minute); err != nil {
+		t.Errorf("SleepContext: %v", err)
+	}
+	if got := clk.Since(start); got != 4*time.Minute {
+		t.Errorf("clock moved by %v, want %v", got, 4*time.Minute)
+	}
+	if got := clk.NowMonotonic(); got != 4*time.Minute {
+		t.Errorf("NowMonotonic: got %v, want %v", got, 4*time.Minute)
+	}
+}
+
+func TestFakeClockWatchdog(t *testing.T) {
+	reports := make(chan string, 1)
+	clk := NewFake(WithWatchdog(20*time.Millisecond, func(msg string) { reports <- msg }))
+	go clk.Sleep(30 * time.Second)
+	clk.BlockUntil(1)
+
+	select {
+	case msg := <-reports:
+		if !strings.Contains(msg, "Sleep(30s)") || !strings.Contains(msg, "TestFakeClockWatchdog") {
+			t.Errorf("watchdog report should name the blocked call and include its stack, but was:\n%s", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watchdog did not report the blocked sleeper")
+	}
+
+	// Moving the clock keeps the watchdog quiet, and it stops watching
+	// sleepers once they wake.
+	go clk.Sleep(time.Minute)
+	clk.BlockUntil(2)
+	for i := 0; i < 5; i++ {
+		time.Sleep(10 * time.Millisecond)
+		clk.Add(time.Second)
+	}
+	clk.Add(time.Hour)
+	select {
+	case msg := <-reports:
+		t.Errorf("watchdog reported a sleeper while the clock was moving:\n%s", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFakeClockAfter(t *testing.T) {
+	clk := NewFake()
+	start := clk.Now()
+	c := clk.After(time.Minute)
+
+	clk.Add(59 * time.Second)
+	select {
+	case <-c:
+		t.Fatalf("After fired before its deadline")
+	default:
+	}
+
+	clk.Set(start.Add(2 * time.Minute))
+	select {
+	case got := <-c:
+		if want := start.Add(time.Minute); !got.Equal(want) {
+			t.Errorf("After sent %v, want its deadline %v", got, want)
+		}
+	default:
+		t.Fatalf("After did not fire once its deadline passed")
+	}
+
+	select {
+	case <-clk.After(0):
+	default:
+		t.Errorf("After(0) should fire immediately")
+	}
+}
+
+func TestFakeTimer(t *testing.T) {
+	clk := NewFake()
+	tm := clk.NewTimer(time.Minute)
+
+	clk.Add(30 * time.Second)
+	if !tm.Reset(time.Minute) {
+		t.Errorf("Reset of an active timer should return true")
+	}
+	clk.Add(45 * time.Second)
+	select {
+	case <-tm.C():
+		t.Fatalf("timer fired before its reset deadline")
+	default:
+	}
+
+	clk.Add(15 * time.Second)
+	select {
+	case <-tm.C():
+	default:
+		t.Fatalf("timer did not fire at its reset deadline")
+	}
+	if tm.Stop() {
+		t.Errorf("Stop of an expired timer should return false")
+	}
+
+	if tm.Reset(time.Second) {
+		t.Errorf("Reset of an expired timer should return false")
+	}
+	if !tm.Stop() {
+		t.Errorf("Stop of an active timer should return true")
+	}
+	clk.Add(time.Hour)
+	select {
+	case <-tm.C():
+		t.Fatalf("stopped timer fired")
+	default:
+	}
+}
+
+func TestFakeTickerDeliverMissedTicks(t *testing.T) {
+	clk := NewFake(WithMissedTicks(DeliverMissedTicks))
+	start := clk.Now()
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+
+	clk.Add(5 * time.Second)
+	for i := 1; i <= 5; i++ {
+		select {
+		case got := <-tk.C():
+			if want := start.Add(time.Duration(i) * time.Second); !got.Equal(want) {
+				t.Errorf("tick %d: got %v, want %v", i, got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("tick %d was not delivered", i)
+		}
+	}
+	select {
+	case got := <-tk.C():
+		t.Fatalf("got an extra tick %v", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Stopping discards the rest of the backlog.
+	clk.Add(time.Minute)
+	<-tk.C()
+	tk.Stop()
+	select {
+	case got := <-tk.C():
+		t.Fatalf("got a queued tick %v after Stop", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestFakeTickerReplaceMissedTicks(t *testing.T) {
+	for _, m := range []MissedTicks{CoalesceMissedTicks, ReplaceMissedTicks} {
+		clk := NewFake(WithMissedTicks(m))
+		start := clk.Now()
+		tk := clk.NewTicker(time.Second)
+		clk.Add(5*time.Second + 500*time.Millisecond)
+
+		want := start.Add(time.Second)
+		if m == ReplaceMissedTicks {
+			want = start.Add(5 * time.Second)
+		}
+		select {
+		case got := <-tk.C():
+			if !got.Equal(want) {
+				t.Errorf("MissedTicks %d: got tick %v, want %v", m, got, want)
+			}
+		default:
+			t.Errorf("MissedTicks %d: no tick was delivered", m)
+		}
+		select {
+		case got := <-tk.C():
+			t.Errorf("MissedTicks %d: got an extra tick %v", m, got)
+		default:
+		}
+		tk.Stop()
+	}
+}
+
+func TestFakeTimerNoStaleValues(t *testing.T) {
+	clk := NewFake()
+	tm := clk.NewTimer(time.Second)
+	clk.Add(time.Second)
+
+	// The timer fired but nothing received its time, so, as with Go
+	// 1.23's timers, it is still considered active and Reset must
+	// discard the time rather than leaving it for the next receive.
+	if !tm.Reset(time.Second) {
+		t.Errorf("Reset of a fired but unreceived timer should return true")
+	}
+	select {
+	case <-tm.C():
+		t.Fatalf("received a stale time after Reset")
+	default:
+	}
+
+	clk.Add(time.Second)
+	if !tm.Stop() {
+		t.Errorf("Stop of a fired but unreceived timer should return true")
+	}
+	select {
+	case <-tm.C():
+		t.Fatalf("received a stale time after Stop")
+	default:
+	}
+
+	tk := clk.NewTicker(time.Second)
+	clk.Add(time.Second)
+	tk.Stop()
+	select {
+	case <-tk.C():
+		t.Fatalf("received a stale tick after Stop")
+	default:
+	}
+}
+
+func TestFakeTimerAt(t *testing.T) {
+	clk := NewFake()
+	deadline := clk.Now().Add(time.Hour)
+	tm := clk.NewTimerAt(deadline)
+	c := clk.AfterAt(deadline)
+
+	// The deadline is absolute, so moving the clock backwards and
+	// then forwards again doesn't change when the timers fire.
+	clk.Set(deadline.Add(-2 * time.Hour))
+	clk.Add(time.Hour + 59*time.Minute)
+	select {
+	case <-tm.C():
+		t.Fatalf("NewTimerAt fired before its deadline")
+	case <-c:
+		t.Fatalf("AfterAt fired before its deadline")
+	default:
+	}
+
+	clk.Add(time.Minute)
+	for name, ch := range map[string]<-chan time.Time{"NewTimerAt": tm.C(), "AfterAt": c} {
+		select {
+		case <-ch:
+		default:
+			t.Errorf("%s did not fire at its deadline", name)
+		}
+	}
+
+	select {
+	case <-clk.AfterAt(deadline.Add(-time.Second)):
+	default:
+		t.Errorf("AfterAt with a past deadline should fire immediately")
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	clk := NewFake()
+	start := clk.Now()
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+
+	for i := 1; i <= 3; i++ {
+		clk.Add(time.Second)
+		select {
+		case got := <-tk.C():
+			if want := start.Add(time.Duration(i) * time.Second); !got.Equal(want) {
+				t.Errorf("tick %d: got %v, want %v", i, got, want)
+			}
+		default:
+			t.Fatalf("tick %d was not delivered", i)
+		}
+	}
+
+	// Spanning several periods in one Add delivers a single tick and
+	// keeps the ticker on its original period boundaries.
+	clk.Add(3500 * time.Millisecond)
+	<-tk.C()
+	clk.Add(400 * time.Millisecond)
+	select {
+	case <-tk.C():
+		t.Fatalf("ticker ticked off of its period boundary")
+	default:
+	}
+	clk.Add(100 * time.Millisecond)
+	select {
+	case <-tk.C():
+	default:
+		t.Fatalf("ticker missed its period boundary")
+	}
+
+	tk.Reset(time.Minute)
+	clk.Add(time.Second)
+	select {
+	case <-tk.C():
+		t.Fatalf("ticker ticked on its old period after Reset")
+	default:
+	}
+
+	tk.Stop()
+	clk.Add(time.Hour)
+	select {
+	case <-tk.C():
+		t.Fatalf("stopped ticker ticked")
+	default:
+	}
+}
+
+func TestFakeAfterFunc(t *testing.T) {
+	clk := NewFake()
+	called := make(chan struct{}, 1)
+	tm := clk.AfterFunc(time.Minute, func() { called <- struct{}{} })
+	if tm.C() != nil {
+		t.Errorf("AfterFunc timers should have a nil channel")
+	}
+
+	clk.Add(time.Minute)
+	select {
+	case <-called:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("AfterFunc's function was not called after its deadline passed")
+	}
+
+	tm.Reset(time.Minute)
+	if !tm.Stop() {
+		t.Errorf("Stop of a reset AfterFunc timer should return true")
+	}
+	clk.Add(time.Hour)
+	select {
+	case <-called:
+		t.Fatalf("stopped AfterFunc's function was called")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestFakeTick(t *testing.T) {
+	clk := NewFake()
+	if clk.Tick(0) != nil {
+		t.Errorf("Tick(0) should return nil")
+	}
+
+	c := clk.Tick(time.Second)
+	for i := 0; i < 3; i++ {
+		clk.Add(time.Second)
+		select {
+		case <-c:
+		default:
+			t.Fatalf("tick %d was not delivered", i)
+		}
+	}
+}
+
+func TestFakeAfterFuncSynchronous(t *testing.T) {
+	clk := NewFake(WithSynchronousAfterFunc())
+	// Without a new goroutine, calling FailNow in the function is
+	// fine and the function's effects are visible without any
+	// synchronization.
+	called := 0
+	clk.AfterFunc(time.Second, func() {
+		called++
+		if !clk.Now().Equal(time.Unix(1, 0)) {
+			t.Fatalf("Now in AfterFunc function: got %v, want its deadline", clk.Now())
+		}
+	})
+	// A function that is already due is called in a new goroutine, so
+	// that AfterFunc doesn't call it with its caller's locks held.
+	due := make(chan struct{})
+	clk.AfterFunc(0, func() { close(due) })
+	<-due
+	clk.Add(time.Minute)
+	if called != 1 {
+		t.Fatalf("Add did not call the AfterFunc function")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("panic in AfterFunc function did not reach Add's caller")
+		}
+	}()
+	clk.AfterFunc(time.Second, func() { panic("boom") })
+	clk.Add(time.Second)
+}
+
+func TestSynchronousAfterFuncDecorators(t *testing.T) {
+	// The Clocks built on a FakeClock's AfterFunc must not deadlock
+	// when it is given a function that is already due.
+	for name, wrap := range map[string]func(Clock) Clock{
+		"Scale":     func(c Clock) Clock { return Scale(c, 2) },
+		"Drift":     func(c Clock) Clock { return Drift(c, 100) },
+		"Freezable": func(c Clock) Clock { return NewFreezable(c) },
+		"Offset":    func(c Clock) Clock { return Offset(ReadOnly(c.(FakeClock)), time.Second) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			fc := NewFake(WithSynchronousAfterFunc())
+			clk := wrap(fc)
+			<-clk.After(0)
+			tm := clk.NewTimer(time.Second)
+			fc.Add(time.Second)
+			<-tm.C()
+			tm.Reset(0)
+			<-tm.C()
+			done := make(chan struct{})
+			clk.AfterFunc(-time.Second, func() { close(done) })
+			<-done
+		})
+	}
+}
+
+func TestFakeClockFiresInDeadlineOrder(t *testing.T) {
+	clk := NewFake()
+	start := clk.Now()
+	var mu sync.Mutex
+	var seen []time.Duration
+	for _, d := range []time.Duration{3 * time.Minute, time.Minute, 2 * time.Minute} {
+		d := d
+		clk.AfterFunc(d, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			seen = append(seen, clk.Since(start))
+		})
+	}
+	c := clk.After(90 * time.Second)
+
+	clk.Add(10 * time.Minute)
+	want := []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute}
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Errorf("AfterFunc functions saw clock times %v, want %v", seen, want)
+	}
+	if got := <-c; !got.Equal(start.Add(90 * time.Second)) {
+		t.Errorf("After sent %v, want its deadline %v", got, start.Add(90*time.Second))
+	}
+	if !clk.Now().Equal(start.Add(10 * time.Minute)) {
+		t.Errorf("clock ended at %v, want %v", clk.Now(), start.Add(10*time.Minute))
+	}
+}
+
+func TestFakeClockAdvanceToNextTimer(t *testing.T) {
+	clk := NewFake()
+	start := clk.Now()
+	if now, ok := clk.AdvanceToNextTimer(); ok || !now.Equal(start) {
+		t.Errorf("AdvanceToNextTimer with no timers: got %v, %t, want %v, false", now, ok, start)
+	}
+
+	late := clk.After(time.Hour)
+	early := clk.After(17 * time.Minute)
+	now, ok := clk.AdvanceToNextTimer()
+	if want := start.Add(17 * time.Minute); !ok || !now.Equal(want) {
+		t.Errorf("AdvanceToNextTimer: got %v, %t, want %v, true", now, ok, want)
+	}
+	select {
+	case <-early:
+	default:
+		t.Errorf("the earliest timer did not fire")
+	}
+	select {
+	case <-late:
+		t.Errorf("a later timer fired")
+	default:
+	}
+	if got := clk.NowMonotonic(); got != 17*time.Minute {
+		t.Errorf("NowMonotonic: got %v, want %v", got, 17*time.Minute)
+	}
+}
+
+func TestFakeClockRunUntilIdle(t *testing.T) {
+	clk := NewFake()
+	if got := clk.RunUntilIdle(time.Hour); got != 0 {
+		t.Errorf("RunUntilIdle with no timers moved the clock by %v", got)
+	}
+
+	// A retry loop that backs off exponentially, giving up after
+	// five attempts.
+	attempts := 0
+	var retry func()
+	retry = func() {
+		attempts++
+		if attempts < 5 {
+			clk.AfterFunc(time.Duration(1<<uint(attempts))*time.Second, retry)
+		}
+	}
+	clk.AfterFunc(time.Second, retry)
+	if got, want := clk.RunUntilIdle(time.Hour), 31*time.Second; got != want {
+		t.Errorf("RunUntilIdle: got %v, want %v", got, want)
+	}
+	if attempts != 5 {
+		t.Errorf("got %d attempts, want 5", attempts)
+	}
+
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+	if got := clk.RunUntilIdle(time.Minute); got != time.Minute {
+		t.Errorf("RunUntilIdle with a ticker: got %v, want %v", got, time.Minute)
+	}
+}
+
+func TestFakeClockAdvanceBy(t *testing.T) {
+	clk := NewFake()
+	start := clk.Now()
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+
+	// One poll per second, for a minute and a half.
+	var polls []time.Time
+	clk.AdvanceBy(90*time.Second+500*time.Millisecond, time.Second, func(now time.Time) {
+		select {
+		case <-tk.C():
+			polls = append(polls, now)
+		default:
+		}
+	})
+	if got, want := clk.Since(start), 90*time.Second+500*time.Millisecond; got != want {
+		t.Errorf("AdvanceBy moved the clock by %v, want %v", got, want)
+	}
+	if len(polls) != 90 {
+		t.Fatalf("got %d polls, want 90", len(polls))
+	}
+	for i, p := range polls {
+		if want := start.Add(time.Duration(i+1) * time.Second); !p.Equal(want) {
+			t.Errorf("poll %d at %v, want %v", i, p, want)
+		}
+	}
+
+	clk.AdvanceBy(time.Second, time.Millisecond, nil)
+	if got, want := clk.Since(start), 91*time.Second+500*time.Millisecond; got != want {
+		t.Errorf("AdvanceBy with nil between moved the clock to %v, want %v", got, want)
+	}
+}
+
+func TestFakeClockAdvanceUntil(t *testing.T) {
+	clk := NewFake()
+	start := clk.Now()
+	var done atomic.Bool
+	clk.AfterFunc(10*time.Second, func() { done.Store(true) })
+	if err := clk.AdvanceUntil(done.Load, 3*time.Second, time.Minute); err != nil {
+		t.Fatalf("AdvanceUntil: %v", err)
+	}
+	if got, want := clk.Since(start), 12*time.Second; got != want {
+		t.Errorf("AdvanceUntil moved the clock by %v, want %v", got, want)
+	}
+
+	if err := clk.AdvanceUntil(done.Load, time.Hour, time.Minute); err != nil {
+		t.Errorf("AdvanceUntil with cond already true: %v", err)
+	}
+	if got, want := clk.Since(start), 12*time.Second; got != want {
+		t.Errorf("AdvanceUntil with cond already true moved the clock to %v, want %v", got, want)
+	}
+
+	never := func() bool { return false }
+	if err := clk.AdvanceUntil(never, 7*time.Second, 30*time.Second); err == nil {
+		t.Errorf("AdvanceUntil with cond never true returned nil")
+	}
+	if got, want := clk.Since(start), 42*time.Second; got != want {
+		t.Errorf("AdvanceUntil with cond never true moved the clock to %v, want %v", got, want)
+	}
+}
+
+func TestSetDefaultForTesting(t *testing.T) {
+	// Default isn't the system clock in test binaries built with the
+	// clockenv tag and its environment variables set.
+	prev := Default()
+	fc := NewFake()
+	restore := SetDefaultForTesting(fc)
+	if Default() != fc {
+		t.Errorf("Default() did not return the Clock given to SetDefaultForTesting")
+	}
+	other := NewFake()
+	restoreFC := SetDefaultForTesting(other)
+	if Default() != other {
+		t.Errorf("Default() did not return the Clock given to a nested SetDefaultForTesting")
+	}
+	restoreFC()
+	if Default() != fc {
+		t.Errorf("Default() after the nested restore did not return the first Clock")
+	}
+	restore()
+	if Default() != prev {
+		t.Errorf("Default() after restore = %T, want the Clock from before, %T", Default(), prev)
+	}
+}
+
+func TestFakeClockYield(t *testing.T) {
+	clk := NewFake(WithYield(10 * time.Millisecond))
+	start := clk.Now()
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+	ticks := make(chan time.Time, 3)
+	go func() {
+		for i := 0; i < 3; i++ {
+			ticks <- <-tk.C()
+		}
+	}()
+
+	// Each tick is taken by the receiver before the next is due, so
+	// none is dropped, even though they're all in one call to Set.
+	clk.Set(start.Add(3 * time.Second))
+	for i := 1; i <= 3; i++ {
+		select {
+		case got := <-ticks:
+			if want := start.Add(time.Duration(i) * time.Second); !got.Equal(want) {
+				t.Errorf("tick %d: got %v, want %v", i, got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("receiver did not get tick %d", i)
+		}
+	}
+}
+
+func TestFakeClockRewindGuard(t *testing.T) {
+	clk := NewFake(WithRewindGuard())
+	start := clk.Now()
+
+	// Rewinding with nothing waiting is fine.
+	clk.Add(time.Hour)
+	clk.Set(start)
+	clk.Add(-time.Minute)
+
+	clk.After(time.Hour)
+	for name, rewind := range map[string]func(){
+		"Set": func() { clk.Set(start.Add(-time.Hour)) },
+		"Add": func() { clk.Add(-time.Second) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s moving the clock back with a waiter did not panic", name)
+				}
+			}()
+			rewind()
+		}()
+	}
+	clk.Add(0)
+	clk.Set(clk.Now())
+}
+
+func TestFakeClockFiresCascadedTimers(t *testing.T) {
+	clk := NewFake()
+	start := clk.Now()
+	var fired []time.Duration
+	var every func()
+	every = func() {
+		fired = append(fired, clk.Since(start))
+		clk.AfterFunc(time.Minute, every)
+	}
+	clk.AfterFunc(time.Minute, every)
+
+	// A timer created by an AfterFunc function with a deadline inside
+	// the remaining span of the Add fires during that same Add.
+	clk.AfterFunc(30*time.Second, func() {
+		clk.AfterFunc(time.Minute, func() {
+			fired = append(fired, -clk.Since(start))
+		})
+	})
+
+	clk.Add(3*time.Minute + 30*time.Second)
+	want := []time.Duration{time.Minute, -90 * time.Second, 2 * time.Minute, 3 * time.Minute}
+	if fmt.Sprint(fired) != fmt.Sprint(want) {
+		t.Errorf("fired at %v, want %v", fired, want)
 	}
 }
 
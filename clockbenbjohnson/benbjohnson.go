@@ -0,0 +1,271 @@
+// Package clockbenbjohnson converts between clock.Clock and the Clock of
+// github.com/benbjohnson/clock, so that one clock, and in tests one fake
+// clock, can drive both code using this module and code using
+// benbjohnson/clock.
+//
+// benbjohnson/clock's Timer, Ticker and Mock are structs whose fields,
+// apart from C, are unexported, so nothing outside that package can
+// make a working one. The simplest way to drive both kinds of code from
+// one fake is a benbjohnson Mock given to this module's code through
+// FromBenbjohnson. ToBenbjohnson goes the other way, with the limits
+// given in its documentation.
+package clockbenbjohnson
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	benclock "github.com/benbjohnson/clock"
+	"github.com/jmhodges/clock"
+)
+
+// ToBenbjohnson returns a benbjohnson Clock backed by clk. If clk is
+// clock.Default(), benbjohnson's own real clock is returned, and if clk
+// was returned by FromBenbjohnson, the Clock it was made from is.
+//
+// Otherwise, its Now, Since, Until, Sleep, After, Tick, WithDeadline
+// and WithTimeout use clk directly. Its Timers, Tickers and AfterFunc
+// functions belong to a benbjohnson Mock that is set to clk's time each
+// time one of them comes due on clk. Because Stop and Reset on those
+// can't be seen by the adapter, a Timer or Ticker that is Reset only
+// fires once another one made by the same Clock brings the Mock up to
+// date, and every Ticker keeps a waiter on clk, once per period, for as
+// long as clk is in use. Tests should use ToBenbjohnsonForTest instead.
+func ToBenbjohnson(clk clock.Clock) benclock.Clock {
+	if f, ok := clk.(*fromBenbjohnson); ok {
+		return f.c
+	}
+	if clk == clock.Default() {
+		return benclock.New()
+	}
+	m := benclock.NewMock()
+	m.Set(clk.Now())
+	return &toBenbjohnson{clk: clk, m: m, waiters: make(map[uint64]clock.Timer)}
+}
+
+// ToBenbjohnsonForTest is ToBenbjohnson for tests: at the end of the
+// test tb belongs to, it stops all of the adapter's waiters on clk, so
+// that they aren't reported by clock.WithLeakCheck.
+func ToBenbjohnsonForTest(tb clock.TB, clk clock.Clock) benclock.Clock {
+	c := ToBenbjohnson(clk)
+	if t, ok := c.(*toBenbjohnson); ok {
+		tb.Cleanup(t.stop)
+	}
+	return c
+}
+
+type toBenbjohnson struct {
+	clk clock.Clock
+
+	mu sync.Mutex // serializes calls to m.Set
+	m  *benclock.Mock
+
+	wmu     sync.Mutex // guards the fields below
+	waiters map[uint64]clock.Timer
+	n       uint64
+	stopped bool
+}
+
+// sync brings the Mock up to clk's time, firing what has come due on it.
+func (t *toBenbjohnson) sync() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if now := t.clk.Now(); now.After(t.m.Now()) {
+		t.m.Set(now)
+	}
+}
+
+// after calls fn once d has passed on clk, unless the test has ended
+// by then.
+func (t *toBenbjohnson) after(d time.Duration, fn func()) {
+	t.wmu.Lock()
+	defer t.wmu.Unlock()
+	if t.stopped {
+		return
+	}
+	t.n++
+	id := t.n
+	t.waiters[id] = t.clk.AfterFunc(d, func() {
+		t.wmu.Lock()
+		delete(t.waiters, id)
+		t.wmu.Unlock()
+		fn()
+	})
+}
+
+// stop stops the waiters on clk, for good, at the end of the test.
+func (t *toBenbjohnson) stop() {
+	t.wmu.Lock()
+	defer t.wmu.Unlock()
+	t.stopped = true
+	for id, w := range t.waiters {
+		w.Stop()
+		delete(t.waiters, id)
+	}
+}
+
+func (t *toBenbjohnson) After(d time.Duration) <-chan time.Time {
+	return t.clk.After(d)
+}
+
+func (t *toBenbjohnson) AfterFunc(d time.Duration, f func()) *benclock.Timer {
+	t.sync()
+	bt := t.m.AfterFunc(d, f)
+	t.after(d, t.sync)
+	return bt
+}
+
+func (t *toBenbjohnson) Now() time.Time {
+	return t.clk.Now()
+}
+
+func (t *toBenbjohnson) Since(u time.Time) time.Duration {
+	return t.clk.Since(u)
+}
+
+func (t *toBenbjohnson) Until(u time.Time) time.Duration {
+	return t.clk.Until(u)
+}
+
+func (t *toBenbjohnson) Sleep(d time.Duration) {
+	t.clk.Sleep(d)
+}
+
+func (t *toBenbjohnson) Tick(d time.Duration) <-chan time.Time {
+	return t.clk.Tick(d)
+}
+
+func (t *toBenbjohnson) Ticker(d time.Duration) *benclock.Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker")
+	}
+	t.sync()
+	bt := t.m.Ticker(d)
+	var tick func()
+	tick = func() {
+		t.sync()
+		t.after(d, tick)
+	}
+	t.after(d, tick)
+	return bt
+}
+
+func (t *toBenbjohnson) Timer(d time.Duration) *benclock.Timer {
+	t.sync()
+	bt := t.m.Timer(d)
+	t.after(d, t.sync)
+	return bt
+}
+
+func (t *toBenbjohnson) WithDeadline(parent context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	return clock.WithDeadline(parent, t.clk, d)
+}
+
+func (t *toBenbjohnson) WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return clock.WithTimeout(parent, t.clk, d)
+}
+
+// FromBenbjohnson returns a clock.Clock backed by c, such as a
+// benbjohnson Mock. Its NowMonotonic is the time that has passed on c
+// since FromBenbjohnson was called. If c was returned by ToBenbjohnson,
+// the clock.Clock it was made from is returned.
+func FromBenbjohnson(c benclock.Clock) clock.Clock {
+	if t, ok := c.(*toBenbjohnson); ok {
+		return t.clk
+	}
+	return &fromBenbjohnson{c: c, Monotonic: clock.NewMonotonic(c.Now)}
+}
+
+type fromBenbjohnson struct {
+	c benclock.Clock
+	clock.Monotonic
+}
+
+func (f *fromBenbjohnson) Now() time.Time {
+	return f.c.Now()
+}
+
+func (f *fromBenbjohnson) NowUnix() int64 {
+	return f.c.Now().Unix()
+}
+
+func (f *fromBenbjohnson) NowUnixMilli() int64 {
+	return f.c.Now().UnixMilli()
+}
+
+func (f *fromBenbjohnson) NowUnixNano() int64 {
+	return f.c.Now().UnixNano()
+}
+
+func (f *fromBenbjohnson) Since(t time.Time) time.Duration {
+	return f.c.Since(t)
+}
+
+func (f *fromBenbjohnson) Until(t time.Time) time.Duration {
+	return f.c.Until(t)
+}
+
+func (f *fromBenbjohnson) Sleep(d time.Duration) {
+	f.c.Sleep(d)
+}
+
+func (f *fromBenbjohnson) SleepUntil(t time.Time) {
+	f.c.Sleep(f.c.Until(t))
+}
+
+func (f *fromBenbjohnson) SleepContext(ctx context.Context, d time.Duration) error {
+	return clock.SleepContextWith(ctx, d, f.NewTimer)
+}
+
+func (f *fromBenbjohnson) After(d time.Duration) <-chan time.Time {
+	return f.c.After(d)
+}
+
+func (f *fromBenbjohnson) AfterAt(t time.Time) <-chan time.Time {
+	return f.c.After(f.c.Until(t))
+}
+
+func (f *fromBenbjohnson) NewTimer(d time.Duration) clock.Timer {
+	return timer{f.c.Timer(d)}
+}
+
+func (f *fromBenbjohnson) NewTimerAt(t time.Time) clock.Timer {
+	return timer{f.c.Timer(f.c.Until(t))}
+}
+
+func (f *fromBenbjohnson) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return ticker{f.c.Ticker(d)}
+}
+
+func (f *fromBenbjohnson) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return f.c.Tick(d)
+}
+
+func (f *fromBenbjohnson) AfterFunc(d time.Duration, fn func()) clock.Timer {
+	return timer{f.c.AfterFunc(d, fn)}
+}
+
+// timer is a benbjohnson Timer as a clock.Timer.
+type timer struct {
+	*benclock.Timer
+}
+
+func (t timer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// ticker is a benbjohnson Ticker as a clock.Ticker.
+type ticker struct {
+	*benclock.Ticker
+}
+
+func (t ticker) C() <-chan time.Time {
+	return t.Ticker.C
+}
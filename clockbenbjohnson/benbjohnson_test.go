@@ -0,0 +1,89 @@
+package clockbenbjohnson
+
+import (
+	"testing"
+	"time"
+
+	benclock "github.com/benbjohnson/clock"
+	"github.com/jmhodges/clock"
+)
+
+func TestToBenbjohnson(t *testing.T) {
+	fc := clock.NewFake(clock.WithLeakCheck(t))
+	bc := ToBenbjohnsonForTest(t, fc)
+	if FromBenbjohnson(bc) != clock.Clock(fc) {
+		t.Errorf("FromBenbjohnson(ToBenbjohnson(fc)) is not fc")
+	}
+	start := fc.Now()
+	tm := bc.Timer(time.Second)
+	tk := bc.Ticker(time.Minute)
+	defer tk.Stop()
+	fired := make(chan struct{})
+	bc.AfterFunc(time.Hour, func() { close(fired) })
+
+	fc.Add(time.Second)
+	if got, want := <-tm.C, start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	fc.Add(time.Minute)
+	if got, want := <-tk.C, start.Add(time.Minute); !got.Equal(want) {
+		t.Errorf("Ticker sent %v, want %v", got, want)
+	}
+	fc.Set(start.Add(time.Hour))
+	<-fired
+	if got := bc.Since(start); got != time.Hour {
+		t.Errorf("Since = %v, want %v", got, time.Hour)
+	}
+}
+
+func TestToBenbjohnsonCleanup(t *testing.T) {
+	fc := clock.NewFake()
+	t.Run("test", func(t *testing.T) {
+		bc := ToBenbjohnsonForTest(t, fc)
+		bc.Ticker(time.Second).Stop()
+		bc.Timer(time.Hour).Stop()
+		fc.Add(time.Minute)
+	})
+	if n := fc.Waiters(); n != 0 {
+		t.Errorf("%d waiters left on the FakeClock after the test, want 0", n)
+	}
+}
+
+func TestToBenbjohnsonWrapped(t *testing.T) {
+	// A Clock wrapping the real one needs no test.
+	bc := ToBenbjohnson(clock.Offset(clock.Default(), time.Hour))
+	if d := bc.Since(clock.Default().Now()); d < time.Hour-time.Minute || d > time.Hour+time.Minute {
+		t.Errorf("Since(clock.Default().Now()) = %v, want about %v", d, time.Hour)
+	}
+	<-bc.Timer(time.Millisecond).C
+}
+
+func TestToBenbjohnsonDefault(t *testing.T) {
+	if _, ok := ToBenbjohnson(clock.Default()).(*benclock.Mock); ok {
+		t.Errorf("ToBenbjohnson(clock.Default()) is a Mock")
+	}
+}
+
+func TestFromBenbjohnson(t *testing.T) {
+	m := benclock.NewMock()
+	clk := FromBenbjohnson(m)
+	if ToBenbjohnson(clk) != benclock.Clock(m) {
+		t.Errorf("ToBenbjohnson(FromBenbjohnson(m)) is not m")
+	}
+	start := clk.Now()
+	tm := clk.NewTimerAt(start.Add(time.Second))
+	m.Add(time.Second)
+	if got, want := <-tm.C(), start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got := clk.NowMonotonic(); got != time.Second {
+		t.Errorf("NowMonotonic() = %v, want %v", got, time.Second)
+	}
+	if tm.Reset(time.Second) {
+		t.Errorf("Reset of a fired Timer = true, want false")
+	}
+	m.Add(time.Second)
+	if got, want := <-tm.C(), start.Add(2*time.Second); !got.Equal(want) {
+		t.Errorf("Reset Timer sent %v, want %v", got, want)
+	}
+}
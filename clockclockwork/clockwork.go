@@ -0,0 +1,187 @@
+// Package clockclockwork converts between clock.Clock and the Clock of
+// github.com/jonboulle/clockwork, so that one clock, and in tests one
+// FakeClock, can drive both code using this module and code using
+// clockwork.
+//
+// Code given ToClockwork(fc), where fc is a clock.FakeClock, is driven
+// by fc's Add and Set. Code that needs clockwork's own *FakeClock
+// rather than its Clock interface can't be, since that is a concrete
+// type, but code using this module can be driven by one through
+// FromClockwork.
+package clockclockwork
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/jonboulle/clockwork"
+)
+
+// ToClockwork returns a clockwork.Clock backed by clk. If clk was
+// returned by FromClockwork, the clockwork.Clock it was made from is
+// returned.
+func ToClockwork(clk clock.Clock) clockwork.Clock {
+	if f, ok := clk.(*fromClockwork); ok {
+		return f.c
+	}
+	return toClockwork{clk}
+}
+
+type toClockwork struct {
+	clk clock.Clock
+}
+
+func (t toClockwork) After(d time.Duration) <-chan time.Time {
+	return t.clk.After(d)
+}
+
+func (t toClockwork) Sleep(d time.Duration) {
+	t.clk.Sleep(d)
+}
+
+func (t toClockwork) Now() time.Time {
+	return t.clk.Now()
+}
+
+func (t toClockwork) Since(u time.Time) time.Duration {
+	return t.clk.Since(u)
+}
+
+func (t toClockwork) Until(u time.Time) time.Duration {
+	return t.clk.Until(u)
+}
+
+func (t toClockwork) NewTicker(d time.Duration) clockwork.Ticker {
+	return cwTicker{t.clk.NewTicker(d)}
+}
+
+func (t toClockwork) NewTimer(d time.Duration) clockwork.Timer {
+	return cwTimer{t.clk.NewTimer(d)}
+}
+
+func (t toClockwork) AfterFunc(d time.Duration, f func()) clockwork.Timer {
+	return cwTimer{t.clk.AfterFunc(d, f)}
+}
+
+// cwTicker is a clock.Ticker as a clockwork.Ticker.
+type cwTicker struct {
+	clock.Ticker
+}
+
+func (t cwTicker) Chan() <-chan time.Time {
+	return t.C()
+}
+
+// cwTimer is a clock.Timer as a clockwork.Timer.
+type cwTimer struct {
+	clock.Timer
+}
+
+func (t cwTimer) Chan() <-chan time.Time {
+	return t.C()
+}
+
+// FromClockwork returns a clock.Clock backed by c, such as a
+// clockwork.FakeClock. Its NowMonotonic is the time that has passed on
+// c since FromClockwork was called. If c was returned by ToClockwork,
+// the clock.Clock it was made from is returned.
+func FromClockwork(c clockwork.Clock) clock.Clock {
+	if t, ok := c.(toClockwork); ok {
+		return t.clk
+	}
+	return &fromClockwork{c: c, Monotonic: clock.NewMonotonic(c.Now)}
+}
+
+type fromClockwork struct {
+	c clockwork.Clock
+	clock.Monotonic
+}
+
+func (f *fromClockwork) Now() time.Time {
+	return f.c.Now()
+}
+
+func (f *fromClockwork) NowUnix() int64 {
+	return f.c.Now().Unix()
+}
+
+func (f *fromClockwork) NowUnixMilli() int64 {
+	return f.c.Now().UnixMilli()
+}
+
+func (f *fromClockwork) NowUnixNano() int64 {
+	return f.c.Now().UnixNano()
+}
+
+func (f *fromClockwork) Since(t time.Time) time.Duration {
+	return f.c.Since(t)
+}
+
+func (f *fromClockwork) Until(t time.Time) time.Duration {
+	return f.c.Until(t)
+}
+
+func (f *fromClockwork) Sleep(d time.Duration) {
+	f.c.Sleep(d)
+}
+
+func (f *fromClockwork) SleepUntil(t time.Time) {
+	f.c.Sleep(f.c.Until(t))
+}
+
+func (f *fromClockwork) SleepContext(ctx context.Context, d time.Duration) error {
+	return clock.SleepContextWith(ctx, d, f.NewTimer)
+}
+
+func (f *fromClockwork) After(d time.Duration) <-chan time.Time {
+	return f.c.After(d)
+}
+
+func (f *fromClockwork) AfterAt(t time.Time) <-chan time.Time {
+	return f.c.After(f.c.Until(t))
+}
+
+func (f *fromClockwork) NewTimer(d time.Duration) clock.Timer {
+	return timer{f.c.NewTimer(d)}
+}
+
+func (f *fromClockwork) NewTimerAt(t time.Time) clock.Timer {
+	return timer{f.c.NewTimer(f.c.Until(t))}
+}
+
+func (f *fromClockwork) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return ticker{f.c.NewTicker(d)}
+}
+
+func (f *fromClockwork) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return f.c.NewTicker(d).Chan()
+}
+
+func (f *fromClockwork) AfterFunc(d time.Duration, fn func()) clock.Timer {
+	return timer{f.c.AfterFunc(d, fn)}
+}
+
+// timer is a clockwork.Timer as a clock.Timer.
+type timer struct {
+	clockwork.Timer
+}
+
+func (t timer) C() <-chan time.Time {
+	return t.Chan()
+}
+
+// ticker is a clockwork.Ticker as a clock.Ticker.
+type ticker struct {
+	clockwork.Ticker
+}
+
+func (t ticker) C() <-chan time.Time {
+	return t.Chan()
+}
@@ -0,0 +1,50 @@
+package clockclockwork
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/jonboulle/clockwork"
+)
+
+func TestToClockwork(t *testing.T) {
+	fc := clock.NewFake()
+	cw := ToClockwork(fc)
+	if FromClockwork(cw) != clock.Clock(fc) {
+		t.Errorf("FromClockwork(ToClockwork(fc)) is not fc")
+	}
+	tm := cw.NewTimer(time.Second)
+	tk := cw.NewTicker(time.Minute)
+	defer tk.Stop()
+	fc.Add(time.Minute)
+	if got, want := <-tm.Chan(), fc.Now().Add(-59*time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got := <-tk.Chan(); !got.Equal(fc.Now()) {
+		t.Errorf("Ticker sent %v, want %v", got, fc.Now())
+	}
+	if got := cw.Since(fc.Now().Add(-time.Hour)); got != time.Hour {
+		t.Errorf("Since = %v, want %v", got, time.Hour)
+	}
+}
+
+func TestFromClockwork(t *testing.T) {
+	cf := clockwork.NewFakeClock()
+	clk := FromClockwork(cf)
+	if ToClockwork(clk) != clockwork.Clock(cf) {
+		t.Errorf("ToClockwork(FromClockwork(cf)) is not cf")
+	}
+	start := clk.Now()
+	tm := clk.NewTimerAt(start.Add(time.Second))
+	cf.Advance(time.Second)
+	if got, want := <-tm.C(), start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got := clk.NowMonotonic(); got != time.Second {
+		t.Errorf("NowMonotonic() = %v, want %v", got, time.Second)
+	}
+	if got, want := clk.NowUnix(), start.Add(time.Second).Unix(); got != want {
+		t.Errorf("NowUnix() = %d, want %d", got, want)
+	}
+}
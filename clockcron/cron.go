@@ -0,0 +1,254 @@
+// Package clockcron runs github.com/robfig/cron/v3 schedules on a
+// clock.Clock. robfig/cron's own Cron always reads the system's time, so
+// this package provides a Cron with the same methods that takes a Clock,
+// and reuses robfig/cron's parsers, Schedules, Jobs and JobWrappers. In
+// tests, given a clock.FakeClock, its jobs run when the test moves the
+// clock past their scheduled times.
+package clockcron
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/robfig/cron/v3"
+)
+
+// Cron runs jobs on their schedules, like robfig/cron's Cron, with time
+// read from a Clock. Each entry waits on the Clock with its own
+// AfterFunc while the Cron is running.
+//
+// When an entry comes due, its job is started in its own goroutine and
+// its next time is worked out from the Clock's time. A FakeClock runs
+// AfterFunc functions at their own times as Add moves past them and
+// waits for them to return, so moving one past several of an entry's
+// times starts its job at each, and the entry is waiting for its next
+// time by the time Add returns. Other Clocks, like robfig/cron itself,
+// skip the times that pass while an entry's job is being started.
+type Cron struct {
+	clk      clock.Clock
+	chain    cron.Chain
+	location *time.Location
+	parser   cron.ScheduleParser
+
+	mu      sync.Mutex
+	running bool
+	nextID  cron.EntryID
+	entries map[cron.EntryID]*entry
+	jobs    sync.WaitGroup
+}
+
+// entry is a cron.Entry and what is waiting for its next time.
+type entry struct {
+	cron.Entry
+	timer clock.Timer // nil when not waiting
+	gen   int         // incremented when timer is replaced or stopped
+}
+
+// Option changes how New sets up a Cron.
+type Option func(*Cron)
+
+// WithLocation sets the time zone schedules are read in. The default is
+// time.Local.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Cron) {
+		c.location = loc
+	}
+}
+
+// WithSeconds makes specs start with a seconds field.
+func WithSeconds() Option {
+	return WithParser(cron.NewParser(
+		cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+	))
+}
+
+// WithParser sets the parser AddFunc and AddJob read specs with.
+func WithParser(p cron.ScheduleParser) Option {
+	return func(c *Cron) {
+		c.parser = p
+	}
+}
+
+// WithChain wraps every job added to the Cron with wrappers.
+func WithChain(wrappers ...cron.JobWrapper) Option {
+	return func(c *Cron) {
+		c.chain = cron.NewChain(wrappers...)
+	}
+}
+
+// New returns a Cron, not yet started, that reads time from clk. By
+// default it reads specs as robfig/cron does, in the standard five-field
+// form or as descriptors such as "@every 1h".
+func New(clk clock.Clock, opts ...Option) *Cron {
+	c := &Cron{
+		clk:      clk,
+		chain:    cron.NewChain(),
+		location: time.Local,
+		parser: cron.NewParser(
+			cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+		),
+		entries: make(map[cron.EntryID]*entry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// AddFunc adds cmd to run on the schedule given by spec.
+func (c *Cron) AddFunc(spec string, cmd func()) (cron.EntryID, error) {
+	return c.AddJob(spec, cron.FuncJob(cmd))
+}
+
+// AddJob adds cmd to run on the schedule given by spec.
+func (c *Cron) AddJob(spec string, cmd cron.Job) (cron.EntryID, error) {
+	schedule, err := c.parser.Parse(spec)
+	if err != nil {
+		return 0, err
+	}
+	return c.Schedule(schedule, cmd), nil
+}
+
+// Schedule adds cmd to run on schedule.
+func (c *Cron) Schedule(schedule cron.Schedule, cmd cron.Job) cron.EntryID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	e := &entry{Entry: cron.Entry{
+		ID:         c.nextID,
+		Schedule:   schedule,
+		WrappedJob: c.chain.Then(cmd),
+		Job:        cmd,
+	}}
+	c.entries[e.ID] = e
+	if c.running {
+		c.arm(e)
+	}
+	return e.ID
+}
+
+// Entries returns a snapshot of the Cron's entries, in the order they
+// will next run. Entries that will never run again come last.
+func (c *Cron) Entries() []cron.Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]cron.Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, e.Entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch {
+		case a.Next.IsZero() != b.Next.IsZero():
+			return b.Next.IsZero()
+		case !a.Next.Equal(b.Next):
+			return a.Next.Before(b.Next)
+		}
+		return a.ID < b.ID
+	})
+	return entries
+}
+
+// Entry returns a snapshot of the entry with the given id, or the zero
+// Entry if there is none.
+func (c *Cron) Entry(id cron.EntryID) cron.Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[id]; ok {
+		return e.Entry
+	}
+	return cron.Entry{}
+}
+
+// Location returns the time zone schedules are read in.
+func (c *Cron) Location() *time.Location {
+	return c.location
+}
+
+// Remove stops the entry with the given id from running again.
+func (c *Cron) Remove(id cron.EntryID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[id]; ok {
+		c.disarm(e)
+		delete(c.entries, id)
+	}
+}
+
+// Start starts the Cron's entries waiting for their next times. Starting
+// a running Cron does nothing.
+func (c *Cron) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.running {
+		return
+	}
+	c.running = true
+	for _, e := range c.entries {
+		c.arm(e)
+	}
+}
+
+// Stop stops the Cron from running any more jobs, if it is running. The
+// Context returned is done once the jobs already running have returned.
+func (c *Cron) Stop() context.Context {
+	c.mu.Lock()
+	if c.running {
+		c.running = false
+		for _, e := range c.entries {
+			c.disarm(e)
+		}
+	}
+	c.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		c.jobs.Wait()
+		cancel()
+	}()
+	return ctx
+}
+
+// arm sets e's next time from the Clock's and waits for it. c.mu must be
+// held.
+func (c *Cron) arm(e *entry) {
+	now := c.clk.Now().In(c.location)
+	e.Next = e.Schedule.Next(now)
+	if e.Next.IsZero() {
+		return
+	}
+	e.gen++
+	gen := e.gen
+	e.timer = c.clk.AfterFunc(e.Next.Sub(now), func() { c.run(e, gen) })
+}
+
+// disarm stops e waiting for its next time. c.mu must be held.
+func (c *Cron) disarm(e *entry) {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+	e.gen++
+	e.Next = time.Time{}
+}
+
+// run starts e's job, if e is still waiting for the time gen was armed
+// for, and waits for its next time.
+func (c *Cron) run(e *entry, gen int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.running || e.gen != gen {
+		return
+	}
+	e.Prev = e.Next
+	e.timer = nil
+	job := e.WrappedJob
+	c.jobs.Add(1)
+	go func() {
+		defer c.jobs.Done()
+		job.Run()
+	}()
+	c.arm(e)
+}
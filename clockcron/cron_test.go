@@ -0,0 +1,60 @@
+package clockcron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+func TestCron(t *testing.T) {
+	fc := clock.NewFake()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc.Set(start)
+	c := New(fc, WithLocation(time.UTC))
+	ran := make(chan time.Time, 10)
+	id, err := c.AddFunc("*/5 * * * *", func() { ran <- fc.Now() })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddFunc("not a spec", func() {}); err == nil {
+		t.Errorf("AddFunc accepted a bad spec")
+	}
+	if e := c.Entry(id); !e.Next.IsZero() {
+		t.Errorf("Next = %v before Start, want zero", e.Next)
+	}
+
+	c.Start()
+	fc.BlockUntil(1)
+	if got, want := c.Entry(id).Next, start.Add(5*time.Minute); !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+	fc.Add(5 * time.Minute)
+	if got, want := <-ran, start.Add(5*time.Minute); !got.Equal(want) {
+		t.Errorf("job ran at %v, want %v", got, want)
+	}
+
+	// Moving a FakeClock past several times runs the job at each.
+	fc.Add(time.Hour)
+	for range 12 {
+		<-ran
+	}
+	select {
+	case at := <-ran:
+		t.Errorf("job ran an extra time, at %v", at)
+	default:
+	}
+	e := c.Entries()[0]
+	if want := start.Add(70 * time.Minute); !e.Prev.Equal(start.Add(65*time.Minute)) || !e.Next.Equal(want) {
+		t.Errorf("Prev, Next = %v, %v, want %v, %v", e.Prev, e.Next, start.Add(65*time.Minute), want)
+	}
+
+	<-c.Stop().Done()
+	if n := fc.Waiters(); n != 0 {
+		t.Errorf("%d waiters left after Stop", n)
+	}
+	c.Remove(id)
+	if len(c.Entries()) != 0 {
+		t.Errorf("Entries() = %v after Remove", c.Entries())
+	}
+}
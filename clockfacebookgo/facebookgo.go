@@ -0,0 +1,366 @@
+// Package clockfacebookgo converts between clock.Clock and the Clock of
+// github.com/facebookgo/clock, so that older code still using
+// facebookgo/clock can be driven by the same clock, and in tests the
+// same FakeClock, as code using this module.
+//
+// facebookgo/clock's Timer, Ticker and Mock are structs whose fields,
+// apart from C, are unexported, so nothing outside that package can
+// make a working one. ToFacebookgo gets around this with a facebookgo
+// Mock kept up to date with the Clock it wraps, with the limits given
+// in its documentation.
+package clockfacebookgo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	fbclock "github.com/facebookgo/clock"
+	"github.com/jmhodges/clock"
+)
+
+// ToFacebookgo returns a facebookgo Clock backed by clk. If clk is
+// clock.Default(), facebookgo's own real clock is returned, and if clk
+// was returned by FromFacebookgo, the Clock it was made from is.
+//
+// Otherwise, its Now, Sleep, After and Tick use clk directly. Its
+// Timers, Tickers and AfterFunc functions belong to a facebookgo Mock
+// that is moved up to clk's time, from its own goroutine, each time one
+// of them comes due on clk. As with facebookgo's Mock, sending on the
+// channel of a Timer waits for it to be received, and the Mock can't
+// move on to the next until it has. Because Stop on a Ticker can't be
+// seen by the adapter, every Ticker keeps a waiter on clk, once per
+// period, for as long as clk is in use. Tests should use
+// ToFacebookgoForTest instead.
+func ToFacebookgo(clk clock.Clock) fbclock.Clock {
+	if f, ok := clk.(*fromFacebookgo); ok {
+		return f.c
+	}
+	if clk == clock.Default() {
+		return fbclock.New()
+	}
+	m := fbclock.NewMock()
+	m.Add(clk.Now().Sub(m.Now()))
+	return &toFacebookgo{clk: clk, m: m, waiters: make(map[uint64]clock.Timer)}
+}
+
+// ToFacebookgoForTest is ToFacebookgo for tests: at the end of the test
+// tb belongs to, it stops all of the adapter's waiters on clk, so that
+// they aren't reported by clock.WithLeakCheck.
+func ToFacebookgoForTest(tb clock.TB, clk clock.Clock) fbclock.Clock {
+	c := ToFacebookgo(clk)
+	if t, ok := c.(*toFacebookgo); ok {
+		tb.Cleanup(t.stop)
+	}
+	return c
+}
+
+type toFacebookgo struct {
+	clk clock.Clock
+
+	mu sync.Mutex // serializes calls to m.Add
+	m  *fbclock.Mock
+
+	wmu     sync.Mutex // guards the fields below
+	waiters map[uint64]clock.Timer
+	n       uint64
+	stopped bool
+}
+
+// sync moves the Mock up to clk's time, firing what has come due on it.
+func (t *toFacebookgo) sync() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d := t.clk.Now().Sub(t.m.Now()); d > 0 {
+		t.m.Add(d)
+	}
+}
+
+// syncAfter syncs the Mock from its own goroutine once d has passed on
+// clk.
+func (t *toFacebookgo) syncAfter(d time.Duration) {
+	t.after(d, func() { go t.sync() })
+}
+
+// after calls fn once d has passed on clk, unless the test has ended
+// by then.
+func (t *toFacebookgo) after(d time.Duration, fn func()) {
+	t.wmu.Lock()
+	defer t.wmu.Unlock()
+	if t.stopped {
+		return
+	}
+	t.n++
+	id := t.n
+	t.waiters[id] = t.clk.AfterFunc(d, func() {
+		t.wmu.Lock()
+		delete(t.waiters, id)
+		t.wmu.Unlock()
+		fn()
+	})
+}
+
+// stop stops the waiters on clk, for good, at the end of the test.
+func (t *toFacebookgo) stop() {
+	t.wmu.Lock()
+	defer t.wmu.Unlock()
+	t.stopped = true
+	for id, w := range t.waiters {
+		w.Stop()
+		delete(t.waiters, id)
+	}
+}
+
+func (t *toFacebookgo) After(d time.Duration) <-chan time.Time {
+	return t.clk.After(d)
+}
+
+func (t *toFacebookgo) AfterFunc(d time.Duration, f func()) *fbclock.Timer {
+	t.sync()
+	ft := t.m.AfterFunc(d, f)
+	t.syncAfter(d)
+	return ft
+}
+
+func (t *toFacebookgo) Now() time.Time {
+	return t.clk.Now()
+}
+
+func (t *toFacebookgo) Sleep(d time.Duration) {
+	t.clk.Sleep(d)
+}
+
+func (t *toFacebookgo) Tick(d time.Duration) <-chan time.Time {
+	return t.clk.Tick(d)
+}
+
+func (t *toFacebookgo) Ticker(d time.Duration) *fbclock.Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker")
+	}
+	t.sync()
+	ft := t.m.Ticker(d)
+	var tick func()
+	tick = func() {
+		go t.sync()
+		t.after(d, tick)
+	}
+	t.after(d, tick)
+	return ft
+}
+
+func (t *toFacebookgo) Timer(d time.Duration) *fbclock.Timer {
+	t.sync()
+	ft := t.m.Timer(d)
+	t.syncAfter(d)
+	return ft
+}
+
+// FromFacebookgo returns a clock.Clock backed by c, such as a
+// facebookgo Mock. Its NowMonotonic is the time that has passed on c
+// since FromFacebookgo was called. If c was returned by ToFacebookgo,
+// the clock.Clock it was made from is returned.
+//
+// facebookgo Timers can't be reset and don't report whether Stop
+// stopped them, and its Tickers can't be reset, so the Timers and
+// Tickers it returns are built on c's AfterFunc instead. Unlike those
+// of facebookgo's Mock, their channels hold one value, as the time
+// package's do, so moving a Mock doesn't wait for them to be received.
+func FromFacebookgo(c fbclock.Clock) clock.Clock {
+	if t, ok := c.(*toFacebookgo); ok {
+		return t.clk
+	}
+	return &fromFacebookgo{c: c, Monotonic: clock.NewMonotonic(c.Now)}
+}
+
+type fromFacebookgo struct {
+	c fbclock.Clock
+	clock.Monotonic
+}
+
+func (f *fromFacebookgo) Now() time.Time {
+	return f.c.Now()
+}
+
+func (f *fromFacebookgo) NowUnix() int64 {
+	return f.c.Now().Unix()
+}
+
+func (f *fromFacebookgo) NowUnixMilli() int64 {
+	return f.c.Now().UnixMilli()
+}
+
+func (f *fromFacebookgo) NowUnixNano() int64 {
+	return f.c.Now().UnixNano()
+}
+
+func (f *fromFacebookgo) Since(t time.Time) time.Duration {
+	return f.c.Now().Sub(t)
+}
+
+func (f *fromFacebookgo) Until(t time.Time) time.Duration {
+	return t.Sub(f.c.Now())
+}
+
+func (f *fromFacebookgo) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-f.NewTimer(d).C()
+}
+
+func (f *fromFacebookgo) SleepUntil(t time.Time) {
+	f.Sleep(f.Until(t))
+}
+
+func (f *fromFacebookgo) SleepContext(ctx context.Context, d time.Duration) error {
+	return clock.SleepContextWith(ctx, d, f.NewTimer)
+}
+
+func (f *fromFacebookgo) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *fromFacebookgo) AfterAt(t time.Time) <-chan time.Time {
+	return f.NewTimer(f.Until(t)).C()
+}
+
+func (f *fromFacebookgo) NewTimer(d time.Duration) clock.Timer {
+	t := &timer{c: f.c, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+func (f *fromFacebookgo) NewTimerAt(t time.Time) clock.Timer {
+	return f.NewTimer(f.Until(t))
+}
+
+func (f *fromFacebookgo) AfterFunc(d time.Duration, fn func()) clock.Timer {
+	t := &timer{c: f.c, fn: fn}
+	t.Reset(d)
+	return t
+}
+
+func (f *fromFacebookgo) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	t := &timer{c: f.c, ch: make(chan time.Time, 1), period: d}
+	t.Reset(d)
+	return ticker{t}
+}
+
+func (f *fromFacebookgo) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return f.NewTicker(d).C()
+}
+
+// timer is a Timer, AfterFunc function or Ticker built on a facebookgo
+// Clock's AfterFunc, which is set again after each tick for Tickers.
+type timer struct {
+	c      fbclock.Clock
+	ch     chan time.Time // nil for AfterFunc
+	fn     func()         // nil for Timers and Tickers
+	period time.Duration  // zero for Timers and AfterFunc
+
+	mu     sync.Mutex
+	t      *fbclock.Timer // nil until first set
+	gen    int            // incremented each time t is set or stopped
+	active bool
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.ch
+}
+
+// fire is called by the facebookgo Clock when the timer set as gen
+// fires.
+func (t *timer) fire(gen int) {
+	t.mu.Lock()
+	if t.gen != gen || !t.active {
+		t.mu.Unlock()
+		return
+	}
+	if t.ch != nil {
+		select {
+		case t.ch <- t.c.Now():
+		default:
+		}
+	}
+	if t.period > 0 {
+		t.set(t.period)
+	} else {
+		t.active = false
+	}
+	t.mu.Unlock()
+	if t.fn != nil {
+		t.fn()
+	}
+}
+
+// set sets t to fire once d has passed. t.mu must be held.
+func (t *timer) set(d time.Duration) {
+	t.gen++
+	gen := t.gen
+	t.active = true
+	t.t = t.c.AfterFunc(d, func() { t.fire(gen) })
+}
+
+// stop stops t, reporting whether it was active. It drains t's channel,
+// so that no stale time is received after Stop or Reset. t.mu must be
+// held.
+func (t *timer) stop() bool {
+	t.gen++
+	if t.t != nil {
+		t.t.Stop()
+	}
+	if t.ch != nil {
+		select {
+		case <-t.ch:
+		default:
+		}
+	}
+	wasActive := t.active
+	t.active = false
+	return wasActive
+}
+
+func (t *timer) Stop() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stop()
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	wasActive := t.stop()
+	t.set(d)
+	return wasActive
+}
+
+// ticker is a timer with a period as a clock.Ticker.
+type ticker struct {
+	t *timer
+}
+
+func (t ticker) C() <-chan time.Time {
+	return t.t.ch
+}
+
+func (t ticker) Stop() {
+	t.t.Stop()
+}
+
+func (t ticker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	t.t.mu.Lock()
+	defer t.t.mu.Unlock()
+	t.t.stop()
+	t.t.period = d
+	t.t.set(d)
+}
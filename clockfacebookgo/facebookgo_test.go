@@ -0,0 +1,98 @@
+package clockfacebookgo
+
+import (
+	"testing"
+	"time"
+
+	fbclock "github.com/facebookgo/clock"
+	"github.com/jmhodges/clock"
+)
+
+func TestToFacebookgo(t *testing.T) {
+	fc := clock.NewFake(clock.WithLeakCheck(t))
+	c := ToFacebookgoForTest(t, fc)
+	if FromFacebookgo(c) != clock.Clock(fc) {
+		t.Errorf("FromFacebookgo(ToFacebookgo(fc)) is not fc")
+	}
+	start := fc.Now()
+	tm := c.Timer(time.Second)
+	tk := c.Ticker(time.Minute)
+	defer tk.Stop()
+	fired := make(chan struct{})
+	c.AfterFunc(time.Hour, func() { close(fired) })
+
+	fc.Add(time.Second)
+	if got, want := <-tm.C, start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	fc.Add(time.Minute)
+	if got, want := <-tk.C, start.Add(time.Minute); !got.Equal(want) {
+		t.Errorf("Ticker sent %v, want %v", got, want)
+	}
+	fc.Set(start.Add(time.Hour))
+	<-fired
+	if got := c.Now(); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("Now() = %v, want %v", got, start.Add(time.Hour))
+	}
+}
+
+func TestToFacebookgoCleanup(t *testing.T) {
+	fc := clock.NewFake()
+	t.Run("test", func(t *testing.T) {
+		c := ToFacebookgoForTest(t, fc)
+		c.Ticker(time.Second).Stop()
+		c.Timer(time.Hour).Stop()
+		fc.Add(time.Minute)
+	})
+	if n := fc.Waiters(); n != 0 {
+		t.Errorf("%d waiters left on the FakeClock after the test, want 0", n)
+	}
+}
+
+func TestToFacebookgoWrapped(t *testing.T) {
+	// A Clock wrapping the real one needs no test.
+	c := ToFacebookgo(clock.Offset(clock.Default(), time.Hour))
+	if d := c.Now().Sub(clock.Default().Now()); d < time.Hour-time.Minute || d > time.Hour+time.Minute {
+		t.Errorf("Now() is %v from clock.Default().Now(), want about %v", d, time.Hour)
+	}
+	<-c.Timer(time.Millisecond).C
+}
+
+func TestFromFacebookgo(t *testing.T) {
+	m := fbclock.NewMock()
+	clk := FromFacebookgo(m)
+	if ToFacebookgo(clk) != fbclock.Clock(m) {
+		t.Errorf("ToFacebookgo(FromFacebookgo(m)) is not m")
+	}
+	start := clk.Now()
+	tm := clk.NewTimerAt(start.Add(time.Second))
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+	m.Add(time.Second)
+	if got, want := <-tm.C(), start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got := clk.NowMonotonic(); got != time.Second {
+		t.Errorf("NowMonotonic() = %v, want %v", got, time.Second)
+	}
+	if tm.Stop() {
+		t.Errorf("Stop of a fired Timer returned true")
+	}
+	if tm.Reset(time.Second) {
+		t.Errorf("Reset of a stopped Timer returned true")
+	}
+
+	// A Ticker whose tick hasn't been received drops the ticks after it.
+	m.Add(2 * time.Second)
+	if got, want := <-tk.C(), start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Ticker sent %v, want %v", got, want)
+	}
+	if got, want := <-tm.C(), start.Add(2*time.Second); !got.Equal(want) {
+		t.Errorf("Reset Timer sent %v, want %v", got, want)
+	}
+	tk.Reset(time.Minute)
+	m.Add(time.Minute)
+	if got, want := <-tk.C(), start.Add(time.Minute+3*time.Second); !got.Equal(want) {
+		t.Errorf("Reset Ticker sent %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+// Package clockgomega runs Gomega's Eventually and Consistently on a
+// clock.FakeClock's time, moving the clock forward between polls
+// instead of sleeping, so that suites testing time-based behavior don't
+// spend real seconds on each assertion.
+package clockgomega
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+)
+
+// Eventually is like g.Eventually(actual), but polls actual, which
+// must be a function, on fc's time: it is called at once and then
+// again each time fc has been moved forward by interval, until it
+// satisfies the matcher or more than timeout would have passed on fc,
+// at which point the assertion fails. Polls are not spaced out in real
+// time, so the returned assertion should not be given a timeout or
+// polling interval of its own.
+func Eventually(g types.Gomega, fc clock.FakeClock, actual any, timeout, interval time.Duration) types.AsyncAssertion {
+	return g.Eventually(poll(fc, actual, timeout, interval, false)).
+		WithTimeout(math.MaxInt64).
+		WithPolling(0)
+}
+
+// Consistently is like g.Consistently(actual), but polls actual, which
+// must be a function, on fc's time as Eventually does. The assertion
+// passes if actual keeps satisfying the matcher until more than timeout
+// would have passed on fc.
+func Consistently(g types.Gomega, fc clock.FakeClock, actual any, timeout, interval time.Duration) types.AsyncAssertion {
+	return g.Consistently(poll(fc, actual, timeout, interval, true)).
+		WithTimeout(math.MaxInt64).
+		WithPolling(0)
+}
+
+// poll returns a function of the same type as actual that, except on
+// its first call, moves fc forward by interval before calling actual,
+// and that stops the assertion polling it, successfully if successful
+// is true, once the next poll would be more than timeout after the
+// first.
+func poll(fc clock.FakeClock, actual any, timeout, interval time.Duration, successful bool) any {
+	if interval <= 0 {
+		panic("clock: non-positive interval for polling")
+	}
+	v := reflect.ValueOf(actual)
+	if v.Kind() != reflect.Func {
+		panic(fmt.Sprintf("clock: polling needs a function, not %T", actual))
+	}
+	var start time.Time
+	first := true
+	return reflect.MakeFunc(v.Type(), func(args []reflect.Value) []reflect.Value {
+		if first {
+			start = fc.Now()
+			first = false
+		} else {
+			if fc.Since(start)+interval > timeout {
+				stop := gomega.StopTrying(fmt.Sprintf("Passed %v on the FakeClock", timeout))
+				if successful {
+					stop = stop.Successfully()
+				}
+				stop.Now()
+			}
+			fc.Add(interval)
+		}
+		if v.Type().IsVariadic() {
+			return v.CallSlice(args)
+		}
+		return v.Call(args)
+	}).Interface()
+}
@@ -0,0 +1,59 @@
+package clockgomega
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/onsi/gomega"
+)
+
+func TestEventually(t *testing.T) {
+	fc := clock.NewFake()
+	deadline := fc.Now().Add(10 * time.Second)
+	g := gomega.NewWithT(t)
+	start := time.Now()
+	Eventually(g, fc, func() bool { return !fc.Now().Before(deadline) }, time.Minute, time.Second).Should(gomega.BeTrue())
+	if got := fc.Now(); !got.Equal(deadline) {
+		t.Errorf("clock is at %v, want %v", got, deadline)
+	}
+	if real := time.Since(start); real > 10*time.Second {
+		t.Errorf("Eventually took %v of real time", real)
+	}
+
+	var failure string
+	g2 := gomega.NewGomega(func(message string, _ ...int) { failure = message })
+	fc = clock.NewFake()
+	begin := fc.Now()
+	Eventually(g2, fc, func() int { return 0 }, time.Minute, 10*time.Second).Should(gomega.Equal(1))
+	if !strings.Contains(failure, "Told to stop trying") {
+		t.Errorf("failure = %q, want it to say Eventually stopped trying", failure)
+	}
+	if got := fc.Since(begin); got != time.Minute {
+		t.Errorf("Eventually gave up after %v on the clock, want %v", got, time.Minute)
+	}
+}
+
+func TestConsistently(t *testing.T) {
+	fc := clock.NewFake()
+	begin := fc.Now()
+	calls := 0
+	g := gomega.NewWithT(t)
+	Consistently(g, fc, func(g gomega.Gomega) {
+		calls++
+		g.Expect(fc.Since(begin)).To(gomega.BeNumerically("<=", time.Minute))
+	}, time.Minute, 10*time.Second).Should(gomega.Succeed())
+	if calls != 7 {
+		t.Errorf("polled %d times, want 7", calls)
+	}
+
+	var failure string
+	g2 := gomega.NewGomega(func(message string, _ ...int) { failure = message })
+	fc = clock.NewFake()
+	deadline := fc.Now().Add(30 * time.Second)
+	Consistently(g2, fc, func() bool { return fc.Now().Before(deadline) }, time.Minute, 10*time.Second).Should(gomega.BeTrue())
+	if failure == "" {
+		t.Errorf("Consistently passed for a condition that stopped holding")
+	}
+}
@@ -0,0 +1,125 @@
+// Package clockgrpc provides gRPC interceptors that carry a clock.Clock
+// in the Contexts of the calls they intercept.
+//
+// The server interceptors put the Clock in the Context given to
+// handlers, for them to get with clock.FromContext. gRPC sends a call's
+// deadline as the time remaining until it, measured by the system
+// clock, so when servers and clients use a FakeClock in tests, the
+// interceptors can translate deadlines between the fake time and the
+// system's.
+package clockgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"google.golang.org/grpc"
+)
+
+// Option configures the server interceptors.
+type Option func(*options)
+
+type options struct {
+	translate bool
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithDeadlineTranslation makes the server interceptors give handlers a
+// Context whose deadline is in the Clock's time, the same time away on
+// the Clock as the call's deadline is on the system clock, and which
+// expires once the Clock reaches it, as with clock.WithDeadline. The
+// call's own deadline still applies, too.
+func WithDeadlineTranslation() Option {
+	return func(o *options) {
+		o.translate = true
+	}
+}
+
+// serverContext returns the Context to give a handler of a call whose
+// Context is ctx.
+func (o options) serverContext(ctx context.Context, clk clock.Clock) (context.Context, context.CancelFunc) {
+	ctx = clock.NewContext(ctx, clk)
+	if d, ok := ctx.Deadline(); ok && o.translate {
+		return clock.WithTimeout(ctx, clk, time.Until(d))
+	}
+	return ctx, func() {}
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor for unary calls
+// that carries clk in the Context given to their handlers.
+func UnaryServerInterceptor(clk clock.Clock, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := o.serverContext(ctx, clk)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor for streaming
+// calls that carries clk in the Context of the streams given to their
+// handlers.
+func StreamServerInterceptor(clk clock.Clock, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := o.serverContext(ss.Context(), clk)
+		defer cancel()
+		return handler(srv, serverStream{ss, ctx})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// clientContext returns the Context to make a call with in place of
+// ctx, whose deadline, if any, is in clk's time.
+func clientContext(ctx context.Context, clk clock.Clock) context.Context {
+	if d, ok := ctx.Deadline(); ok {
+		return systemDeadline{ctx, time.Now().Add(clk.Until(d))}
+	}
+	return ctx
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor for unary calls
+// made with Contexts whose deadlines are in clk's time, such as those
+// made by clock.WithDeadline. It sends the server the time remaining
+// until the deadline on clk, rather than on the system clock. The
+// calls still end once the Contexts expire.
+func UnaryClientInterceptor(clk clock.Clock) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(clientContext(ctx, clk), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor is like UnaryClientInterceptor, but for
+// streaming calls.
+func StreamClientInterceptor(clk clock.Clock) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(clientContext(ctx, clk), desc, cc, method, opts...)
+	}
+}
+
+// systemDeadline is a Context whose Deadline is replaced by one on the
+// system clock.
+type systemDeadline struct {
+	context.Context
+	deadline time.Time
+}
+
+func (c systemDeadline) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
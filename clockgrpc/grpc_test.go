@@ -0,0 +1,81 @@
+package clockgrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"google.golang.org/grpc"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	fc := clock.NewFake()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	for _, translate := range []bool{false, true} {
+		var opts []Option
+		if translate {
+			opts = append(opts, WithDeadlineTranslation())
+		}
+		intercept := UnaryServerInterceptor(fc, opts...)
+		_, err := intercept(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+			if clock.FromContext(ctx) != fc {
+				t.Errorf("handler's Context does not carry the Clock")
+			}
+			d, _ := ctx.Deadline()
+			if left := fc.Until(d); translate && (left <= 59*time.Minute || left > time.Hour) {
+				t.Errorf("translated deadline is %v away on the Clock, want about an hour", left)
+			}
+			if left := time.Until(d); !translate && (left <= 59*time.Minute || left > time.Hour) {
+				t.Errorf("untranslated deadline is %v away, want about an hour", left)
+			}
+			return nil, nil
+		})
+		if err != nil {
+			t.Errorf("interceptor returned %v", err)
+		}
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	fc := clock.NewFake()
+	intercept := StreamServerInterceptor(fc)
+	ss := fakeServerStream{ctx: context.Background()}
+	err := intercept(nil, ss, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+		if clock.FromContext(ss.Context()) != fc {
+			t.Errorf("handler's stream's Context does not carry the Clock")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("interceptor returned %v", err)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	fc := clock.NewFake()
+	ctx, cancel := clock.WithTimeout(context.Background(), fc, time.Minute)
+	defer cancel()
+	intercept := UnaryClientInterceptor(fc)
+	err := intercept(ctx, "/svc/Method", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		d, ok := ctx.Deadline()
+		if left := time.Until(d); !ok || left <= 59*time.Second || left > time.Minute {
+			t.Errorf("deadline sent is %v away on the system clock, want about a minute", left)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("interceptor returned %v", err)
+	}
+}
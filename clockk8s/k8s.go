@@ -0,0 +1,226 @@
+// Package clockk8s converts between clock.Clock and the clocks of
+// k8s.io/utils/clock, so that one clock, and in tests one FakeClock, can
+// drive both a controller's own code and the Kubernetes libraries it
+// uses.
+package clockk8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	k8sclock "k8s.io/utils/clock"
+)
+
+// ToKubernetes returns a Kubernetes clock backed by clk. It satisfies
+// k8sclock.Clock, WithTicker and WithDelayedExecution. If clk was
+// returned by FromKubernetes, the clock it was made from is returned.
+func ToKubernetes(clk clock.Clock) k8sclock.WithTickerAndDelayedExecution {
+	if f, ok := clk.(*fromKubernetes); ok {
+		return f.c
+	}
+	return toKubernetes{clk}
+}
+
+type toKubernetes struct {
+	clk clock.Clock
+}
+
+func (t toKubernetes) Now() time.Time {
+	return t.clk.Now()
+}
+
+func (t toKubernetes) Since(u time.Time) time.Duration {
+	return t.clk.Since(u)
+}
+
+func (t toKubernetes) After(d time.Duration) <-chan time.Time {
+	return t.clk.After(d)
+}
+
+func (t toKubernetes) NewTimer(d time.Duration) k8sclock.Timer {
+	return t.clk.NewTimer(d)
+}
+
+func (t toKubernetes) Sleep(d time.Duration) {
+	t.clk.Sleep(d)
+}
+
+func (t toKubernetes) Tick(d time.Duration) <-chan time.Time {
+	return t.clk.Tick(d)
+}
+
+func (t toKubernetes) NewTicker(d time.Duration) k8sclock.Ticker {
+	return t.clk.NewTicker(d)
+}
+
+func (t toKubernetes) AfterFunc(d time.Duration, f func()) k8sclock.Timer {
+	return t.clk.AfterFunc(d, f)
+}
+
+// FromKubernetes returns a clock.Clock backed by c, such as a
+// k8s.io/utils/clock/testing FakeClock. Its NowMonotonic is the time
+// that has passed on c since FromKubernetes was called. If c was
+// returned by ToKubernetes, the clock.Clock it was made from is
+// returned.
+//
+// Kubernetes Tickers can't be reset, so the Tickers it returns forward
+// the ticks of one to their own channel from a goroutine and replace it
+// on Reset. Ticks therefore reach C shortly after c sends them rather
+// than by the time a FakeClock's Step returns.
+func FromKubernetes(c k8sclock.WithTickerAndDelayedExecution) clock.Clock {
+	if t, ok := c.(toKubernetes); ok {
+		return t.clk
+	}
+	return &fromKubernetes{c: c, Monotonic: clock.NewMonotonic(c.Now)}
+}
+
+type fromKubernetes struct {
+	c k8sclock.WithTickerAndDelayedExecution
+	clock.Monotonic
+}
+
+func (f *fromKubernetes) Now() time.Time {
+	return f.c.Now()
+}
+
+func (f *fromKubernetes) NowUnix() int64 {
+	return f.c.Now().Unix()
+}
+
+func (f *fromKubernetes) NowUnixMilli() int64 {
+	return f.c.Now().UnixMilli()
+}
+
+func (f *fromKubernetes) NowUnixNano() int64 {
+	return f.c.Now().UnixNano()
+}
+
+func (f *fromKubernetes) Since(t time.Time) time.Duration {
+	return f.c.Since(t)
+}
+
+func (f *fromKubernetes) Until(t time.Time) time.Duration {
+	return t.Sub(f.c.Now())
+}
+
+func (f *fromKubernetes) Sleep(d time.Duration) {
+	f.c.Sleep(d)
+}
+
+func (f *fromKubernetes) SleepUntil(t time.Time) {
+	f.c.Sleep(f.Until(t))
+}
+
+func (f *fromKubernetes) SleepContext(ctx context.Context, d time.Duration) error {
+	return clock.SleepContextWith(ctx, d, f.NewTimer)
+}
+
+func (f *fromKubernetes) After(d time.Duration) <-chan time.Time {
+	return f.c.After(d)
+}
+
+func (f *fromKubernetes) AfterAt(t time.Time) <-chan time.Time {
+	return f.c.After(f.Until(t))
+}
+
+func (f *fromKubernetes) NewTimer(d time.Duration) clock.Timer {
+	return f.c.NewTimer(d)
+}
+
+func (f *fromKubernetes) NewTimerAt(t time.Time) clock.Timer {
+	return f.c.NewTimer(f.Until(t))
+}
+
+func (f *fromKubernetes) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	t := &ticker{c: f.c, ch: make(chan time.Time, 1)}
+	t.start(d)
+	return t
+}
+
+func (f *fromKubernetes) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return f.c.Tick(d)
+}
+
+// AfterFunc calls fn in its own goroutine, since a FakeClock calls its
+// AfterFunc functions while holding its lock.
+func (f *fromKubernetes) AfterFunc(d time.Duration, fn func()) clock.Timer {
+	return f.c.AfterFunc(d, func() { go fn() })
+}
+
+// ticker is a clock.Ticker forwarding the ticks of a Kubernetes Ticker,
+// which is replaced on Reset.
+type ticker struct {
+	c  k8sclock.WithTicker
+	ch chan time.Time
+
+	mu   sync.Mutex
+	stop chan struct{} // closed to stop the current forwarding goroutine
+	done chan struct{} // closed once it has returned
+}
+
+// start begins forwarding from a new Kubernetes Ticker with period d.
+// t.mu must be held, or t not yet shared.
+func (t *ticker) start(d time.Duration) {
+	kt := t.c.NewTicker(d)
+	stop, done := make(chan struct{}), make(chan struct{})
+	t.stop, t.done = stop, done
+	go func() {
+		defer close(done)
+		defer kt.Stop()
+		for {
+			select {
+			case now := <-kt.C():
+				select {
+				case t.ch <- now:
+				default:
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *ticker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.halt()
+}
+
+// halt stops the forwarding goroutine, if there is one, waits for it to
+// return and then drains t.ch, so that no tick from before Stop or
+// Reset is received after, as with a time.Ticker. t.mu must be held.
+func (t *ticker) halt() {
+	if t.stop == nil {
+		return
+	}
+	close(t.stop)
+	<-t.done
+	t.stop, t.done = nil, nil
+	select {
+	case <-t.ch:
+	default:
+	}
+}
+
+func (t *ticker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.halt()
+	t.start(d)
+}
@@ -0,0 +1,95 @@
+package clockk8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	k8sclock "k8s.io/utils/clock"
+	k8stesting "k8s.io/utils/clock/testing"
+)
+
+func TestToKubernetes(t *testing.T) {
+	fc := clock.NewFake()
+	kc := ToKubernetes(fc)
+	if FromKubernetes(kc) != clock.Clock(fc) {
+		t.Errorf("FromKubernetes(ToKubernetes(fc)) is not fc")
+	}
+	tm := kc.NewTimer(time.Second)
+	tk := kc.NewTicker(time.Minute)
+	defer tk.Stop()
+	fc.Add(time.Minute)
+	if got, want := <-tm.C(), fc.Now().Add(-59*time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got := <-tk.C(); !got.Equal(fc.Now()) {
+		t.Errorf("Ticker sent %v, want %v", got, fc.Now())
+	}
+	if got := kc.Since(fc.Now().Add(-time.Hour)); got != time.Hour {
+		t.Errorf("Since = %v, want %v", got, time.Hour)
+	}
+}
+
+func TestFromKubernetes(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	kf := k8stesting.NewFakeClock(start)
+	clk := FromKubernetes(kf)
+	if ToKubernetes(clk) != k8sclock.WithTickerAndDelayedExecution(kf) {
+		t.Errorf("ToKubernetes(FromKubernetes(kf)) is not kf")
+	}
+	tm := clk.NewTimerAt(start.Add(time.Second))
+	fired := make(chan time.Time)
+	clk.AfterFunc(time.Second, func() { fired <- clk.Now() })
+	kf.Step(time.Second)
+	if got, want := <-tm.C(), start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got, want := <-fired, start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("AfterFunc saw %v, want %v", got, want)
+	}
+	if got := clk.NowMonotonic(); got != time.Second {
+		t.Errorf("NowMonotonic() = %v, want %v", got, time.Second)
+	}
+	if got, want := clk.Until(start.Add(time.Minute)), 59*time.Second; got != want {
+		t.Errorf("Until = %v, want %v", got, want)
+	}
+}
+
+func TestFromKubernetesTicker(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	kf := k8stesting.NewFakeClock(start)
+	clk := FromKubernetes(kf)
+	tk := clk.NewTicker(time.Second)
+	kf.Step(time.Second)
+	if got, want := <-tk.C(), start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Ticker sent %v, want %v", got, want)
+	}
+	tk.Stop()
+	tk.Reset(time.Minute)
+	defer tk.Stop()
+	kf.Step(time.Minute)
+	if got, want := <-tk.C(), start.Add(time.Minute+time.Second); !got.Equal(want) {
+		t.Errorf("Reset Ticker sent %v, want %v", got, want)
+	}
+}
+
+func TestFromKubernetesTickerStopDrains(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	kf := k8stesting.NewFakeClock(start)
+	tk := FromKubernetes(kf).NewTicker(time.Second)
+	defer tk.Stop()
+	for _, stop := range []func(){tk.Stop, func() { tk.Reset(time.Minute) }} {
+		tk.Reset(time.Second)
+		kf.Step(time.Second)
+		// Wait for the tick to be forwarded.
+		for len(tk.(*ticker).ch) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		stop()
+		select {
+		case got := <-tk.C():
+			t.Errorf("Ticker sent %v from before Stop or Reset", got)
+		default:
+		}
+	}
+}
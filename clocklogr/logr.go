@@ -0,0 +1,83 @@
+// Package clocklogr adds timestamps read from a clock.Clock to the lines
+// logged through a github.com/go-logr/logr Logger, so that loggers such
+// as funcr, which otherwise stamp lines with the system's time, log fake
+// times in tests.
+package clocklogr
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/jmhodges/clock"
+)
+
+// DefaultLayout is the layout WithTimestamps uses when given an empty
+// one. It is the one funcr uses for its own timestamps.
+const DefaultLayout = "2006-01-02 15:04:05.000000"
+
+// WithTimestamps returns a Logger that logs through log, adding to each
+// line a "ts" key whose value is clk's time formatted with layout. log
+// should not add timestamps of its own, for example by having funcr's
+// LogTimestamp option unset. The caller log reports, if it reports one,
+// is the caller of the returned Logger.
+func WithTimestamps(log logr.Logger, clk clock.Clock, layout string) logr.Logger {
+	if layout == "" {
+		layout = DefaultLayout
+	}
+	s := log.GetSink()
+	if s == nil {
+		return log
+	}
+	if cd, ok := s.(logr.CallDepthLogSink); ok {
+		s = cd.WithCallDepth(1)
+	}
+	return logr.New(&sink{s: s, clk: clk, layout: layout})
+}
+
+// sink is a LogSink adding timestamps to the lines it passes to s.
+type sink struct {
+	s      logr.LogSink
+	clk    clock.Clock
+	layout string
+}
+
+// Init does nothing, as s was initialized by the Logger it came from
+// and given the frame added by sink through WithCallDepth.
+func (l *sink) Init(logr.RuntimeInfo) {}
+
+func (l *sink) Enabled(level int) bool {
+	return l.s.Enabled(level)
+}
+
+func (l *sink) Info(level int, msg string, keysAndValues ...any) {
+	l.s.Info(level, msg, l.stamp(keysAndValues)...)
+}
+
+func (l *sink) Error(err error, msg string, keysAndValues ...any) {
+	l.s.Error(err, msg, l.stamp(keysAndValues)...)
+}
+
+func (l *sink) stamp(keysAndValues []any) []any {
+	return append([]any{"ts", l.clk.Now().Format(l.layout)}, keysAndValues...)
+}
+
+func (l *sink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &sink{s: l.s.WithValues(keysAndValues...), clk: l.clk, layout: l.layout}
+}
+
+func (l *sink) WithName(name string) logr.LogSink {
+	return &sink{s: l.s.WithName(name), clk: l.clk, layout: l.layout}
+}
+
+func (l *sink) WithCallDepth(depth int) logr.LogSink {
+	cd, ok := l.s.(logr.CallDepthLogSink)
+	if !ok {
+		return l
+	}
+	return &sink{s: cd.WithCallDepth(depth), clk: l.clk, layout: l.layout}
+}
+
+func (l *sink) GetCallStackHelper() func() {
+	if h, ok := l.s.(logr.CallStackHelperLogSink); ok {
+		return h.GetCallStackHelper()
+	}
+	return func() {}
+}
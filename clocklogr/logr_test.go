@@ -0,0 +1,39 @@
+package clocklogr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/jmhodges/clock"
+)
+
+func TestWithTimestamps(t *testing.T) {
+	fc := clock.NewFake()
+	fc.Set(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	var lines []string
+	base := funcr.New(func(prefix, args string) {
+		lines = append(lines, prefix+" "+args)
+	}, funcr.Options{LogCaller: funcr.All})
+	log := WithTimestamps(base, fc, "")
+
+	log.WithName("n").WithValues("a", 1).Info("hello", "b", 2)
+	fc.Add(1500 * time.Millisecond)
+	log.Error(errors.New("boom"), "failed")
+	WithTimestamps(logr.Discard(), fc, time.RFC3339).Info("discarded")
+
+	want := []string{
+		`n "caller"={"file"="logr_test.go" "line"=22} "level"=0 "msg"="hello" "a"=1 "ts"="2020-01-01 00:00:00.000000" "b"=2`,
+		` "caller"={"file"="logr_test.go" "line"=24} "msg"="failed" "error"="boom" "ts"="2020-01-01 00:00:01.500000"`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("logged %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %s, want %s", i, lines[i], want[i])
+		}
+	}
+}
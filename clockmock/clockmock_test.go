@@ -0,0 +1,53 @@
+package clockmock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	_ clock.Clock  = (*MockClock)(nil)
+	_ clock.Timer  = (*MockTimer)(nil)
+	_ clock.Ticker = (*MockTicker)(nil)
+)
+
+func TestMockClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	clk := NewMockClock(ctrl)
+	tm := NewMockTimer(ctrl)
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk.EXPECT().Now().Return(at)
+	clk.EXPECT().NewTimer(time.Second).Return(tm)
+	tm.EXPECT().Stop().Return(true)
+
+	var c clock.Clock = clk
+	if got := c.Now(); !got.Equal(at) {
+		t.Errorf("Now() = %v, want %v", got, at)
+	}
+	if !c.NewTimer(time.Second).Stop() {
+		t.Errorf("Stop() = false, want true")
+	}
+}
+
+func TestTickerMock(t *testing.T) {
+	ch := make(chan time.Time)
+	tk := &TickerMock{
+		CFunc:     func() <-chan time.Time { return ch },
+		ResetFunc: func(d time.Duration) {},
+	}
+
+	var c clock.Ticker = tk
+	if c.C() != ch {
+		t.Errorf("C() did not return the mocked channel")
+	}
+	c.Reset(time.Minute)
+	if calls := tk.ResetCalls(); len(calls) != 1 || calls[0].D != time.Minute {
+		t.Errorf("Reset calls = %+v, want one with %v", calls, time.Minute)
+	}
+	if n := len(tk.StopCalls()); n != 0 {
+		t.Errorf("Stop called %d times, want 0", n)
+	}
+}
@@ -0,0 +1,17 @@
+// Package clockmock provides generated expectation-style mocks of
+// clock.Clock, clock.Timer and clock.Ticker, so that they are made from
+// the interfaces in this module and keep up with them as they grow.
+//
+// MockClock, MockTimer and MockTicker are for go.uber.org/mock's gomock
+// and are made by mockgen. TimerMock and TickerMock are made by moq.
+// moq can't make a mock of Clock: it would name the field holding the
+// function for Clock's After method AfterFunc, which is also the name
+// of one of Clock's methods.
+//
+// Most tests are better off with a clock.FakeClock, whose time moves
+// only when the test moves it. These are for tests that need to check
+// exactly how code calls its Clock.
+package clockmock
+
+//go:generate mockgen -write_package_comment=false -destination=gomock.go -package=clockmock github.com/jmhodges/clock Clock,Timer,Ticker
+//go:generate moq -rm -out=moq.go -pkg=clockmock .. Timer Ticker
@@ -0,0 +1,404 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/jmhodges/clock (interfaces: Clock,Timer,Ticker)
+//
+// Generated by this command:
+//
+//	mockgen -write_package_comment=false -destination=gomock.go -package=clockmock github.com/jmhodges/clock Clock,Timer,Ticker
+//
+
+package clockmock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	clock "github.com/jmhodges/clock"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClock is a mock of Clock interface.
+type MockClock struct {
+	ctrl     *gomock.Controller
+	recorder *MockClockMockRecorder
+	isgomock struct{}
+}
+
+// MockClockMockRecorder is the mock recorder for MockClock.
+type MockClockMockRecorder struct {
+	mock *MockClock
+}
+
+// NewMockClock creates a new mock instance.
+func NewMockClock(ctrl *gomock.Controller) *MockClock {
+	mock := &MockClock{ctrl: ctrl}
+	mock.recorder = &MockClockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClock) EXPECT() *MockClockMockRecorder {
+	return m.recorder
+}
+
+// After mocks base method.
+func (m *MockClock) After(d time.Duration) <-chan time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "After", d)
+	ret0, _ := ret[0].(<-chan time.Time)
+	return ret0
+}
+
+// After indicates an expected call of After.
+func (mr *MockClockMockRecorder) After(d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "After", reflect.TypeOf((*MockClock)(nil).After), d)
+}
+
+// AfterAt mocks base method.
+func (m *MockClock) AfterAt(t time.Time) <-chan time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AfterAt", t)
+	ret0, _ := ret[0].(<-chan time.Time)
+	return ret0
+}
+
+// AfterAt indicates an expected call of AfterAt.
+func (mr *MockClockMockRecorder) AfterAt(t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterAt", reflect.TypeOf((*MockClock)(nil).AfterAt), t)
+}
+
+// AfterFunc mocks base method.
+func (m *MockClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AfterFunc", d, f)
+	ret0, _ := ret[0].(clock.Timer)
+	return ret0
+}
+
+// AfterFunc indicates an expected call of AfterFunc.
+func (mr *MockClockMockRecorder) AfterFunc(d, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterFunc", reflect.TypeOf((*MockClock)(nil).AfterFunc), d, f)
+}
+
+// NewTicker mocks base method.
+func (m *MockClock) NewTicker(d time.Duration) clock.Ticker {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewTicker", d)
+	ret0, _ := ret[0].(clock.Ticker)
+	return ret0
+}
+
+// NewTicker indicates an expected call of NewTicker.
+func (mr *MockClockMockRecorder) NewTicker(d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewTicker", reflect.TypeOf((*MockClock)(nil).NewTicker), d)
+}
+
+// NewTimer mocks base method.
+func (m *MockClock) NewTimer(d time.Duration) clock.Timer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewTimer", d)
+	ret0, _ := ret[0].(clock.Timer)
+	return ret0
+}
+
+// NewTimer indicates an expected call of NewTimer.
+func (mr *MockClockMockRecorder) NewTimer(d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewTimer", reflect.TypeOf((*MockClock)(nil).NewTimer), d)
+}
+
+// NewTimerAt mocks base method.
+func (m *MockClock) NewTimerAt(t time.Time) clock.Timer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewTimerAt", t)
+	ret0, _ := ret[0].(clock.Timer)
+	return ret0
+}
+
+// NewTimerAt indicates an expected call of NewTimerAt.
+func (mr *MockClockMockRecorder) NewTimerAt(t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewTimerAt", reflect.TypeOf((*MockClock)(nil).NewTimerAt), t)
+}
+
+// Now mocks base method.
+func (m *MockClock) Now() time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Now")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// Now indicates an expected call of Now.
+func (mr *MockClockMockRecorder) Now() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Now", reflect.TypeOf((*MockClock)(nil).Now))
+}
+
+// NowMonotonic mocks base method.
+func (m *MockClock) NowMonotonic() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NowMonotonic")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// NowMonotonic indicates an expected call of NowMonotonic.
+func (mr *MockClockMockRecorder) NowMonotonic() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NowMonotonic", reflect.TypeOf((*MockClock)(nil).NowMonotonic))
+}
+
+// NowUnix mocks base method.
+func (m *MockClock) NowUnix() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NowUnix")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// NowUnix indicates an expected call of NowUnix.
+func (mr *MockClockMockRecorder) NowUnix() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NowUnix", reflect.TypeOf((*MockClock)(nil).NowUnix))
+}
+
+// NowUnixMilli mocks base method.
+func (m *MockClock) NowUnixMilli() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NowUnixMilli")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// NowUnixMilli indicates an expected call of NowUnixMilli.
+func (mr *MockClockMockRecorder) NowUnixMilli() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NowUnixMilli", reflect.TypeOf((*MockClock)(nil).NowUnixMilli))
+}
+
+// NowUnixNano mocks base method.
+func (m *MockClock) NowUnixNano() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NowUnixNano")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// NowUnixNano indicates an expected call of NowUnixNano.
+func (mr *MockClockMockRecorder) NowUnixNano() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NowUnixNano", reflect.TypeOf((*MockClock)(nil).NowUnixNano))
+}
+
+// Since mocks base method.
+func (m *MockClock) Since(t time.Time) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Since", t)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Since indicates an expected call of Since.
+func (mr *MockClockMockRecorder) Since(t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Since", reflect.TypeOf((*MockClock)(nil).Since), t)
+}
+
+// Sleep mocks base method.
+func (m *MockClock) Sleep(d time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Sleep", d)
+}
+
+// Sleep indicates an expected call of Sleep.
+func (mr *MockClockMockRecorder) Sleep(d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sleep", reflect.TypeOf((*MockClock)(nil).Sleep), d)
+}
+
+// SleepContext mocks base method.
+func (m *MockClock) SleepContext(ctx context.Context, d time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SleepContext", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SleepContext indicates an expected call of SleepContext.
+func (mr *MockClockMockRecorder) SleepContext(ctx, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SleepContext", reflect.TypeOf((*MockClock)(nil).SleepContext), ctx, d)
+}
+
+// SleepUntil mocks base method.
+func (m *MockClock) SleepUntil(t time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SleepUntil", t)
+}
+
+// SleepUntil indicates an expected call of SleepUntil.
+func (mr *MockClockMockRecorder) SleepUntil(t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SleepUntil", reflect.TypeOf((*MockClock)(nil).SleepUntil), t)
+}
+
+// Tick mocks base method.
+func (m *MockClock) Tick(d time.Duration) <-chan time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tick", d)
+	ret0, _ := ret[0].(<-chan time.Time)
+	return ret0
+}
+
+// Tick indicates an expected call of Tick.
+func (mr *MockClockMockRecorder) Tick(d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tick", reflect.TypeOf((*MockClock)(nil).Tick), d)
+}
+
+// Until mocks base method.
+func (m *MockClock) Until(t time.Time) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Until", t)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Until indicates an expected call of Until.
+func (mr *MockClockMockRecorder) Until(t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Until", reflect.TypeOf((*MockClock)(nil).Until), t)
+}
+
+// MockTimer is a mock of Timer interface.
+type MockTimer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTimerMockRecorder
+	isgomock struct{}
+}
+
+// MockTimerMockRecorder is the mock recorder for MockTimer.
+type MockTimerMockRecorder struct {
+	mock *MockTimer
+}
+
+// NewMockTimer creates a new mock instance.
+func NewMockTimer(ctrl *gomock.Controller) *MockTimer {
+	mock := &MockTimer{ctrl: ctrl}
+	mock.recorder = &MockTimerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTimer) EXPECT() *MockTimerMockRecorder {
+	return m.recorder
+}
+
+// C mocks base method.
+func (m *MockTimer) C() <-chan time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "C")
+	ret0, _ := ret[0].(<-chan time.Time)
+	return ret0
+}
+
+// C indicates an expected call of C.
+func (mr *MockTimerMockRecorder) C() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "C", reflect.TypeOf((*MockTimer)(nil).C))
+}
+
+// Reset mocks base method.
+func (m *MockTimer) Reset(d time.Duration) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", d)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockTimerMockRecorder) Reset(d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockTimer)(nil).Reset), d)
+}
+
+// Stop mocks base method.
+func (m *MockTimer) Stop() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockTimerMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockTimer)(nil).Stop))
+}
+
+// MockTicker is a mock of Ticker interface.
+type MockTicker struct {
+	ctrl     *gomock.Controller
+	recorder *MockTickerMockRecorder
+	isgomock struct{}
+}
+
+// MockTickerMockRecorder is the mock recorder for MockTicker.
+type MockTickerMockRecorder struct {
+	mock *MockTicker
+}
+
+// NewMockTicker creates a new mock instance.
+func NewMockTicker(ctrl *gomock.Controller) *MockTicker {
+	mock := &MockTicker{ctrl: ctrl}
+	mock.recorder = &MockTickerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTicker) EXPECT() *MockTickerMockRecorder {
+	return m.recorder
+}
+
+// C mocks base method.
+func (m *MockTicker) C() <-chan time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "C")
+	ret0, _ := ret[0].(<-chan time.Time)
+	return ret0
+}
+
+// C indicates an expected call of C.
+func (mr *MockTickerMockRecorder) C() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "C", reflect.TypeOf((*MockTicker)(nil).C))
+}
+
+// Reset mocks base method.
+func (m *MockTicker) Reset(d time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reset", d)
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockTickerMockRecorder) Reset(d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockTicker)(nil).Reset), d)
+}
+
+// Stop mocks base method.
+func (m *MockTicker) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockTickerMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockTicker)(nil).Stop))
+}
@@ -0,0 +1,290 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package clockmock
+
+import (
+	"github.com/jmhodges/clock"
+	"sync"
+	"time"
+)
+
+// Ensure, that TimerMock does implement clock.Timer.
+// If this is not the case, regenerate this file with moq.
+var _ clock.Timer = &TimerMock{}
+
+// TimerMock is a mock implementation of clock.Timer.
+//
+//	func TestSomethingThatUsesTimer(t *testing.T) {
+//
+//		// make and configure a mocked clock.Timer
+//		mockedTimer := &TimerMock{
+//			CFunc: func() <-chan time.Time {
+//				panic("mock out the C method")
+//			},
+//			ResetFunc: func(d time.Duration) bool {
+//				panic("mock out the Reset method")
+//			},
+//			StopFunc: func() bool {
+//				panic("mock out the Stop method")
+//			},
+//		}
+//
+//		// use mockedTimer in code that requires clock.Timer
+//		// and then make assertions.
+//
+//	}
+type TimerMock struct {
+	// CFunc mocks the C method.
+	CFunc func() <-chan time.Time
+
+	// ResetFunc mocks the Reset method.
+	ResetFunc func(d time.Duration) bool
+
+	// StopFunc mocks the Stop method.
+	StopFunc func() bool
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// C holds details about calls to the C method.
+		C []struct {
+		}
+		// Reset holds details about calls to the Reset method.
+		Reset []struct {
+			// D is the d argument value.
+			D time.Duration
+		}
+		// Stop holds details about calls to the Stop method.
+		Stop []struct {
+		}
+	}
+	lockC     sync.RWMutex
+	lockReset sync.RWMutex
+	lockStop  sync.RWMutex
+}
+
+// C calls CFunc.
+func (mock *TimerMock) C() <-chan time.Time {
+	if mock.CFunc == nil {
+		panic("TimerMock.CFunc: method is nil but Timer.C was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockC.Lock()
+	mock.calls.C = append(mock.calls.C, callInfo)
+	mock.lockC.Unlock()
+	return mock.CFunc()
+}
+
+// CCalls gets all the calls that were made to C.
+// Check the length with:
+//
+//	len(mockedTimer.CCalls())
+func (mock *TimerMock) CCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockC.RLock()
+	calls = mock.calls.C
+	mock.lockC.RUnlock()
+	return calls
+}
+
+// Reset calls ResetFunc.
+func (mock *TimerMock) Reset(d time.Duration) bool {
+	if mock.ResetFunc == nil {
+		panic("TimerMock.ResetFunc: method is nil but Timer.Reset was just called")
+	}
+	callInfo := struct {
+		D time.Duration
+	}{
+		D: d,
+	}
+	mock.lockReset.Lock()
+	mock.calls.Reset = append(mock.calls.Reset, callInfo)
+	mock.lockReset.Unlock()
+	return mock.ResetFunc(d)
+}
+
+// ResetCalls gets all the calls that were made to Reset.
+// Check the length with:
+//
+//	len(mockedTimer.ResetCalls())
+func (mock *TimerMock) ResetCalls() []struct {
+	D time.Duration
+} {
+	var calls []struct {
+		D time.Duration
+	}
+	mock.lockReset.RLock()
+	calls = mock.calls.Reset
+	mock.lockReset.RUnlock()
+	return calls
+}
+
+// Stop calls StopFunc.
+func (mock *TimerMock) Stop() bool {
+	if mock.StopFunc == nil {
+		panic("TimerMock.StopFunc: method is nil but Timer.Stop was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockStop.Lock()
+	mock.calls.Stop = append(mock.calls.Stop, callInfo)
+	mock.lockStop.Unlock()
+	return mock.StopFunc()
+}
+
+// StopCalls gets all the calls that were made to Stop.
+// Check the length with:
+//
+//	len(mockedTimer.StopCalls())
+func (mock *TimerMock) StopCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStop.RLock()
+	calls = mock.calls.Stop
+	mock.lockStop.RUnlock()
+	return calls
+}
+
+// Ensure, that TickerMock does implement clock.Ticker.
+// If this is not the case, regenerate this file with moq.
+var _ clock.Ticker = &TickerMock{}
+
+// TickerMock is a mock implementation of clock.Ticker.
+//
+//	func TestSomethingThatUsesTicker(t *testing.T) {
+//
+//		// make and configure a mocked clock.Ticker
+//		mockedTicker := &TickerMock{
+//			CFunc: func() <-chan time.Time {
+//				panic("mock out the C method")
+//			},
+//			ResetFunc: func(d time.Duration)  {
+//				panic("mock out the Reset method")
+//			},
+//			StopFunc: func()  {
+//				panic("mock out the Stop method")
+//			},
+//		}
+//
+//		// use mockedTicker in code that requires clock.Ticker
+//		// and then make assertions.
+//
+//	}
+type TickerMock struct {
+	// CFunc mocks the C method.
+	CFunc func() <-chan time.Time
+
+	// ResetFunc mocks the Reset method.
+	ResetFunc func(d time.Duration)
+
+	// StopFunc mocks the Stop method.
+	StopFunc func()
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// C holds details about calls to the C method.
+		C []struct {
+		}
+		// Reset holds details about calls to the Reset method.
+		Reset []struct {
+			// D is the d argument value.
+			D time.Duration
+		}
+		// Stop holds details about calls to the Stop method.
+		Stop []struct {
+		}
+	}
+	lockC     sync.RWMutex
+	lockReset sync.RWMutex
+	lockStop  sync.RWMutex
+}
+
+// C calls CFunc.
+func (mock *TickerMock) C() <-chan time.Time {
+	if mock.CFunc == nil {
+		panic("TickerMock.CFunc: method is nil but Ticker.C was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockC.Lock()
+	mock.calls.C = append(mock.calls.C, callInfo)
+	mock.lockC.Unlock()
+	return mock.CFunc()
+}
+
+// CCalls gets all the calls that were made to C.
+// Check the length with:
+//
+//	len(mockedTicker.CCalls())
+func (mock *TickerMock) CCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockC.RLock()
+	calls = mock.calls.C
+	mock.lockC.RUnlock()
+	return calls
+}
+
+// Reset calls ResetFunc.
+func (mock *TickerMock) Reset(d time.Duration) {
+	if mock.ResetFunc == nil {
+		panic("TickerMock.ResetFunc: method is nil but Ticker.Reset was just called")
+	}
+	callInfo := struct {
+		D time.Duration
+	}{
+		D: d,
+	}
+	mock.lockReset.Lock()
+	mock.calls.Reset = append(mock.calls.Reset, callInfo)
+	mock.lockReset.Unlock()
+	mock.ResetFunc(d)
+}
+
+// ResetCalls gets all the calls that were made to Reset.
+// Check the length with:
+//
+//	len(mockedTicker.ResetCalls())
+func (mock *TickerMock) ResetCalls() []struct {
+	D time.Duration
+} {
+	var calls []struct {
+		D time.Duration
+	}
+	mock.lockReset.RLock()
+	calls = mock.calls.Reset
+	mock.lockReset.RUnlock()
+	return calls
+}
+
+// Stop calls StopFunc.
+func (mock *TickerMock) Stop() {
+	if mock.StopFunc == nil {
+		panic("TickerMock.StopFunc: method is nil but Ticker.Stop was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockStop.Lock()
+	mock.calls.Stop = append(mock.calls.Stop, callInfo)
+	mock.lockStop.Unlock()
+	mock.StopFunc()
+}
+
+// StopCalls gets all the calls that were made to Stop.
+// Check the length with:
+//
+//	len(mockedTicker.StopCalls())
+func (mock *TickerMock) StopCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStop.RLock()
+	calls = mock.calls.Stop
+	mock.lockStop.RUnlock()
+	return calls
+}
@@ -0,0 +1,288 @@
+// Package clockotel provides a clock.Clock that records the time spent
+// waiting on it in OpenTelemetry traces, so that sleeps and timer waits
+// show up as spans, or as events on the caller's span, instead of as
+// unexplained gaps.
+//
+// Waits are attributed to the span in a Context: the one given to
+// SleepContext, or the one a Clock was bound to with WithContext. Waits
+// with no span to attribute them to are not recorded, so that they
+// don't start traces of their own.
+package clockotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the Tracer spans are recorded with.
+const instrumentationName = "github.com/jmhodges/clock/clockotel"
+
+// Option configures New.
+type Option func(*options)
+
+type options struct {
+	tp        trace.TracerProvider
+	threshold time.Duration
+	events    bool
+}
+
+// WithTracerProvider sets the TracerProvider spans are recorded with.
+// The default is otel.GetTracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tp = tp
+	}
+}
+
+// WithThreshold keeps waits shorter than d, as measured on the Clock,
+// from being recorded.
+func WithThreshold(d time.Duration) Option {
+	return func(o *options) {
+		o.threshold = d
+	}
+}
+
+// WithEvents records waits as events on the span they are attributed
+// to, at the time they ended, instead of as child spans of it.
+func WithEvents() Option {
+	return func(o *options) {
+		o.events = true
+	}
+}
+
+// Clock is a clock.Clock that passes calls on to another, recording the
+// waits in Sleep, SleepUntil, SleepContext, After, AfterAt and the
+// Timers of NewTimer and NewTimerAt. A span or event named after the
+// method runs, on the Clock's time, from when the wait started until
+// it ended, and has the duration asked for as the attribute
+// clock.requested_seconds. A Timer's wait runs from when it was set
+// until it fired, so Timers that are stopped are not recorded.
+// AfterFunc and Tickers are passed on unrecorded.
+type Clock struct {
+	clk    clock.Clock
+	ctx    context.Context
+	tracer trace.Tracer
+	opts   options
+}
+
+// New returns a Clock passing calls on to clk.
+func New(clk clock.Clock, opts ...Option) *Clock {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.tp == nil {
+		o.tp = otel.GetTracerProvider()
+	}
+	return &Clock{
+		clk:    clk,
+		ctx:    context.Background(),
+		tracer: o.tp.Tracer(instrumentationName),
+		opts:   o,
+	}
+}
+
+// WithContext returns a Clock like c whose waits, other than those in
+// SleepContext, are attributed to the span in ctx.
+func (c *Clock) WithContext(ctx context.Context) *Clock {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// record records a wait in ctx's span that started at start, on the
+// Clock, and ended now. err is the error the wait ended with, if any.
+func (c *Clock) record(ctx context.Context, name string, requested time.Duration, start time.Time, err error) {
+	parent := trace.SpanFromContext(ctx)
+	if !parent.SpanContext().IsValid() {
+		return
+	}
+	end := c.clk.Now()
+	if end.Sub(start) < c.opts.threshold {
+		return
+	}
+	attrs := []attribute.KeyValue{attribute.Float64("clock.requested_seconds", requested.Seconds())}
+	if c.opts.events {
+		if err != nil {
+			attrs = append(attrs, attribute.String("clock.error", err.Error()))
+		}
+		parent.AddEvent(name, trace.WithAttributes(attrs...), trace.WithTimestamp(end))
+		return
+	}
+	_, span := c.tracer.Start(ctx, name, trace.WithAttributes(attrs...), trace.WithTimestamp(start))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+func (c *Clock) Now() time.Time {
+	return c.clk.Now()
+}
+
+func (c *Clock) NowUnix() int64 {
+	return c.clk.NowUnix()
+}
+
+func (c *Clock) NowUnixMilli() int64 {
+	return c.clk.NowUnixMilli()
+}
+
+func (c *Clock) NowUnixNano() int64 {
+	return c.clk.NowUnixNano()
+}
+
+func (c *Clock) NowMonotonic() time.Duration {
+	return c.clk.NowMonotonic()
+}
+
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.clk.Since(t)
+}
+
+func (c *Clock) Until(t time.Time) time.Duration {
+	return c.clk.Until(t)
+}
+
+func (c *Clock) Sleep(d time.Duration) {
+	start := c.clk.Now()
+	c.clk.Sleep(d)
+	c.record(c.ctx, "clock.Sleep", d, start, nil)
+}
+
+func (c *Clock) SleepUntil(t time.Time) {
+	start := c.clk.Now()
+	c.clk.SleepUntil(t)
+	c.record(c.ctx, "clock.SleepUntil", t.Sub(start), start, nil)
+}
+
+func (c *Clock) SleepContext(ctx context.Context, d time.Duration) error {
+	start := c.clk.Now()
+	err := c.clk.SleepContext(ctx, d)
+	c.record(ctx, "clock.SleepContext", d, start, err)
+	return err
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.newTimer("clock.After", d).C()
+}
+
+func (c *Clock) AfterAt(t time.Time) <-chan time.Time {
+	return c.newTimer("clock.AfterAt", c.clk.Until(t)).C()
+}
+
+func (c *Clock) NewTimer(d time.Duration) clock.Timer {
+	return c.newTimer("clock.NewTimer", d)
+}
+
+func (c *Clock) NewTimerAt(t time.Time) clock.Timer {
+	return c.newTimer("clock.NewTimerAt", c.clk.Until(t))
+}
+
+func (c *Clock) newTimer(name string, d time.Duration) *timer {
+	t := &timer{c: c, name: name, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	return c.clk.AfterFunc(d, f)
+}
+
+func (c *Clock) NewTicker(d time.Duration) clock.Ticker {
+	return c.clk.NewTicker(d)
+}
+
+func (c *Clock) Tick(d time.Duration) <-chan time.Time {
+	return c.clk.Tick(d)
+}
+
+// timer is a Timer built on the wrapped clock's AfterFunc, so that its
+// firing can be seen and recorded.
+type timer struct {
+	c    *Clock
+	name string
+	ch   chan time.Time
+
+	mu    sync.Mutex
+	t     clock.Timer // nil until first set
+	gen   int         // incremented each time t is set or stopped
+	set   time.Time   // when t was last set
+	d     time.Duration
+	armed bool
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.ch
+}
+
+// fire is called by the wrapped clock when the timer set as gen fires.
+func (t *timer) fire(gen int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.gen != gen || !t.armed {
+		return
+	}
+	t.armed = false
+	select {
+	case t.ch <- t.c.clk.Now():
+	default:
+	}
+	t.c.record(t.c.ctx, t.name, t.d, t.set, nil)
+}
+
+// stop stops t, reporting whether it was active or had fired without
+// its time being received, as the wrapped clock's Timers do, and drains
+// its channel so that no stale time is received after Stop or Reset.
+// t.mu must be held.
+func (t *timer) stop() bool {
+	t.gen++
+	if t.t != nil {
+		t.t.Stop()
+	}
+	drained := false
+	select {
+	case <-t.ch:
+		drained = true
+	default:
+	}
+	wasArmed := t.armed
+	t.armed = false
+	return drained || wasArmed
+}
+
+func (t *timer) Stop() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stop()
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.mu.Lock()
+	wasArmed := t.stop()
+	t.armed = true
+	t.set = t.c.clk.Now()
+	t.d = d
+	gen := t.gen
+	t.mu.Unlock()
+
+	// Set the wrapped clock's timer without t.mu held, since the clock
+	// may call fire before AfterFunc returns.
+	bt := t.c.clk.AfterFunc(d, func() { t.fire(gen) })
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.gen != gen {
+		// Stopped or reset again meanwhile.
+		bt.Stop()
+		return wasArmed
+	}
+	t.t = bt
+	return wasArmed
+}
@@ -0,0 +1,141 @@
+package clockotel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// sleep runs f in a goroutine and moves fc forward by d once f is
+// waiting on it.
+func sleep(fc clock.FakeClock, d time.Duration, f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	fc.BlockUntil(1)
+	fc.Add(d)
+	<-done
+}
+
+func TestClock(t *testing.T) {
+	fc := clock.NewFake()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	c := New(fc, WithTracerProvider(tp), WithThreshold(time.Second))
+
+	// Without a span to attribute it to, a wait isn't recorded.
+	unattributed := c.NewTimer(time.Minute)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	start := fc.Now()
+	ctxClk := c.WithContext(ctx)
+	tm := ctxClk.NewTimer(time.Minute)
+	stopped := ctxClk.NewTimer(time.Minute)
+	stopped.Stop()
+	fc.Add(time.Minute)
+	<-tm.C()
+	<-unattributed.C()
+	sleep(fc, time.Millisecond, func() { ctxClk.Sleep(time.Millisecond) }) // under the threshold
+	sleep(fc, time.Second, func() {
+		if err := c.SleepContext(ctx, time.Second); err != nil {
+			t.Error(err)
+		}
+	})
+	parent.End()
+
+	spans := rec.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("recorded %d spans, want 3", len(spans))
+	}
+	want := []struct {
+		name       string
+		start, end time.Time
+	}{
+		{"clock.NewTimer", start, start.Add(time.Minute)},
+		{"clock.SleepContext", start.Add(time.Minute + time.Millisecond), start.Add(time.Minute + time.Second + time.Millisecond)},
+		{"parent", spans[2].StartTime(), spans[2].EndTime()},
+	}
+	for i, w := range want {
+		s := spans[i]
+		if s.Name() != w.name || !s.StartTime().Equal(w.start) || !s.EndTime().Equal(w.end) {
+			t.Errorf("span %d = %s from %v to %v, want %s from %v to %v",
+				i, s.Name(), s.StartTime(), s.EndTime(), w.name, w.start, w.end)
+		}
+		if i < 2 && s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the parent span", s.Name())
+		}
+	}
+}
+
+func TestClockEvents(t *testing.T) {
+	fc := clock.NewFake()
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	c := New(fc, WithTracerProvider(tp), WithEvents())
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := c.SleepContext(ctx, time.Second); err == nil {
+		t.Fatal("SleepContext with a canceled Context returned nil")
+	}
+	parent.End()
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	events := spans[0].Events()
+	if len(events) != 1 || events[0].Name != "clock.SleepContext" || len(events[0].Attributes) != 2 {
+		t.Errorf("events = %+v, want one clock.SleepContext event with 2 attributes", events)
+	}
+}
+
+func TestClockSynchronousAfterFunc(t *testing.T) {
+	// Timers that are already due don't deadlock over a FakeClock that
+	// calls AfterFunc functions synchronously.
+	fc := clock.NewFake(clock.WithSynchronousAfterFunc())
+	c := New(fc)
+	<-c.After(0)
+	tm := c.NewTimer(time.Second)
+	fc.Add(time.Second)
+	<-tm.C()
+	tm.Reset(0)
+	<-tm.C()
+	done := make(chan struct{})
+	c.AfterFunc(0, func() { close(done) })
+	<-done
+}
+
+func TestTimerStopMatchesFake(t *testing.T) {
+	// Stopping or resetting a Timer that fired but wasn't received
+	// reports true, as it does for the FakeClock's own Timers.
+	fc := clock.NewFake(clock.WithSynchronousAfterFunc())
+	for name, clk := range map[string]clock.Clock{"FakeClock": fc, "Clock": New(fc)} {
+		tm := clk.NewTimer(time.Second)
+		fc.Add(time.Second)
+		if !tm.Stop() {
+			t.Errorf("%s: Stop of a fired, unreceived Timer returned false", name)
+		}
+		if tm.Stop() {
+			t.Errorf("%s: second Stop returned true", name)
+		}
+		tm.Reset(time.Second)
+		fc.Add(time.Second)
+		if !tm.Reset(time.Second) {
+			t.Errorf("%s: Reset of a fired, unreceived Timer returned false", name)
+		}
+		select {
+		case <-tm.C():
+			t.Errorf("%s: Timer kept its time across Reset", name)
+		default:
+		}
+		tm.Stop()
+	}
+}
@@ -0,0 +1,305 @@
+// Package clockprometheus provides a clock.Clock that exports metrics
+// about how it is used, such as how long callers spend parked in Sleep,
+// as a prometheus.Collector.
+package clockprometheus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Clock is a clock.Clock that passes calls on to another and counts
+// them. It is a prometheus.Collector of these metrics, each named with
+// the namespace given to New:
+//
+//   - clock_now_calls_total, a counter of calls reading the time: Now,
+//     the NowUnix methods, NowMonotonic, Since and Until.
+//   - clock_sleep_seconds, a histogram of how long calls to Sleep,
+//     SleepUntil and SleepContext were parked for, as measured on the
+//     Clock.
+//   - clock_timer_seconds, a histogram of the durations Timers, After,
+//     AfterAt and AfterFunc were set for, each time one is made or
+//     reset.
+//   - clock_active_timers, a gauge of the Timers, Tickers and AfterFunc
+//     functions, including those behind After, AfterAt and Tick, that
+//     have yet to fire or be stopped.
+type Clock struct {
+	clk clock.Clock
+
+	nowCalls prometheus.Counter
+	sleeps   prometheus.Histogram
+	timers   prometheus.Histogram
+	active   prometheus.Gauge
+}
+
+// buckets runs from a millisecond to a little over an hour.
+var buckets = prometheus.ExponentialBuckets(0.001, 4, 12)
+
+// New returns a Clock passing calls on to clk, with metrics named in
+// namespace, which may be empty.
+func New(clk clock.Clock, namespace string) *Clock {
+	return &Clock{
+		clk: clk,
+		nowCalls: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "clock_now_calls_total",
+			Help:      "Calls reading the clock's time.",
+		}),
+		sleeps: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "clock_sleep_seconds",
+			Help:      "Time callers spent sleeping on the clock.",
+			Buckets:   buckets,
+		}),
+		timers: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "clock_timer_seconds",
+			Help:      "Durations timers on the clock were set for.",
+			Buckets:   buckets,
+		}),
+		active: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "clock_active_timers",
+			Help:      "Timers, tickers and functions waiting on the clock.",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Clock) Describe(ch chan<- *prometheus.Desc) {
+	c.nowCalls.Describe(ch)
+	c.sleeps.Describe(ch)
+	c.timers.Describe(ch)
+	c.active.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Clock) Collect(ch chan<- prometheus.Metric) {
+	c.nowCalls.Collect(ch)
+	c.sleeps.Collect(ch)
+	c.timers.Collect(ch)
+	c.active.Collect(ch)
+}
+
+func (c *Clock) Now() time.Time {
+	c.nowCalls.Inc()
+	return c.clk.Now()
+}
+
+func (c *Clock) NowUnix() int64 {
+	c.nowCalls.Inc()
+	return c.clk.NowUnix()
+}
+
+func (c *Clock) NowUnixMilli() int64 {
+	c.nowCalls.Inc()
+	return c.clk.NowUnixMilli()
+}
+
+func (c *Clock) NowUnixNano() int64 {
+	c.nowCalls.Inc()
+	return c.clk.NowUnixNano()
+}
+
+func (c *Clock) NowMonotonic() time.Duration {
+	c.nowCalls.Inc()
+	return c.clk.NowMonotonic()
+}
+
+func (c *Clock) Since(t time.Time) time.Duration {
+	c.nowCalls.Inc()
+	return c.clk.Since(t)
+}
+
+func (c *Clock) Until(t time.Time) time.Duration {
+	c.nowCalls.Inc()
+	return c.clk.Until(t)
+}
+
+// parked observes the time since start, a reading of the wrapped
+// clock's NowMonotonic, as time spent sleeping.
+func (c *Clock) parked(start time.Duration) {
+	c.sleeps.Observe((c.clk.NowMonotonic() - start).Seconds())
+}
+
+func (c *Clock) Sleep(d time.Duration) {
+	defer c.parked(c.clk.NowMonotonic())
+	c.clk.Sleep(d)
+}
+
+func (c *Clock) SleepUntil(t time.Time) {
+	defer c.parked(c.clk.NowMonotonic())
+	c.clk.SleepUntil(t)
+}
+
+func (c *Clock) SleepContext(ctx context.Context, d time.Duration) error {
+	defer c.parked(c.clk.NowMonotonic())
+	return c.clk.SleepContext(ctx, d)
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *Clock) AfterAt(t time.Time) <-chan time.Time {
+	return c.NewTimerAt(t).C()
+}
+
+func (c *Clock) NewTimer(d time.Duration) clock.Timer {
+	t := &timer{c: c, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+func (c *Clock) NewTimerAt(t time.Time) clock.Timer {
+	return c.NewTimer(c.clk.Until(t))
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	t := &timer{c: c, fn: f}
+	t.Reset(d)
+	return t
+}
+
+func (c *Clock) NewTicker(d time.Duration) clock.Ticker {
+	tk := &ticker{c: c, t: c.clk.NewTicker(d), active: true}
+	c.active.Inc()
+	return tk
+}
+
+func (c *Clock) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return c.NewTicker(d).C()
+}
+
+// timer is a Timer or AfterFunc function, counted in the active gauge
+// from when it is set until it fires or is stopped. Both kinds are
+// built on the wrapped clock's AfterFunc, so that firing can be seen.
+type timer struct {
+	c  *Clock
+	ch chan time.Time // nil for AfterFunc
+	fn func()         // nil for Timers
+
+	mu     sync.Mutex
+	t      clock.Timer // nil until first set
+	gen    int         // incremented each time t is set or stopped
+	active bool
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.ch
+}
+
+// fire is called by the wrapped clock when the timer set as gen fires.
+func (t *timer) fire(gen int) {
+	t.mu.Lock()
+	if t.gen != gen || !t.active {
+		t.mu.Unlock()
+		return
+	}
+	t.active = false
+	t.c.active.Dec()
+	if t.fn == nil {
+		select {
+		case t.ch <- t.c.clk.Now():
+		default:
+		}
+	}
+	t.mu.Unlock()
+	if t.fn != nil {
+		t.fn()
+	}
+}
+
+// stop stops t, reporting whether it was active or had fired without
+// its time being received, as the wrapped clock's Timers do. It drains
+// a Timer's channel, so that no stale time is received after Stop or
+// Reset. t.mu must be held.
+func (t *timer) stop() bool {
+	t.gen++
+	if t.t != nil {
+		t.t.Stop()
+	}
+	drained := false
+	if t.ch != nil {
+		select {
+		case <-t.ch:
+			drained = true
+		default:
+		}
+	}
+	wasActive := t.active
+	if wasActive {
+		t.active = false
+		t.c.active.Dec()
+	}
+	return drained || wasActive
+}
+
+func (t *timer) Stop() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stop()
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.mu.Lock()
+	wasActive := t.stop()
+	t.active = true
+	t.c.active.Inc()
+	t.c.timers.Observe(d.Seconds())
+	gen := t.gen
+	t.mu.Unlock()
+
+	// Set the wrapped clock's timer without t.mu held, since the clock
+	// may call fire before AfterFunc returns.
+	bt := t.c.clk.AfterFunc(d, func() { t.fire(gen) })
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.gen != gen {
+		// Stopped or reset again meanwhile.
+		bt.Stop()
+		return wasActive
+	}
+	t.t = bt
+	return wasActive
+}
+
+// ticker is a Ticker counted in the active gauge until it is stopped.
+type ticker struct {
+	c *Clock
+	t clock.Ticker
+
+	mu     sync.Mutex
+	active bool
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.t.C()
+}
+
+func (t *ticker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.t.Stop()
+	if t.active {
+		t.active = false
+		t.c.active.Dec()
+	}
+}
+
+func (t *ticker) Reset(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.t.Reset(d)
+	if !t.active {
+		t.active = true
+		t.c.active.Inc()
+	}
+}
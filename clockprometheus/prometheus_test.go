@@ -0,0 +1,128 @@
+package clockprometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestClock(t *testing.T) {
+	fc := clock.NewFake()
+	c := New(fc, "test")
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	c.Now()
+	c.Since(fc.Now())
+	tm := c.NewTimer(time.Second)
+	c.AfterFunc(time.Minute, func() {})
+	tk := c.NewTicker(time.Hour)
+	if got := testutil.ToFloat64(c.active); got != 3 {
+		t.Errorf("active timers = %v, want 3", got)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Sleep(2 * time.Second)
+	}()
+	fc.BlockUntil(4)
+	fc.Add(2 * time.Second)
+	<-done
+	if got, want := <-tm.C(), fc.Now().Add(-time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got := testutil.ToFloat64(c.active); got != 2 {
+		t.Errorf("active timers = %v after the Timer fired, want 2", got)
+	}
+	if tm.Reset(time.Second) {
+		t.Errorf("Reset of a fired Timer returned true")
+	}
+	if !tm.Stop() {
+		t.Errorf("Stop of a reset Timer returned false")
+	}
+	tk.Stop()
+	fc.Add(time.Minute)
+
+	want := `
+# HELP test_clock_active_timers Timers, tickers and functions waiting on the clock.
+# TYPE test_clock_active_timers gauge
+test_clock_active_timers 0
+# HELP test_clock_now_calls_total Calls reading the clock's time.
+# TYPE test_clock_now_calls_total counter
+test_clock_now_calls_total 2
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(want), "test_clock_active_timers", "test_clock_now_calls_total")
+	if err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(c, "test_clock_sleep_seconds"); n != 1 {
+		t.Errorf("collected %d sleep histograms, want 1", n)
+	}
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		h := mf.GetMetric()[0].GetHistogram()
+		switch mf.GetName() {
+		case "test_clock_sleep_seconds":
+			if h.GetSampleCount() != 1 || h.GetSampleSum() != 2 {
+				t.Errorf("sleep histogram has %d samples summing to %v, want 1 summing to 2",
+					h.GetSampleCount(), h.GetSampleSum())
+			}
+		case "test_clock_timer_seconds":
+			if h.GetSampleCount() != 3 || h.GetSampleSum() != 62 {
+				t.Errorf("timer histogram has %d samples summing to %v, want 3 summing to 62",
+					h.GetSampleCount(), h.GetSampleSum())
+			}
+		}
+	}
+}
+
+func TestClockSynchronousAfterFunc(t *testing.T) {
+	// Timers that are already due don't deadlock over a FakeClock that
+	// calls AfterFunc functions synchronously.
+	fc := clock.NewFake(clock.WithSynchronousAfterFunc())
+	c := New(fc, "sync")
+	<-c.After(0)
+	tm := c.NewTimer(time.Second)
+	fc.Add(time.Second)
+	<-tm.C()
+	tm.Reset(0)
+	<-tm.C()
+	done := make(chan struct{})
+	c.AfterFunc(0, func() { close(done) })
+	<-done
+}
+
+func TestTimerStopMatchesFake(t *testing.T) {
+	// Stopping or resetting a Timer that fired but wasn't received
+	// reports true, as it does for the FakeClock's own Timers.
+	fc := clock.NewFake(clock.WithSynchronousAfterFunc())
+	for name, clk := range map[string]clock.Clock{"FakeClock": fc, "Clock": New(fc, "stop")} {
+		tm := clk.NewTimer(time.Second)
+		fc.Add(time.Second)
+		if !tm.Stop() {
+			t.Errorf("%s: Stop of a fired, unreceived Timer returned false", name)
+		}
+		if tm.Stop() {
+			t.Errorf("%s: second Stop returned true", name)
+		}
+		tm.Reset(time.Second)
+		fc.Add(time.Second)
+		if !tm.Reset(time.Second) {
+			t.Errorf("%s: Reset of a fired, unreceived Timer returned false", name)
+		}
+		select {
+		case <-tm.C():
+			t.Errorf("%s: Timer kept its time across Reset", name)
+		default:
+		}
+		tm.Stop()
+	}
+}
@@ -0,0 +1,351 @@
+// Package clockquartz converts between clock.Clock and the Clock of
+// github.com/coder/quartz, so that projects can move between the two
+// one package at a time.
+//
+// quartz's Timer and Ticker are structs whose fields, apart from C, are
+// unexported, so nothing outside quartz can make a working one. For a
+// clock.Clock other than Default, ToQuartz gets around this with a
+// quartz Mock kept up to date with the Clock it wraps, with the limits
+// given in its documentation.
+package clockquartz
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coder/quartz"
+	"github.com/jmhodges/clock"
+)
+
+// ToQuartz returns a quartz.Clock backed by clk. If clk is
+// clock.Default(), quartz's real clock is returned, and if clk was
+// returned by FromQuartz, the quartz.Clock it was made from is.
+//
+// Otherwise, its Now, Since and Until use clk directly. Its Timers,
+// Tickers, AfterFunc functions and TickerFuncs belong to a quartz Mock,
+// which is moved up to clk's time, one event at a time, each time its
+// next event comes due on clk. So a FakeClock, moved by Add or Set,
+// fires them before returning, and once they have all fired or been
+// stopped, nothing is left waiting on clk. Because Reset on a Timer or
+// Ticker can't be seen by the adapter, one Reset to come due sooner
+// fires only once the Mock's previous next event has come due on clk.
+// A Ticker made from an AfterFunc function, while the Mock is catching
+// up with clk, starts from the Mock's time rather than clk's. Tests
+// should use ToQuartzForTest instead.
+func ToQuartz(clk clock.Clock) quartz.Clock {
+	if c := passthrough(clk); c != nil {
+		return c
+	}
+	return newToQuartz(untested{}, clk)
+}
+
+// ToQuartzForTest is ToQuartz for tests: the Mock is made with tb, so
+// that it logs to the test and reports its errors there, and at the end
+// of the test, the adapter stops waiting on clk for good, so that it
+// isn't reported by clock.WithLeakCheck.
+func ToQuartzForTest(tb quartz.TestingT, clk clock.Clock) quartz.Clock {
+	if c := passthrough(clk); c != nil {
+		return c
+	}
+	q := newToQuartz(tb, clk)
+	tb.Cleanup(q.stop)
+	return q
+}
+
+// passthrough returns the quartz.Clock for clk if it needs no Mock, and
+// nil otherwise.
+func passthrough(clk clock.Clock) quartz.Clock {
+	if f, ok := clk.(*fromQuartz); ok {
+		return f.c
+	}
+	if clk == clock.Default() {
+		return quartz.NewReal()
+	}
+	return nil
+}
+
+func newToQuartz(tb quartz.TestingT, clk clock.Clock) *toQuartz {
+	m := quartz.NewMock(tb)
+	m.Set(clk.Now().Round(0))
+	return &toQuartz{clk: clk, m: m}
+}
+
+// untested is the quartz.TestingT of the Mocks made by ToQuartz, for
+// use outside tests. It discards the Mock's logs and errors. The Mock
+// only reports an error when it is moved past its next event, which
+// can happen if a Timer is Reset while the adapter is moving it, in
+// which case the Mock is left where it was until the next move. The
+// functions the Mock registers with Cleanup are never called, so a
+// fired Timer whose time is neither received nor stopped keeps a
+// goroutine waiting to send it.
+type untested struct{}
+
+func (untested) Helper()               {}
+func (untested) Log(...any)            {}
+func (untested) Logf(string, ...any)   {}
+func (untested) Error(...any)          {}
+func (untested) Errorf(string, ...any) {}
+func (untested) Fatal(...any)          {}
+func (untested) Fatalf(string, ...any) {}
+func (untested) Cleanup(func())        {}
+
+type toQuartz struct {
+	clk clock.Clock
+	m   *quartz.Mock
+
+	// syncMu is held while the Mock is moved, and dirty is set when it
+	// may have fallen behind clk since.
+	syncMu sync.Mutex
+	dirty  atomic.Bool
+
+	mu      sync.Mutex // guards the fields below
+	wake    clock.Timer
+	wakeAt  time.Time
+	gen     uint64
+	stopped bool
+}
+
+// sync moves the Mock up to clk's time, firing what has come due on it
+// and waiting for its AfterFunc functions to return. If the Mock is
+// already being moved, such as when sync is called from one of those
+// functions, sync leaves it to the caller moving it to catch up again.
+func (q *toQuartz) sync() {
+	q.dirty.Store(true)
+	for q.dirty.Load() && q.syncMu.TryLock() {
+		q.dirty.Store(false)
+		q.catchUp()
+		q.syncMu.Unlock()
+	}
+}
+
+// catchUp does the work of sync. It must be called with q.syncMu held.
+func (q *toQuartz) catchUp() {
+	ctx := context.Background()
+	now := q.clk.Now().Round(0)
+	for {
+		d, ok := q.m.Peek()
+		if !ok || q.m.Now().Add(d).After(now) {
+			break
+		}
+		_, w := q.m.AdvanceNext()
+		w.MustWait(ctx)
+	}
+	if now.After(q.m.Now()) {
+		q.m.Set(now).MustWait(ctx)
+	}
+}
+
+// lag returns how far the Mock is behind clk, for new timers to make up.
+func (q *toQuartz) lag() time.Duration {
+	return max(q.clk.Now().Sub(q.m.Now()), 0)
+}
+
+// arm makes sure a function is waiting on clk for the Mock's next
+// event, if it has one, replacing the one waiting for a later event.
+func (q *toQuartz) arm() {
+	d, ok := q.m.Peek()
+	if !ok {
+		return
+	}
+	at := q.m.Now().Add(d)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped || q.wake != nil && !at.Before(q.wakeAt) {
+		return
+	}
+	if q.wake != nil {
+		q.wake.Stop()
+	}
+	q.gen++
+	gen := q.gen
+	q.wake, q.wakeAt = q.clk.AfterFunc(q.clk.Until(at), func() { q.woke(gen) }), at
+}
+
+// woke is called by the function arm left waiting on clk, made when
+// q.gen was gen.
+func (q *toQuartz) woke(gen uint64) {
+	q.mu.Lock()
+	if q.gen == gen {
+		q.wake = nil
+	}
+	q.mu.Unlock()
+	q.sync()
+	q.arm()
+}
+
+// stop stops the function waiting on clk, for good, at the end of the
+// test.
+func (q *toQuartz) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stopped = true
+	if q.wake != nil {
+		q.wake.Stop()
+		q.wake = nil
+	}
+}
+
+func (q *toQuartz) NewTicker(d time.Duration, tags ...string) *quartz.Ticker {
+	q.sync()
+	t := q.m.NewTicker(d, tags...)
+	q.arm()
+	return t
+}
+
+func (q *toQuartz) TickerFunc(ctx context.Context, d time.Duration, f func() error, tags ...string) quartz.Waiter {
+	q.sync()
+	w := q.m.TickerFunc(ctx, d, f, tags...)
+	q.arm()
+	return w
+}
+
+func (q *toQuartz) NewTimer(d time.Duration, tags ...string) *quartz.Timer {
+	q.sync()
+	t := q.m.NewTimer(max(d, 0)+q.lag(), tags...)
+	q.arm()
+	return t
+}
+
+func (q *toQuartz) AfterFunc(d time.Duration, f func(), tags ...string) *quartz.Timer {
+	q.sync()
+	t := q.m.AfterFunc(max(d, 0)+q.lag(), f, tags...)
+	q.arm()
+	return t
+}
+
+func (q *toQuartz) Now(tags ...string) time.Time {
+	return q.clk.Now()
+}
+
+func (q *toQuartz) Since(t time.Time, tags ...string) time.Duration {
+	return q.clk.Since(t)
+}
+
+func (q *toQuartz) Until(t time.Time, tags ...string) time.Duration {
+	return q.clk.Until(t)
+}
+
+// FromQuartz returns a clock.Clock backed by c, such as a quartz Mock.
+// Its NowMonotonic is the time that has passed on c since FromQuartz
+// was called. If c was returned by ToQuartz, the clock.Clock it was
+// made from is returned.
+func FromQuartz(c quartz.Clock) clock.Clock {
+	if q, ok := c.(*toQuartz); ok {
+		return q.clk
+	}
+	return &fromQuartz{c: c, Monotonic: clock.NewMonotonic(func() time.Time { return c.Now() })}
+}
+
+type fromQuartz struct {
+	c quartz.Clock
+	clock.Monotonic
+}
+
+func (f *fromQuartz) Now() time.Time {
+	return f.c.Now()
+}
+
+func (f *fromQuartz) NowUnix() int64 {
+	return f.c.Now().Unix()
+}
+
+func (f *fromQuartz) NowUnixMilli() int64 {
+	return f.c.Now().UnixMilli()
+}
+
+func (f *fromQuartz) NowUnixNano() int64 {
+	return f.c.Now().UnixNano()
+}
+
+func (f *fromQuartz) Since(t time.Time) time.Duration {
+	return f.c.Since(t)
+}
+
+func (f *fromQuartz) Until(t time.Time) time.Duration {
+	return f.c.Until(t)
+}
+
+func (f *fromQuartz) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-f.c.NewTimer(d).C
+}
+
+func (f *fromQuartz) SleepUntil(t time.Time) {
+	f.Sleep(f.c.Until(t))
+}
+
+func (f *fromQuartz) SleepContext(ctx context.Context, d time.Duration) error {
+	return clock.SleepContextWith(ctx, d, f.NewTimer)
+}
+
+func (f *fromQuartz) After(d time.Duration) <-chan time.Time {
+	return f.c.NewTimer(d).C
+}
+
+func (f *fromQuartz) AfterAt(t time.Time) <-chan time.Time {
+	return f.c.NewTimer(f.c.Until(t)).C
+}
+
+func (f *fromQuartz) NewTimer(d time.Duration) clock.Timer {
+	return timer{f.c.NewTimer(d)}
+}
+
+func (f *fromQuartz) NewTimerAt(t time.Time) clock.Timer {
+	return timer{f.c.NewTimer(f.c.Until(t))}
+}
+
+func (f *fromQuartz) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return ticker{f.c.NewTicker(d)}
+}
+
+func (f *fromQuartz) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return f.c.NewTicker(d).C
+}
+
+func (f *fromQuartz) AfterFunc(d time.Duration, fn func()) clock.Timer {
+	return timer{f.c.AfterFunc(d, fn)}
+}
+
+// timer is a quartz Timer as a clock.Timer.
+type timer struct {
+	t *quartz.Timer
+}
+
+func (t timer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t timer) Stop() bool {
+	return t.t.Stop()
+}
+
+func (t timer) Reset(d time.Duration) bool {
+	return t.t.Reset(d)
+}
+
+// ticker is a quartz Ticker as a clock.Ticker.
+type ticker struct {
+	t *quartz.Ticker
+}
+
+func (t ticker) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t ticker) Stop() {
+	t.t.Stop()
+}
+
+func (t ticker) Reset(d time.Duration) {
+	t.t.Reset(d)
+}
@@ -0,0 +1,135 @@
+package clockquartz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coder/quartz"
+	"github.com/jmhodges/clock"
+)
+
+func TestToQuartz(t *testing.T) {
+	m := quartz.NewMock(t)
+	if ToQuartz(FromQuartz(m)) != quartz.Clock(m) {
+		t.Errorf("ToQuartz(FromQuartz(m)) is not m")
+	}
+	if ToQuartz(clock.Default()) == nil {
+		t.Errorf("ToQuartz(clock.Default()) is nil")
+	}
+}
+
+func TestToQuartzWrapped(t *testing.T) {
+	// A Clock wrapping the real one needs no test.
+	q := ToQuartz(clock.Offset(clock.Default(), time.Hour))
+	if d := q.Since(clock.Default().Now()); d < time.Hour-time.Minute || d > time.Hour+time.Minute {
+		t.Errorf("Since(clock.Default().Now()) = %v, want about %v", d, time.Hour)
+	}
+	<-q.NewTimer(time.Millisecond).C
+	done := make(chan struct{})
+	q.AfterFunc(time.Millisecond, func() { close(done) })
+	<-done
+}
+
+func TestToQuartzFake(t *testing.T) {
+	fc := clock.NewFake(clock.WithLeakCheck(t))
+	q := ToQuartzForTest(t, fc)
+	if FromQuartz(q) != clock.Clock(fc) {
+		t.Errorf("FromQuartz(ToQuartz(fc)) is not fc")
+	}
+	start := fc.Now()
+	tm := q.NewTimer(time.Second)
+	tk := q.NewTicker(time.Minute)
+	var ticks []time.Time
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := q.TickerFunc(ctx, time.Minute, func() error {
+		ticks = append(ticks, q.Now())
+		return nil
+	})
+	fired := make(chan time.Time, 1)
+	q.AfterFunc(time.Hour, func() { fired <- q.Now() })
+
+	fc.Add(time.Second)
+	if got, want := <-tm.C, start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	fc.Add(time.Minute)
+	if got, want := <-tk.C, start.Add(time.Minute); !got.Equal(want) {
+		t.Errorf("Ticker sent %v, want %v", got, want)
+	}
+	tk.Stop()
+	fc.Set(start.Add(time.Hour))
+	if got, want := <-fired, start.Add(time.Hour); !got.Equal(want) {
+		t.Errorf("AfterFunc function ran at %v, want %v", got, want)
+	}
+	if len(ticks) != 60 || !ticks[59].Equal(start.Add(time.Hour)) {
+		t.Errorf("TickerFunc ran %d times, last at %v, want 60 times, last at %v", len(ticks), ticks[len(ticks)-1], start.Add(time.Hour))
+	}
+	if got := q.Since(start); got != time.Hour {
+		t.Errorf("Since = %v, want %v", got, time.Hour)
+	}
+
+	// Once the TickerFunc stops, so does the waiting on fc.
+	cancel()
+	w.Wait()
+	fc.Add(time.Minute)
+	if n := fc.Waiters(); n != 0 {
+		t.Errorf("%d waiters left on the FakeClock, want 0", n)
+	}
+}
+
+func TestToQuartzDue(t *testing.T) {
+	fc := clock.NewFake(clock.WithSynchronousAfterFunc())
+	q := ToQuartzForTest(t, fc)
+	start := fc.Now()
+	// A Timer made from an AfterFunc function fires within the same
+	// Add, as it would on fc.
+	var tm *quartz.Timer
+	var at time.Time
+	q.AfterFunc(time.Second, func() {
+		tm = q.NewTimer(time.Second)
+		q.AfterFunc(time.Second, func() { at = fc.Now() })
+	})
+	fc.Add(5 * time.Second)
+	if want := start.Add(2 * time.Second); !at.Equal(want) {
+		t.Errorf("AfterFunc function ran at %v, want %v", at, want)
+	}
+	if got, want := <-tm.C, start.Add(2*time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	done := make(chan struct{})
+	q.AfterFunc(0, func() { close(done) })
+	<-done
+}
+
+func TestFromQuartz(t *testing.T) {
+	ctx := context.Background()
+	m := quartz.NewMock(t)
+	clk := FromQuartz(m)
+	start := clk.Now()
+
+	trap := m.Trap().NewTimer()
+	slept := make(chan time.Time)
+	go func() {
+		clk.Sleep(time.Second)
+		slept <- clk.Now()
+	}()
+	trap.MustWait(ctx).MustRelease(ctx)
+	trap.Close()
+	tm := clk.NewTimerAt(start.Add(time.Second))
+
+	m.Advance(time.Second).MustWait(ctx)
+	if got, want := <-slept, start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Sleep woke at %v, want %v", got, want)
+	}
+	if got, want := <-tm.C(), start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got := clk.NowMonotonic(); got != time.Second {
+		t.Errorf("NowMonotonic() = %v, want %v", got, time.Second)
+	}
+	if tm.Stop() {
+		t.Errorf("Stop of a fired Timer returned true")
+	}
+}
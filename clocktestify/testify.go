@@ -0,0 +1,42 @@
+// Package clocktestify provides a github.com/stretchr/testify suite
+// that gives each of its tests a fresh clock.FakeClock.
+package clocktestify
+
+import (
+	"github.com/jmhodges/clock"
+	"github.com/stretchr/testify/suite"
+)
+
+// Suite is a testify suite.Suite with a FakeClock for each test. Embed
+// it in a suite in place of suite.Suite:
+//
+//	type ServerSuite struct {
+//		clocktestify.Suite
+//	}
+//
+//	func (s *ServerSuite) TestExpiry() {
+//		srv := NewServer(s.Clock)
+//		s.Clock.Add(time.Hour)
+//		...
+//	}
+//
+// A suite that defines its own SetupTest must call Suite's from it.
+type Suite struct {
+	suite.Suite
+
+	// Clock is the current test's FakeClock, made by SetupTest.
+	Clock clock.FakeClock
+
+	// ClockOptions are the Options SetupTest makes each test's
+	// FakeClock with, such as clock.WithLocation. They can be set
+	// before the suite is run or in SetupSuite.
+	ClockOptions []clock.Option
+}
+
+// SetupTest makes a new FakeClock for the test about to run. The test
+// fails if it ends with any Timers, Tickers, AfterFunc functions or
+// sleepers still waiting on the clock, as with clock.WithLeakCheck.
+func (s *Suite) SetupTest() {
+	opts := append([]clock.Option{clock.WithLeakCheck(s.T())}, s.ClockOptions...)
+	s.Clock = clock.NewFake(opts...)
+}
@@ -0,0 +1,44 @@
+package clocktestify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"github.com/stretchr/testify/suite"
+)
+
+type exampleSuite struct {
+	Suite
+	clocks []clock.FakeClock
+}
+
+func (s *exampleSuite) TestFirst() {
+	s.check()
+}
+
+func (s *exampleSuite) TestSecond() {
+	s.check()
+}
+
+// check checks that the test has a fresh clock at the suite's start
+// time, and uses it.
+func (s *exampleSuite) check() {
+	for _, c := range s.clocks {
+		s.NotSame(c, s.Clock, "test shares a FakeClock with an earlier one")
+	}
+	s.clocks = append(s.clocks, s.Clock)
+	s.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), s.Clock.Now())
+	tm := s.Clock.NewTimer(time.Second)
+	s.Clock.Add(time.Second)
+	<-tm.C()
+}
+
+func TestSuite(t *testing.T) {
+	s := &exampleSuite{}
+	s.ClockOptions = []clock.Option{clock.WithStart(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))}
+	suite.Run(t, s)
+	if len(s.clocks) != 2 {
+		t.Errorf("ran %d tests, want 2", len(s.clocks))
+	}
+}
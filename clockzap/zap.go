@@ -0,0 +1,38 @@
+// Package clockzap adapts clock.Clock to zapcore.Clock, so that the
+// timestamps of go.uber.org/zap log entries, and the flushes of its
+// BufferedWriteSyncer, follow a clock.FakeClock in tests.
+package clockzap
+
+import (
+	"time"
+
+	"github.com/jmhodges/clock"
+	"go.uber.org/zap/zapcore"
+)
+
+// ToZap returns a zapcore.Clock backed by clk, for use with zap.WithClock
+// or zapcore.BufferedWriteSyncer.
+//
+// zapcore.Clock's NewTicker returns a *time.Ticker, which only the time
+// package can make tick on its own. When clk isn't clock.Default(), the
+// Ticker returned has the channel of a clock.Ticker made by clk, and
+// stopping it can't stop that clock.Ticker. Each one keeps ticking on
+// clk, dropping ticks nobody reads, for as long as clk is in use.
+func ToZap(clk clock.Clock) zapcore.Clock {
+	if clk == clock.Default() {
+		return zapcore.DefaultClock
+	}
+	return zapClock{clk}
+}
+
+type zapClock struct {
+	clk clock.Clock
+}
+
+func (z zapClock) Now() time.Time {
+	return z.clk.Now()
+}
+
+func (z zapClock) NewTicker(d time.Duration) *time.Ticker {
+	return &time.Ticker{C: z.clk.NewTicker(d).C()}
+}
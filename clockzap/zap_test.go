@@ -0,0 +1,66 @@
+package clockzap
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// syncBuffer is a bytes.Buffer safe for the BufferedWriteSyncer's
+// flushing goroutine to write to.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestToZap(t *testing.T) {
+	if ToZap(clock.Default()) != zapcore.DefaultClock {
+		t.Errorf("ToZap(clock.Default()) is not zapcore.DefaultClock")
+	}
+
+	fc := clock.NewFake()
+	fc.Set(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	out := &syncBuffer{}
+	ws := &zapcore.BufferedWriteSyncer{
+		WS:            zapcore.AddSync(out),
+		FlushInterval: time.Minute,
+		Clock:         ToZap(fc),
+	}
+	defer ws.Stop()
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		MessageKey: "msg",
+		TimeKey:    "ts",
+		EncodeTime: zapcore.RFC3339TimeEncoder,
+	})
+	log := zap.New(zapcore.NewCore(enc, ws, zap.InfoLevel), zap.WithClock(ToZap(fc)))
+
+	log.Info("hello")
+	if got := out.String(); got != "" {
+		t.Fatalf("wrote %q before the flush interval passed", got)
+	}
+	fc.Add(time.Minute)
+	want := `{"ts":"2020-01-01T00:00:00Z","msg":"hello"}` + "\n"
+	for i := 0; out.String() == "" && i < 1000; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if got := out.String(); got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,236 @@
+// Package cluster simulates a cluster of nodes with imperfect clocks
+// that exchange messages over a network with delays, for deterministic
+// tests of distributed protocols, such as consensus and leases, that
+// depend on time.
+//
+// A Cluster keeps one clock.FakeClock as the global, true time. Each
+// Node's clock follows it with its own offset and drift, and messages
+// are delivered once the global clock has moved past their delay, so
+// moving the global clock moves every node's view of time, and the
+// network's, consistently.
+package cluster
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// DelayFunc returns how long, in global time, a message from one node
+// to another takes to be delivered. A negative delay drops the message.
+type DelayFunc func(from, to string) time.Duration
+
+// FixedDelay returns a DelayFunc that delays every message by d.
+func FixedDelay(d time.Duration) DelayFunc {
+	return func(from, to string) time.Duration {
+		return d
+	}
+}
+
+// UniformDelay returns a DelayFunc that delays each message by a
+// pseudo-random duration between minDelay and maxDelay, picked using
+// seed so that runs are repeatable. It panics if maxDelay is less than
+// minDelay.
+func UniformDelay(minDelay, maxDelay time.Duration, seed int64) DelayFunc {
+	if maxDelay < minDelay {
+		panic("cluster: UniformDelay's maximum is less than its minimum")
+	}
+	var mu sync.Mutex
+	r := rand.New(rand.NewSource(seed))
+	return func(from, to string) time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		return minDelay + time.Duration(r.Int63n(int64(maxDelay-minDelay)+1))
+	}
+}
+
+// Option configures New.
+type Option func(*options)
+
+type options struct {
+	delay     DelayFunc
+	clockOpts []clock.Option
+}
+
+// WithDelay sets how long messages take to be delivered. The default is
+// no delay, which delivers them before Send returns.
+func WithDelay(d DelayFunc) Option {
+	return func(o *options) {
+		o.delay = d
+	}
+}
+
+// WithClockOptions sets the options the global clock is made with, such
+// as clock.WithStart.
+func WithClockOptions(opts ...clock.Option) Option {
+	return func(o *options) {
+		o.clockOpts = append(o.clockOpts, opts...)
+	}
+}
+
+// Cluster is a simulated cluster of Nodes. It is safe for concurrent
+// use.
+type Cluster struct {
+	global clock.FakeClock
+	delay  DelayFunc
+
+	mu    sync.Mutex
+	nodes []*Node
+	names map[string]*Node
+}
+
+// New returns a Cluster with no Nodes. Its global clock is made by
+// clock.NewFake with clock.WithSynchronousAfterFunc, so that moving it
+// delivers messages, and calls the Nodes' AfterFunc functions, in order
+// before returning. Like the global clock's, Node clocks' AfterFunc
+// functions that are already due when they are set are called in a new
+// goroutine.
+func New(opts ...Option) *Cluster {
+	o := options{delay: FixedDelay(0)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Cluster{
+		global: clock.NewFake(append(o.clockOpts, clock.WithSynchronousAfterFunc())...),
+		delay:  o.delay,
+		names:  make(map[string]*Node),
+	}
+}
+
+// Global returns the Cluster's global clock, which tests move to move
+// the whole Cluster's time.
+func (c *Cluster) Global() clock.FakeClock {
+	return c.global
+}
+
+// Advance moves the global clock forward by d, as its Add does, firing
+// the Nodes' Timers and delivering messages in global time order.
+func (c *Cluster) Advance(d time.Duration) {
+	c.global.Add(d)
+}
+
+// AddNode adds a Node named name whose clock, from the global clock's
+// current time, is ahead of it by offset and gains ppm microseconds on
+// it for every second of global time, as clock.Drift does. It panics if
+// the Cluster already has a Node named name.
+func (c *Cluster) AddNode(name string, offset time.Duration, ppm float64) *Node {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.names[name]; ok {
+		panic(fmt.Sprintf("cluster: a node named %q already exists", name))
+	}
+	n := &Node{
+		c:    c,
+		name: name,
+		clk:  clock.Offset(clock.Drift(c.global, ppm), offset),
+	}
+	c.nodes = append(c.nodes, n)
+	c.names[name] = n
+	return n
+}
+
+// Node returns the Node named name, or nil if there is none.
+func (c *Cluster) Node(name string) *Node {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.names[name]
+}
+
+// Nodes returns the Cluster's Nodes in the order they were added.
+func (c *Cluster) Nodes() []*Node {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Node(nil), c.nodes...)
+}
+
+// Message is a message between Nodes.
+type Message struct {
+	From, To string
+	Payload  any
+
+	// Sent and Delivered are the global clock's times when the message
+	// was sent and delivered.
+	Sent, Delivered time.Time
+}
+
+// Node is a member of a Cluster, with its own clock.
+type Node struct {
+	c    *Cluster
+	name string
+	clk  clock.Clock
+
+	mu      sync.Mutex
+	handler func(Message)
+}
+
+// Name returns the Node's name.
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Clock returns the Node's clock, for the code under test running on
+// the Node to use.
+func (n *Node) Clock() clock.Clock {
+	return n.clk
+}
+
+// Skew returns how far ahead of the global clock the Node's clock is.
+func (n *Node) Skew() time.Duration {
+	return n.clk.Now().Round(0).Sub(n.c.global.Now().Round(0))
+}
+
+// Handle sets the function the Node's messages are delivered to,
+// replacing any set before. Messages delivered to a Node without one
+// are dropped.
+func (n *Node) Handle(fn func(Message)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handler = fn
+}
+
+// Send sends payload to the Node named to, which receives it once the
+// global clock has moved forward by the Cluster's delay for the pair,
+// or before Send returns if there is no delay. It panics if the Cluster
+// has no Node named to.
+func (n *Node) Send(to string, payload any) {
+	dst := n.c.Node(to)
+	if dst == nil {
+		panic(fmt.Sprintf("cluster: no node named %q", to))
+	}
+	d := n.c.delay(n.name, to)
+	if d < 0 {
+		return
+	}
+	m := Message{From: n.name, To: to, Payload: payload, Sent: n.c.global.Now()}
+	if d == 0 {
+		// The global clock would call an AfterFunc function that is
+		// already due in a new goroutine, out of order.
+		dst.deliver(m)
+		return
+	}
+	n.c.global.AfterFunc(d, func() { dst.deliver(m) })
+}
+
+// deliver passes m to the Node's handler, if it has one.
+func (n *Node) deliver(m Message) {
+	m.Delivered = n.c.global.Now()
+	n.mu.Lock()
+	fn := n.handler
+	n.mu.Unlock()
+	if fn != nil {
+		fn(m)
+	}
+}
+
+// Broadcast sends payload to every other Node in the Cluster, in the
+// order they were added.
+func (n *Node) Broadcast(payload any) {
+	for _, dst := range n.c.Nodes() {
+		if dst != n {
+			n.Send(dst.name, payload)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+var start = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNodeClocks(t *testing.T) {
+	c := New(WithClockOptions(clock.WithStart(start)))
+	a := c.AddNode("a", 0, 0)
+	b := c.AddNode("b", time.Second, 0)
+	d := c.AddNode("d", -time.Second, 100)
+
+	if got := b.Clock().Now(); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("b's Now = %v, want %v", got, start.Add(time.Second))
+	}
+	c.Advance(100 * time.Second)
+	for _, tc := range []struct {
+		n    *Node
+		want time.Duration
+	}{
+		{a, 0},
+		{b, time.Second},
+		// 100 seconds at 100ppm is 10ms.
+		{d, -time.Second + 10*time.Millisecond},
+	} {
+		if got := tc.n.Skew(); got != tc.want {
+			t.Errorf("%s's Skew = %v, want %v", tc.n.Name(), got, tc.want)
+		}
+	}
+
+	if got := c.Nodes(); len(got) != 3 || got[0] != a || got[1] != b || got[2] != d {
+		t.Errorf("Nodes = %v, want a, b, d", got)
+	}
+	if c.Node("b") != b || c.Node("x") != nil {
+		t.Error("Node didn't find the Nodes by name")
+	}
+}
+
+func TestNodeTimers(t *testing.T) {
+	// A fast Node's Timers fire sooner in global time.
+	c := New()
+	slow := c.AddNode("slow", 0, 0)
+	fast := c.AddNode("fast", 0, 1e5)
+	var order []string
+	slow.Clock().AfterFunc(time.Second, func() { order = append(order, "slow") })
+	fast.Clock().AfterFunc(time.Second, func() { order = append(order, "fast") })
+	c.Advance(time.Second)
+	if len(order) != 2 || order[0] != "fast" || order[1] != "slow" {
+		t.Errorf("Timers fired in the order %v, want fast, slow", order)
+	}
+}
+
+func TestSend(t *testing.T) {
+	c := New(WithDelay(func(from, to string) time.Duration {
+		if to == "down" {
+			return -1
+		}
+		return 50 * time.Millisecond
+	}))
+	a := c.AddNode("a", 0, 0)
+	b := c.AddNode("b", 0, 0)
+	c.AddNode("down", 0, 0)
+	var got []Message
+	b.Handle(func(m Message) {
+		got = append(got, m)
+		c.Node(m.To).Send(m.From, "pong")
+	})
+	a.Handle(func(m Message) { got = append(got, m) })
+
+	sent := c.Global().Now()
+	a.Broadcast("ping")
+	c.Advance(49 * time.Millisecond)
+	if len(got) != 0 {
+		t.Fatalf("delivered %v before the delay", got)
+	}
+	c.Advance(time.Millisecond)
+	if len(got) != 1 || got[0].Payload != "ping" || got[0].From != "a" || !got[0].Sent.Equal(sent) || !got[0].Delivered.Equal(sent.Add(50*time.Millisecond)) {
+		t.Fatalf("delivered %+v, want a's ping to b after 50ms", got)
+	}
+	c.Advance(50 * time.Millisecond)
+	if len(got) != 2 || got[1].Payload != "pong" || got[1].To != "a" {
+		t.Fatalf("delivered %+v, want b's pong to a", got)
+	}
+	if n := c.Global().Waiters(); n != 0 {
+		t.Errorf("%d messages still waiting, want the one to down dropped", n)
+	}
+}
+
+func TestUniformDelay(t *testing.T) {
+	d1 := UniformDelay(time.Millisecond, 10*time.Millisecond, 1)
+	d2 := UniformDelay(time.Millisecond, 10*time.Millisecond, 1)
+	for i := 0; i < 100; i++ {
+		got := d1("a", "b")
+		if got < time.Millisecond || got > 10*time.Millisecond {
+			t.Fatalf("UniformDelay gave %v, want between 1ms and 10ms", got)
+		}
+		if other := d2("a", "b"); other != got {
+			t.Fatalf("UniformDelay with the same seed gave %v and %v", got, other)
+		}
+	}
+}
+
+func TestAddNodePanics(t *testing.T) {
+	c := New()
+	c.AddNode("a", 0, 0)
+	defer func() {
+		if recover() == nil {
+			t.Error("adding a second node named a didn't panic")
+		}
+	}()
+	c.AddNode("a", 0, 0)
+}
+
+func TestNodeTimersDue(t *testing.T) {
+	// Expired lease and election timers are already due when they are
+	// set, which must not deadlock the Node's clock.
+	c := New()
+	n := c.AddNode("a", time.Second, 50)
+	clk := n.Clock()
+	<-clk.After(0)
+	<-clk.After(-time.Second)
+	tm := clk.NewTimer(time.Second)
+	c.Advance(time.Second)
+	<-tm.C()
+	tm.Reset(0)
+	<-tm.C()
+	done := make(chan struct{})
+	clk.AfterFunc(0, func() { close(done) })
+	<-done
+}
+
+func TestSendNoDelay(t *testing.T) {
+	c := New()
+	a := c.AddNode("a", 0, 0)
+	b := c.AddNode("b", 0, 0)
+	var got []any
+	b.Handle(func(m Message) { got = append(got, m.Payload) })
+	for i := 0; i < 3; i++ {
+		a.Send("b", i)
+	}
+	if len(got) != 3 || got[0] != 0 || got[1] != 1 || got[2] != 2 {
+		t.Errorf("delivered %v, want 0, 1, 2 before Send returned", got)
+	}
+}
@@ -0,0 +1,104 @@
+package clock
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CoarseClock is a Clock that reads the system time only every so
+// often, as returned by Coarse.
+type CoarseClock interface {
+	Clock
+
+	// Stop stops the goroutine refreshing the clock's time. From then
+	// on, reading the clock reads the system time directly, as
+	// Default does.
+	Stop()
+}
+
+// Coarse returns a CoarseClock whose time is the system time as of at
+// most resolution ago. A goroutine reads the system time every
+// resolution and Now returns the last reading, which costs no more than
+// an atomic load, for servers that read the time so often that reading
+// the system time shows up in profiles. NowUnix, NowMonotonic, Since and
+// Until are computed from the same readings. Sleep and the clock's
+// Timers and Tickers are the system's, as Default's are. Coarse panics
+// if resolution is not positive.
+func Coarse(resolution time.Duration) CoarseClock {
+	c := &coarse{
+		Clock:   systemClock,
+		ticker:  time.NewTicker(resolution),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	now := time.Now()
+	c.now.Store(&now)
+	go c.refresh()
+	return c
+}
+
+type coarse struct {
+	Clock
+
+	// now is the last reading of the system time, or nil once the
+	// clock has been stopped.
+	now atomic.Pointer[time.Time]
+
+	ticker  *time.Ticker
+	done    chan struct{}
+	stopped chan struct{}
+	stop    sync.Once
+}
+
+func (c *coarse) refresh() {
+	defer close(c.stopped)
+	defer c.now.Store(nil)
+	for {
+		select {
+		case now := <-c.ticker.C:
+			c.now.Store(&now)
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *coarse) Stop() {
+	c.stop.Do(func() {
+		c.ticker.Stop()
+		close(c.done)
+		<-c.stopped
+	})
+}
+
+func (c *coarse) Now() time.Time {
+	if now := c.now.Load(); now != nil {
+		return *now
+	}
+	return time.Now()
+}
+
+func (c *coarse) NowUnix() int64 {
+	return c.Now().Unix()
+}
+
+func (c *coarse) NowUnixMilli() int64 {
+	return c.Now().UnixMilli()
+}
+
+func (c *coarse) NowUnixNano() int64 {
+	return c.Now().UnixNano()
+}
+
+func (c *coarse) NowMonotonic() time.Duration {
+	return c.Now().Sub(monotonicBase)
+}
+
+func (c *coarse) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *coarse) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
@@ -0,0 +1,32 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoarse(t *testing.T) {
+	clk := Coarse(time.Millisecond)
+	defer clk.Stop()
+	first := clk.Now()
+	if d := time.Since(first); d < 0 || d > time.Second {
+		t.Fatalf("Coarse clock is %v behind the system clock", d)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for clk.Now().Equal(first) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Coarse clock never refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := clk.Now(); got.Before(first) {
+		t.Errorf("Coarse clock went backwards from %v to %v", first, got)
+	}
+
+	clk.Stop()
+	clk.Stop()
+	before := time.Now()
+	if got := clk.Now(); got.Before(before) {
+		t.Errorf("stopped Coarse clock's Now() = %v, want at least %v", got, before)
+	}
+}
@@ -0,0 +1,146 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// contextKey is the key for the Clock in a Context made by NewContext.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries clk, for code such as
+// deeply nested request handlers that has a Context but no Clock passed
+// to it. FromContext returns the Clock.
+func NewContext(ctx context.Context, clk Clock) context.Context {
+	return context.WithValue(ctx, contextKey{}, clk)
+}
+
+// FromContext returns the Clock carried by ctx, as set by NewContext,
+// or Default if it carries none.
+func FromContext(ctx context.Context) Clock {
+	if clk, ok := ctx.Value(contextKey{}).(Clock); ok {
+		return clk
+	}
+	return Default()
+}
+
+// WithDeadline is like context.WithDeadline, but the returned Context
+// expires once clk's time is at or after d, rather than the system's.
+// Its Deadline method returns d, or the parent's deadline if that is
+// earlier. Once it expires, its Done channel is closed and its Err
+// method returns context.DeadlineExceeded, as do those of the Contexts
+// derived from it. Canceling it, or its parent being canceled first,
+// stops the Timer behind it, as with context.WithDeadline.
+//
+// If clk is a FakeClock, or one of its Children, the Context expires
+// during the call to Add or Set that moves the clock to d, before the
+// call returns. Its Timer is labeled "context deadline", as by Labeled,
+// for ActiveTimers and AssertNoPending.
+func WithDeadline(parent context.Context, clk Clock, d time.Time) (context.Context, context.CancelFunc) {
+	if _, ok := clk.(sysClock); ok {
+		return context.WithDeadline(parent, d)
+	}
+	if f, ok := clk.(*fake); ok {
+		clk = Labeled(f, "context deadline")
+	}
+	inner, cancel := context.WithCancelCause(parent)
+	c := &clockContext{
+		Context:  inner,
+		parent:   parent,
+		clk:      clk,
+		deadline: d,
+		done:     make(chan struct{}),
+		cancel:   cancel,
+	}
+	if pd, ok := parent.Deadline(); ok && pd.Before(d) {
+		c.deadline = pd
+	}
+	left := clk.Until(d)
+	if left <= 0 {
+		c.end(context.DeadlineExceeded)
+		return c, func() {}
+	}
+	t := clk.AfterFunc(left, func() { c.end(context.DeadlineExceeded) })
+	// Catch the parent being canceled.
+	stop := context.AfterFunc(inner, func() {
+		t.Stop()
+		c.end(nil)
+	})
+	return c, func() {
+		t.Stop()
+		stop()
+		c.end(context.Canceled)
+	}
+}
+
+// WithTimeout returns WithDeadline(parent, clk, clk.Now().Add(timeout)).
+func WithTimeout(parent context.Context, clk Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(parent, clk, clk.Now().Add(timeout))
+}
+
+// clockContext is a Context returned by WithDeadline for a Clock other
+// than the system's. It embeds a Context made by
+// context.WithCancelCause, which it cancels with
+// context.DeadlineExceeded as the cause when it expires, so that
+// context.Cause and Value work as usual. But it has its own Done
+// channel, so that Contexts derived from it are canceled through its
+// AfterFunc method, and so get their errors from its Err.
+type clockContext struct {
+	context.Context
+	parent   context.Context
+	clk      Clock
+	deadline time.Time
+
+	done   chan struct{}
+	cancel context.CancelCauseFunc
+	once   sync.Once
+}
+
+// end cancels the Context with cause, or with its parent's cause if
+// cause is nil, and closes its Done channel. Only the first call has
+// any effect.
+func (c *clockContext) end(cause error) {
+	c.once.Do(func() {
+		if cause != nil {
+			c.cancel(cause)
+		}
+		close(c.done)
+	})
+}
+
+func (c *clockContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *clockContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *clockContext) Err() error {
+	select {
+	case <-c.done:
+	default:
+		return nil
+	}
+	err := c.Context.Err()
+	if context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// AfterFunc arranges to call f once the Context is done, as
+// context.AfterFunc does. context.WithCancel and the like use it to
+// cancel the Contexts derived from this one.
+func (c *clockContext) AfterFunc(f func()) (stop func() bool) {
+	return context.AfterFunc(c.Context, func() {
+		<-c.done
+		f()
+	})
+}
+
+func (c *clockContext) String() string {
+	return fmt.Sprintf("%v.WithDeadline(%v [%v])", c.parent, c.deadline, c.clk.Until(c.deadline))
+}
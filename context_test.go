@@ -0,0 +1,117 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if FromContext(ctx) != Default() {
+		t.Errorf("FromContext of a Context without a Clock did not return Default()")
+	}
+	fc := NewFake()
+	ctx = NewContext(ctx, fc)
+	if FromContext(ctx) != fc {
+		t.Errorf("FromContext did not return the Clock given to NewContext")
+	}
+	if FromContext(NewContext(ctx, nil)) != Default() {
+		t.Errorf("FromContext of a Context with a nil Clock did not return Default()")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	fc := NewFake()
+	ctx, cancel := WithTimeout(context.Background(), fc, time.Minute)
+	defer cancel()
+	if d, ok := ctx.Deadline(); !ok || !d.Equal(fc.Now().Add(time.Minute)) {
+		t.Errorf("Deadline() = %v, %t, want %v, true", d, ok, fc.Now().Add(time.Minute))
+	}
+	fc.Add(59 * time.Second)
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("Err() before the deadline = %v", err)
+	}
+	fc.Add(time.Second)
+	<-ctx.Done()
+	if err := ctx.Err(); err != context.DeadlineExceeded {
+		t.Errorf("Err() after the deadline = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := context.Cause(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Cause() after the deadline = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel = WithDeadline(context.Background(), fc, fc.Now())
+	defer cancel()
+	if err := ctx.Err(); err != context.DeadlineExceeded {
+		t.Errorf("Err() of a Context made past its deadline = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel = WithTimeout(context.Background(), fc, time.Minute)
+	cancel()
+	if err := ctx.Err(); err != context.Canceled {
+		t.Errorf("Err() after cancel = %v, want %v", err, context.Canceled)
+	}
+	if n := fc.Waiters(); n != 0 {
+		t.Errorf("got %d waiters after cancel, want 0", n)
+	}
+
+	ctx, cancel = WithTimeout(context.Background(), Default(), time.Hour)
+	defer cancel()
+	if d, ok := ctx.Deadline(); !ok || Default().Until(d) <= 0 {
+		t.Errorf("Deadline() of a Context from Default = %v, %t", d, ok)
+	}
+}
+
+func TestWithTimeoutFakeParents(t *testing.T) {
+	fc := NewFake()
+	parent, cancel := WithTimeout(context.Background(), fc, time.Minute)
+	defer cancel()
+	middle, cancelMiddle := context.WithCancel(parent)
+	defer cancelMiddle()
+	child, cancelChild := WithTimeout(middle, fc, time.Hour)
+	defer cancelChild()
+
+	if d, _ := child.Deadline(); !d.Equal(fc.Now().Add(time.Minute)) {
+		t.Errorf("child's Deadline() = %v, want its parent's %v", d, fc.Now().Add(time.Minute))
+	}
+	infos := fc.ActiveTimers()
+	if len(infos) != 2 || infos[0].Label != "context deadline" || infos[0].Kind != FuncWaiter {
+		t.Errorf("ActiveTimers() = %v, want two labeled AfterFunc functions", infos)
+	}
+	if got, want := parent.(fmt.Stringer).String(), "context.Background.WithDeadline(1970-01-01 00:01:00 +0000 UTC [1m0s])"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	fc.Add(time.Minute)
+	select {
+	case <-parent.Done():
+	default:
+		t.Fatalf("parent not done once Add returned")
+	}
+	<-middle.Done()
+	<-child.Done()
+	for name, ctx := range map[string]context.Context{"parent": parent, "middle": middle, "child": child} {
+		if err := ctx.Err(); err != context.DeadlineExceeded {
+			t.Errorf("%s's Err() = %v, want %v", name, err, context.DeadlineExceeded)
+		}
+	}
+
+	// Canceling a parent stops the Timer behind its children.
+	base, cancelBase := context.WithCancel(context.Background())
+	ctx, cancel := WithTimeout(base, fc, time.Minute)
+	defer cancel()
+	cancelBase()
+	<-ctx.Done()
+	if err := ctx.Err(); err != context.Canceled {
+		t.Errorf("Err() after the parent was canceled = %v, want %v", err, context.Canceled)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for fc.Waiters() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timer behind a Context whose parent was canceled was never stopped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,72 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Deadline is an instant in time as seen by a Clock, along with the
+// bookkeeping usually written around one. Create one with DeadlineIn
+// or DeadlineAt.
+type Deadline struct {
+	clk Clock
+	t   time.Time
+
+	once  sync.Once
+	timer Timer
+}
+
+// DeadlineIn returns a Deadline that expires once d has elapsed on clk.
+func DeadlineIn(clk Clock, d time.Duration) *Deadline {
+	return &Deadline{clk: clk, t: clk.Now().Add(d)}
+}
+
+// DeadlineAt returns a Deadline that expires once clk's time is at or
+// after t.
+func DeadlineAt(clk Clock, t time.Time) *Deadline {
+	return &Deadline{clk: clk, t: t}
+}
+
+// Time returns the instant at which the Deadline expires.
+func (dl *Deadline) Time() time.Time {
+	return dl.t
+}
+
+// Remaining returns how long is left until the Deadline expires,
+// or zero if it has already expired.
+func (dl *Deadline) Remaining() time.Duration {
+	r := dl.clk.Until(dl.t)
+	if r < 0 {
+		return 0
+	}
+	return r
+}
+
+// Expired reports whether the Deadline's clock has reached its time.
+func (dl *Deadline) Expired() bool {
+	return !dl.clk.Now().Before(dl.t)
+}
+
+// Channel returns a channel that is sent the current time once the
+// Deadline expires. Every call returns the same channel, and only one
+// time is ever sent on it. The timer behind the channel is created on
+// the first call and can be released early with Stop. If Stop is
+// called before Channel, Channel returns nil.
+func (dl *Deadline) Channel() <-chan time.Time {
+	dl.once.Do(func() {
+		dl.timer = dl.clk.NewTimerAt(dl.t)
+	})
+	if dl.timer == nil {
+		return nil
+	}
+	return dl.timer.C()
+}
+
+// Stop releases the timer behind the Deadline's Channel, if one was
+// created. The channel will not be sent on afterwards.
+func (dl *Deadline) Stop() {
+	dl.once.Do(func() {})
+	if dl.timer != nil {
+		dl.timer.Stop()
+	}
+}
@@ -0,0 +1,64 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadline(t *testing.T) {
+	clk := NewFake()
+	dl := DeadlineIn(clk, time.Minute)
+	if !DeadlineAt(clk, dl.Time()).Time().Equal(dl.Time()) {
+		t.Errorf("DeadlineAt and DeadlineIn disagree on the deadline")
+	}
+
+	clk.Add(20 * time.Second)
+	if dl.Expired() {
+		t.Errorf("deadline expired early")
+	}
+	if got := dl.Remaining(); got != 40*time.Second {
+		t.Errorf("Remaining: got %v, want %v", got, 40*time.Second)
+	}
+	c := dl.Channel()
+	if dl.Channel() != c {
+		t.Errorf("Channel should return the same channel every time")
+	}
+	select {
+	case <-c:
+		t.Fatalf("Channel fired early")
+	default:
+	}
+
+	clk.Add(time.Hour)
+	if !dl.Expired() {
+		t.Errorf("deadline should have expired")
+	}
+	if got := dl.Remaining(); got != 0 {
+		t.Errorf("Remaining after expiry: got %v, want 0", got)
+	}
+	select {
+	case <-c:
+	default:
+		t.Fatalf("Channel did not fire after the deadline expired")
+	}
+}
+
+func TestDeadlineStop(t *testing.T) {
+	clk := NewFake()
+	dl := DeadlineIn(clk, time.Minute)
+	c := dl.Channel()
+	dl.Stop()
+	clk.Add(time.Hour)
+	select {
+	case <-c:
+		t.Fatalf("Channel fired after Stop")
+	default:
+	}
+
+	// Stopping before Channel is ever called is fine, too.
+	dl = DeadlineIn(clk, time.Minute)
+	dl.Stop()
+	if dl.Channel() != nil {
+		t.Errorf("Channel after Stop should be nil")
+	}
+}
@@ -0,0 +1,201 @@
+package clock
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WaiterKind is the kind of thing waiting on a FakeClock.
+type WaiterKind int
+
+const (
+	// TimerWaiter is a Timer, including those behind After and
+	// AfterAt.
+	TimerWaiter WaiterKind = iota
+
+	// SleepWaiter is a goroutine blocked in Sleep, SleepUntil or
+	// SleepContext.
+	SleepWaiter
+
+	// TickerWaiter is a Ticker, including those behind Tick.
+	TickerWaiter
+
+	// FuncWaiter is a function given to AfterFunc.
+	FuncWaiter
+)
+
+func (k WaiterKind) String() string {
+	switch k {
+	case TimerWaiter:
+		return "Timer"
+	case SleepWaiter:
+		return "sleeper"
+	case TickerWaiter:
+		return "Ticker"
+	case FuncWaiter:
+		return "AfterFunc"
+	}
+	return fmt.Sprintf("WaiterKind(%d)", int(k))
+}
+
+// TimerInfo describes something waiting on a FakeClock, as returned by
+// its ActiveTimers method.
+type TimerInfo struct {
+	Kind WaiterKind
+
+	// Deadline is when it next fires.
+	Deadline time.Time
+
+	// Period is how often a Ticker ticks. It is zero for everything
+	// else.
+	Period time.Duration
+
+	// Label is the label of the Labeled clock it was made with, if
+	// any.
+	Label string
+
+	// Stack is the stack trace of the code that created it, starting
+	// at the call into the clock. It is empty unless the clock was made
+	// with WithCreationStacks or WithWatchdog.
+	Stack string
+}
+
+func (ti TimerInfo) String() string {
+	var s string
+	switch ti.Kind {
+	case TickerWaiter:
+		s = fmt.Sprintf("Ticker every %v, next at %v", ti.Period, ti.Deadline)
+	case SleepWaiter:
+		s = fmt.Sprintf("sleeper until %v", ti.Deadline)
+	default:
+		s = fmt.Sprintf("%v at %v", ti.Kind, ti.Deadline)
+	}
+	if ti.Label != "" {
+		s = fmt.Sprintf("%q %s", ti.Label, s)
+	}
+	return s
+}
+
+func (f *fake) ActiveTimers() []TimerInfo {
+	_, _, infos := f.state()
+	return infos
+}
+
+func (f *fake) String() string {
+	now, _, infos := f.state()
+	var b strings.Builder
+	fmt.Fprintf(&b, "FakeClock at %v with %d waiters", now, len(infos))
+	for i, info := range infos {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(info.String())
+	}
+	return b.String()
+}
+
+func (f *fake) Dump(w io.Writer) {
+	now, mono, infos := f.state()
+	fmt.Fprintf(w, "FakeClock at %v (monotonic %v) with %d waiters\n", now, mono, len(infos))
+	for _, info := range infos {
+		if info.Stack == "" {
+			fmt.Fprintf(w, "\n%s\n", info)
+			continue
+		}
+		fmt.Fprintf(w, "\n%s, created at:\n", info)
+		for _, line := range strings.SplitAfter(info.Stack, "\n") {
+			if line != "" {
+				fmt.Fprintf(w, "\t%s", line)
+			}
+		}
+	}
+}
+
+// state returns the clock's time, monotonic time and everything waiting
+// on it, in deadline order, all read at the same instant.
+func (f *fake) state() (time.Time, time.Duration, []TimerInfo) {
+	f.RLock()
+	now, mono := f.t, f.mono
+	infos := make([]TimerInfo, 0, len(f.timers))
+	for _, ft := range f.timers {
+		infos = append(infos, ft.info())
+	}
+	f.RUnlock()
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].Deadline.Before(infos[j].Deadline)
+	})
+	return now, mono, infos
+}
+
+// info describes the timer. It must be called with the clock's lock
+// held.
+func (ft *fakeTimer) info() TimerInfo {
+	return TimerInfo{
+		Kind:     ft.kind,
+		Deadline: ft.until,
+		Period:   ft.period,
+		Label:    ft.tag.label,
+		Stack:    formatStack(ft.created),
+	}
+}
+
+// pkgPrefix is the prefix of the names of this package's functions,
+// such as "github.com/jmhodges/clock.".
+var pkgPrefix = strings.TrimSuffix(runtime.FuncForPC(reflect.ValueOf(NewFake).Pointer()).Name(), "NewFake")
+
+// internalTypes are the types whose methods callers skips over, so
+// that creation stacks start where the code using the clock called
+// into it.
+var internalTypes = []string{"(*fake).", "(*child).", "(*fakeTimer).", "fakeTicker.", "SleepContextWith"}
+
+// callers returns the stack of its caller's caller, for formatStack.
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	return pcs[:runtime.Callers(3, pcs)]
+}
+
+// formatStack formats pcs like a goroutine's stack trace in a panic,
+// leaving out the frames inside the clock's implementation.
+func formatStack(pcs []uintptr) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	skipping := true
+	for {
+		fr, more := frames.Next()
+		if skipping && isInternal(fr.Function) {
+			if !more {
+				break
+			}
+			continue
+		}
+		skipping = false
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", fr.Function, fr.File, fr.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+func isInternal(function string) bool {
+	name, ok := strings.CutPrefix(function, pkgPrefix)
+	if !ok {
+		return false
+	}
+	for _, t := range internalTypes {
+		if strings.HasPrefix(name, t) {
+			return true
+		}
+	}
+	return false
+}
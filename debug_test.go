@@ -0,0 +1,86 @@
+package clock
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActiveTimers(t *testing.T) {
+	clk := NewFake(WithCreationStacks())
+	start := clk.Now()
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+	clk.AfterFunc(time.Hour, func() {})
+	clk.After(time.Minute)
+
+	infos := clk.ActiveTimers()
+	if len(infos) != 3 {
+		t.Fatalf("got %d active timers, want 3", len(infos))
+	}
+	want := []TimerInfo{
+		{Kind: TickerWaiter, Deadline: start.Add(time.Second), Period: time.Second},
+		{Kind: TimerWaiter, Deadline: start.Add(time.Minute)},
+		{Kind: FuncWaiter, Deadline: start.Add(time.Hour)},
+	}
+	for i, info := range infos {
+		if info.Kind != want[i].Kind || !info.Deadline.Equal(want[i].Deadline) || info.Period != want[i].Period {
+			t.Errorf("timer %d: got %v, want %v", i, info, want[i])
+		}
+		if !strings.HasPrefix(info.Stack, pkgPrefix+"TestActiveTimers") {
+			t.Errorf("timer %d: stack should start at the creating call, but was:\n%s", i, info.Stack)
+		}
+	}
+}
+
+func TestFakeClockString(t *testing.T) {
+	clk := NewFake(WithCreationStacks())
+	if got, want := clk.String(), "FakeClock at 1970-01-01 00:00:00 +0000 UTC with 0 waiters"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	tk := clk.NewTicker(time.Minute)
+	defer tk.Stop()
+	tm := Labeled(clk, "retry").NewTimer(time.Second)
+	defer tm.Stop()
+	want := `FakeClock at 1970-01-01 00:00:00 +0000 UTC with 2 waiters: "retry" Timer at 1970-01-01 00:00:01 +0000 UTC, Ticker every 1m0s, next at 1970-01-01 00:01:00 +0000 UTC`
+	if got := clk.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	var b strings.Builder
+	clk.Dump(&b)
+	dump := b.String()
+	for _, want := range []string{
+		"FakeClock at 1970-01-01 00:00:00 +0000 UTC (monotonic 0s) with 2 waiters\n",
+		"\n\"retry\" Timer at 1970-01-01 00:00:01 +0000 UTC, created at:\n\t" + pkgPrefix + "TestFakeClockString\n\t\t",
+		"\nTicker every 1m0s, next at 1970-01-01 00:01:00 +0000 UTC, created at:\n\t" + pkgPrefix + "TestFakeClockString\n\t\t",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("Dump wrote %q, want it to contain %q", dump, want)
+		}
+	}
+}
+
+func TestActiveTimersStacks(t *testing.T) {
+	// Stacks are only recorded when asked for.
+	clk := NewFake()
+	clk.After(time.Minute)
+	if info := clk.ActiveTimers()[0]; info.Stack != "" {
+		t.Errorf("Stack without WithCreationStacks = %q, want none", info.Stack)
+	}
+	var b strings.Builder
+	clk.Dump(&b)
+	if strings.Contains(b.String(), "created at") {
+		t.Errorf("Dump without WithCreationStacks wrote %q, want no stacks", b.String())
+	}
+
+	clk = NewFake(WithCreationStacks())
+	go func() { clk.SleepContext(context.Background(), time.Minute) }()
+	clk.BlockUntil(1)
+	defer clk.Add(time.Minute)
+	if info := clk.ActiveTimers()[0]; !strings.HasPrefix(info.Stack, pkgPrefix+"TestActiveTimersStacks") {
+		t.Errorf("SleepContext's stack should start at the sleeping call, but was:\n%s", info.Stack)
+	}
+}
@@ -0,0 +1,323 @@
+package clock
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// derived is a Clock whose time is a linear function of a base Clock's.
+// It backs NewFreezable, Offset and Scale. Its Timers, Tickers, AfterFunc
+// functions and sleepers are kept by the derived clock itself, and each
+// is fired by a Timer of the base clock that is replaced whenever the
+// function changes.
+type derived struct {
+	sync.RWMutex
+	base Clock
+
+	// The clock's time is t, and its monotonic reading mono, when the
+	// base clock's are baseT and baseMono. From then on, the clock
+	// moves rate times as fast as the base clock. A rate of zero
+	// stops it.
+	t, baseT       time.Time
+	mono, baseMono time.Duration
+	rate           float64
+
+	// timers is the set of active Timers, Tickers, AfterFunc functions
+	// and sleepers.
+	timers map[*derivedTimer]bool
+}
+
+// newDerived returns a derived clock that is offset ahead of base and
+// moves at rate times its speed.
+func newDerived(base Clock, offset time.Duration, rate float64) *derived {
+	f := &derived{base: base, rate: rate, timers: make(map[*derivedTimer]bool)}
+	f.baseT, f.baseMono = base.Now(), base.NowMonotonic()
+	f.t, f.mono = f.baseT.Add(offset), f.baseMono
+	return f
+}
+
+// setRate changes how fast the clock moves relative to its base clock,
+// from its current time on, and rearms everything waiting on it to
+// match. It must be called with f's lock held.
+func (f *derived) setRate(rate float64) {
+	f.t, f.mono = f.now(), f.nowMonotonic()
+	f.baseT, f.baseMono = f.base.Now(), f.base.NowMonotonic()
+	f.rate = rate
+	for ft := range f.timers {
+		ft.arm()
+	}
+}
+
+// scale converts a duration of the base clock to one of this clock.
+func (f *derived) scale(d time.Duration) time.Duration {
+	if f.rate == 1 {
+		return d
+	}
+	return time.Duration(float64(d) * f.rate)
+}
+
+// unscale converts a duration of this clock to one of the base clock,
+// rounding up so that what waits on the clock never fires early. The
+// clock's rate must not be zero.
+func (f *derived) unscale(d time.Duration) time.Duration {
+	if f.rate == 1 {
+		return d
+	}
+	return time.Duration(math.Ceil(float64(d) / f.rate))
+}
+
+func (f *derived) Now() time.Time {
+	f.RLock()
+	defer f.RUnlock()
+	return f.now()
+}
+
+// now returns the clock's time. It must be called with f's lock held.
+func (f *derived) now() time.Time {
+	switch f.rate {
+	case 0:
+		return f.t
+	case 1:
+		// Follow any jumps of the base clock's wall clock, as an
+		// offset from it.
+		return f.base.Now().Add(f.t.Sub(f.baseT))
+	}
+	return f.t.Add(f.scale(f.base.Now().Sub(f.baseT)))
+}
+
+func (f *derived) NowUnix() int64 {
+	return f.Now().Unix()
+}
+
+func (f *derived) NowUnixMilli() int64 {
+	return f.Now().UnixMilli()
+}
+
+func (f *derived) NowUnixNano() int64 {
+	return f.Now().UnixNano()
+}
+
+func (f *derived) NowMonotonic() time.Duration {
+	f.RLock()
+	defer f.RUnlock()
+	return f.nowMonotonic()
+}
+
+// nowMonotonic returns the clock's monotonic reading. It must be called
+// with f's lock held.
+func (f *derived) nowMonotonic() time.Duration {
+	return f.mono + f.scale(f.base.NowMonotonic()-f.baseMono)
+}
+
+func (f *derived) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *derived) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
+
+func (f *derived) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-f.NewTimer(d).C()
+}
+
+func (f *derived) SleepUntil(t time.Time) {
+	<-f.NewTimerAt(t).C()
+}
+
+func (f *derived) SleepContext(ctx context.Context, d time.Duration) error {
+	return SleepContextWith(ctx, d, f.NewTimer)
+}
+
+func (f *derived) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *derived) AfterAt(t time.Time) <-chan time.Time {
+	return f.NewTimerAt(t).C()
+}
+
+func (f *derived) NewTimer(d time.Duration) Timer {
+	f.Lock()
+	defer f.Unlock()
+	return f.start(&derivedTimer{clk: f, c: make(chan time.Time, 1)}, f.now().Add(d))
+}
+
+func (f *derived) NewTimerAt(t time.Time) Timer {
+	f.Lock()
+	defer f.Unlock()
+	return f.start(&derivedTimer{clk: f, c: make(chan time.Time, 1)}, t)
+}
+
+func (f *derived) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	f.Lock()
+	defer f.Unlock()
+	ft := &derivedTimer{clk: f, c: make(chan time.Time, 1), period: d}
+	return derivedTicker{f.start(ft, f.now().Add(d))}
+}
+
+func (f *derived) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return f.NewTicker(d).C()
+}
+
+func (f *derived) AfterFunc(d time.Duration, fn func()) Timer {
+	f.Lock()
+	defer f.Unlock()
+	return f.start(&derivedTimer{clk: f, fn: fn}, f.now().Add(d))
+}
+
+// start makes ft active with the deadline until. It must be called with
+// f's lock held.
+func (f *derived) start(ft *derivedTimer, until time.Time) *derivedTimer {
+	ft.until = until
+	f.timers[ft] = true
+	ft.arm()
+	return ft
+}
+
+// derivedTimer is a Timer, Ticker, AfterFunc function or sleeper
+// waiting on a derived clock.
+type derivedTimer struct {
+	clk    *derived
+	c      chan time.Time
+	fn     func()
+	until  time.Time
+	period time.Duration
+
+	// bt is the base clock's Timer that fires this one, if any.
+	bt Timer
+
+	// gen is incremented every time bt is replaced or stopped, so
+	// that a base Timer that fired just before then does nothing.
+	gen int
+}
+
+// arm starts a base Timer that fires ft at its deadline, unless the
+// clock is stopped and the deadline is still to come. A deadline that
+// has already passed fires ft in a new goroutine instead, since fire
+// takes the clock's lock and a base clock, such as a FakeClock with
+// WithSynchronousAfterFunc, may call the function it is given before
+// its AfterFunc returns. arm must be called with the clock's lock held.
+func (ft *derivedTimer) arm() {
+	ft.disarm()
+	gen := ft.gen
+	d := ft.until.Sub(ft.clk.now())
+	if d <= 0 {
+		go ft.fire(gen)
+		return
+	}
+	if ft.clk.rate == 0 {
+		return
+	}
+	ft.bt = ft.clk.base.AfterFunc(ft.clk.unscale(d), func() { ft.fire(gen) })
+}
+
+// disarm stops ft's base Timer, if any. It must be called with the
+// clock's lock held.
+func (ft *derivedTimer) disarm() {
+	ft.gen++
+	if ft.bt != nil {
+		ft.bt.Stop()
+		ft.bt = nil
+	}
+}
+
+func (ft *derivedTimer) fire(gen int) {
+	f := ft.clk
+	f.Lock()
+	if ft.gen != gen || !f.timers[ft] {
+		f.Unlock()
+		return
+	}
+	ft.bt = nil
+	if ft.fn != nil {
+		delete(f.timers, ft)
+		f.Unlock()
+		ft.fn()
+		return
+	}
+	defer f.Unlock()
+	now := f.now()
+	select {
+	case ft.c <- now:
+	default:
+	}
+	if ft.period == 0 {
+		delete(f.timers, ft)
+		return
+	}
+	// Like time.Ticker, drop the ticks that a slow receiver missed.
+	ft.until = ft.until.Add(ft.period)
+	if !ft.until.After(now) {
+		ft.until = now.Add(ft.period)
+	}
+	ft.arm()
+}
+
+func (ft *derivedTimer) C() <-chan time.Time {
+	return ft.c
+}
+
+func (ft *derivedTimer) Stop() bool {
+	ft.clk.Lock()
+	defer ft.clk.Unlock()
+	return ft.halt()
+}
+
+func (ft *derivedTimer) Reset(d time.Duration) bool {
+	ft.clk.Lock()
+	defer ft.clk.Unlock()
+	active := ft.halt()
+	ft.clk.start(ft, ft.clk.now().Add(d))
+	return active
+}
+
+// halt makes ft inactive, discarding any time sent on its channel but
+// not yet received, and reports whether it was active or had a time
+// discarded, as a FakeClock's Timers do. It must be called with the
+// clock's lock held.
+func (ft *derivedTimer) halt() bool {
+	active := ft.clk.timers[ft]
+	delete(ft.clk.timers, ft)
+	ft.disarm()
+	select {
+	case <-ft.c:
+		return true
+	default:
+	}
+	return active
+}
+
+type derivedTicker struct {
+	ft *derivedTimer
+}
+
+func (t derivedTicker) C() <-chan time.Time {
+	return t.ft.c
+}
+
+func (t derivedTicker) Stop() {
+	t.ft.Stop()
+}
+
+func (t derivedTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	t.ft.clk.Lock()
+	defer t.ft.clk.Unlock()
+	t.ft.halt()
+	t.ft.period = d
+	t.ft.clk.start(t.ft, t.ft.clk.now().Add(d))
+}
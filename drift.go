@@ -0,0 +1,11 @@
+package clock
+
+// Drift returns a Clock that starts at base's current time and from then
+// on gains ppm microseconds for every second of base's that passes, or
+// loses them if ppm is negative, like a real oscillator that runs fast
+// or slow. A ppm of 200 gains 17.28 seconds a day. It is Scale(base,
+// 1+ppm/1e6), and so is for testing NTP corrections, lease safety
+// margins and heartbeat tolerances against realistic clock error.
+func Drift(base Clock, ppm float64) Clock {
+	return Scale(base, 1+ppm/1e6)
+}
@@ -0,0 +1,30 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDrift(t *testing.T) {
+	fc := NewFake()
+	start := fc.Now()
+	fast := Drift(fc, 200)
+	slow := Drift(fc, -50)
+	tm := fast.NewTimer(24 * time.Hour)
+
+	fc.Add(24 * time.Hour)
+	if got, want := fast.Since(start), 24*time.Hour+17280*time.Millisecond; got != want {
+		t.Errorf("fast clock moved %v in a day, want %v", got, want)
+	}
+	if got, want := slow.Since(start), 24*time.Hour-4320*time.Millisecond; got != want {
+		t.Errorf("slow clock moved %v in a day, want %v", got, want)
+	}
+	select {
+	case got := <-tm.C():
+		if got.Before(start.Add(24 * time.Hour)) {
+			t.Errorf("fast clock's Timer fired early, at %v", got)
+		}
+	default:
+		t.Errorf("fast clock's Timer did not fire within a day of the base clock")
+	}
+}
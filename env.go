@@ -0,0 +1,45 @@
+package clock
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The environment variables read by programs built with the clockenv
+// build tag.
+const (
+	// EnvNow, if set, is the time, in RFC 3339 format, that Default
+	// starts at.
+	EnvNow = "CLOCK_FAKE_NOW"
+
+	// EnvRate, if set, is how many times as fast as the system clock
+	// Default moves, such as 0.5 or 60.
+	EnvRate = "CLOCK_FAKE_RATE"
+)
+
+// envClock returns the Clock described by the EnvNow and EnvRate
+// environment variables, as read by getenv, or nil if neither is set.
+func envClock(getenv func(string) string) (Clock, error) {
+	now, rate := getenv(EnvNow), getenv(EnvRate)
+	if now == "" && rate == "" {
+		return nil, nil
+	}
+	var offset time.Duration
+	if now != "" {
+		t, err := time.Parse(time.RFC3339Nano, now)
+		if err != nil {
+			return nil, fmt.Errorf("clock: invalid %s: %w", EnvNow, err)
+		}
+		offset = time.Until(t)
+	}
+	r := 1.0
+	if rate != "" {
+		var err error
+		r, err = strconv.ParseFloat(rate, 64)
+		if err != nil || r <= 0 {
+			return nil, fmt.Errorf("clock: invalid %s %q: must be a positive number", EnvRate, rate)
+		}
+	}
+	return newDerived(systemClock, offset, r), nil
+}
@@ -0,0 +1,20 @@
+//go:build clockenv
+
+package clock
+
+import "os"
+
+// Programs built with the clockenv build tag have Default follow the
+// EnvNow and EnvRate environment variables, so that black-box tests of
+// them can control their time without changing their code. It's a
+// build tag, rather than the variables alone, so that no production
+// binary's time can be changed by its environment.
+func init() {
+	clk, err := envClock(os.Getenv)
+	if err != nil {
+		panic(err)
+	}
+	if clk != nil {
+		defaultClock.Store(&clk)
+	}
+}
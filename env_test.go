@@ -0,0 +1,46 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvClock(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+	if clk, err := envClock(getenv); clk != nil || err != nil {
+		t.Errorf("envClock with nothing set = %v, %v, want nil, nil", clk, err)
+	}
+
+	env[EnvNow] = "2030-01-01T00:00:00Z"
+	clk, err := envClock(getenv)
+	if err != nil {
+		t.Fatalf("envClock: %v", err)
+	}
+	want := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if d := clk.Since(want); d < 0 || d > time.Minute {
+		t.Errorf("Clock from %s is %v past %v", EnvNow, d, want)
+	}
+
+	env[EnvRate] = "3600"
+	clk, err = envClock(getenv)
+	if err != nil {
+		t.Fatalf("envClock: %v", err)
+	}
+	start := clk.Now()
+	time.Sleep(10 * time.Millisecond)
+	if d := clk.Since(start); d < 30*time.Second {
+		t.Errorf("Clock with a rate of 3600 moved %v in 10ms", d)
+	}
+
+	for _, bad := range []map[string]string{
+		{EnvNow: "tomorrow"},
+		{EnvRate: "fast"},
+		{EnvRate: "-1"},
+	} {
+		env = bad
+		if _, err := envClock(getenv); err == nil {
+			t.Errorf("envClock(%v) returned no error", bad)
+		}
+	}
+}
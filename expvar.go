@@ -0,0 +1,35 @@
+package clock
+
+import (
+	"expvar"
+	"time"
+)
+
+// Expvar returns an expvar.Var describing fc, for publishing with
+// expvar.Publish so that long-running test harnesses can be inspected
+// through the /debug/vars endpoint. Its value is a JSON object with
+// fc's time, in RFC 3339 format, as "now", its Waiters as "waiters" and
+// the counts from its WaiterCounts as "sleepers", "timers", "tickers"
+// and "funcs".
+func Expvar(fc FakeClock) expvar.Var {
+	return expvar.Func(func() any {
+		c := fc.WaiterCounts()
+		return expvarState{
+			Now:      fc.Now().Format(time.RFC3339Nano),
+			Waiters:  fc.Waiters(),
+			Sleepers: c.Sleepers,
+			Timers:   c.Timers,
+			Tickers:  c.Tickers,
+			Funcs:    c.Funcs,
+		}
+	})
+}
+
+type expvarState struct {
+	Now      string `json:"now"`
+	Waiters  int    `json:"waiters"`
+	Sleepers int    `json:"sleepers"`
+	Timers   int    `json:"timers"`
+	Tickers  int    `json:"tickers"`
+	Funcs    int    `json:"funcs"`
+}
@@ -0,0 +1,20 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpvar(t *testing.T) {
+	fc := NewFake()
+	fc.Set(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	v := Expvar(fc)
+	fc.NewTimer(time.Second)
+	tk := fc.NewTicker(time.Second)
+	defer tk.Stop()
+
+	want := `{"now":"2020-01-01T00:00:00Z","waiters":2,"sleepers":0,"timers":1,"tickers":1,"funcs":0}`
+	if got := v.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
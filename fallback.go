@@ -0,0 +1,79 @@
+package clock
+
+import "time"
+
+// TimeSource is a source of the time that can fail, such as one kept in
+// sync with a time server that may be unreachable.
+type TimeSource interface {
+	// ReadTime returns the source's current time, or an error if it
+	// can't be trusted to have one.
+	ReadTime() (time.Time, error)
+}
+
+// TimeSourceFunc is a function that is a TimeSource.
+type TimeSourceFunc func() (time.Time, error)
+
+// ReadTime returns f().
+func (f TimeSourceFunc) ReadTime() (time.Time, error) {
+	return f()
+}
+
+// FallbackClock is a Clock that reads the time from a primary
+// TimeSource when it can, and from a secondary Clock when it can't, as
+// returned by Fallback.
+type FallbackClock interface {
+	Clock
+
+	// NowFrom returns the time, as Now does, along with whether the
+	// primary source served it. If it didn't, err is the error the
+	// primary source returned, and the time is the secondary Clock's.
+	NowFrom() (t time.Time, primary bool, err error)
+}
+
+// Fallback returns a FallbackClock whose Now returns primary's time,
+// unless primary fails to read it, in which case it returns secondary's
+// time, such as for a timestamping service that uses a time server's
+// time but falls back to the system clock. NowUnix, Since and Until are
+// computed from the same readings. NowMonotonic, Sleep and the clock's
+// Timers and Tickers are secondary's.
+func Fallback(primary TimeSource, secondary Clock) FallbackClock {
+	return &fallback{Clock: secondary, primary: primary}
+}
+
+type fallback struct {
+	Clock
+	primary TimeSource
+}
+
+func (f *fallback) NowFrom() (time.Time, bool, error) {
+	t, err := f.primary.ReadTime()
+	if err != nil {
+		return f.Clock.Now(), false, err
+	}
+	return t, true, nil
+}
+
+func (f *fallback) Now() time.Time {
+	t, _, _ := f.NowFrom()
+	return t
+}
+
+func (f *fallback) NowUnix() int64 {
+	return f.Now().Unix()
+}
+
+func (f *fallback) NowUnixMilli() int64 {
+	return f.Now().UnixMilli()
+}
+
+func (f *fallback) NowUnixNano() int64 {
+	return f.Now().UnixNano()
+}
+
+func (f *fallback) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *fallback) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
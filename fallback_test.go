@@ -0,0 +1,35 @@
+package clock
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFallback(t *testing.T) {
+	local := NewFake()
+	synced := local.Now().Add(time.Second)
+	errUnsynced := errors.New("unsynchronized")
+	var err error
+	primary := TimeSourceFunc(func() (time.Time, error) {
+		return synced, err
+	})
+	clk := Fallback(primary, local)
+
+	got, fromPrimary, gotErr := clk.NowFrom()
+	if !got.Equal(synced) || !fromPrimary || gotErr != nil {
+		t.Errorf("NowFrom() = %v, %t, %v, want %v, true, nil", got, fromPrimary, gotErr, synced)
+	}
+	if got := clk.Since(local.Now()); got != time.Second {
+		t.Errorf("Since(local time) = %v, want %v", got, time.Second)
+	}
+
+	err = errUnsynced
+	got, fromPrimary, gotErr = clk.NowFrom()
+	if !got.Equal(local.Now()) || fromPrimary || gotErr != errUnsynced {
+		t.Errorf("NowFrom() with a failing primary = %v, %t, %v, want %v, false, %v", got, fromPrimary, gotErr, local.Now(), errUnsynced)
+	}
+	if got := clk.Now(); !got.Equal(local.Now()) {
+		t.Errorf("Now() with a failing primary = %v, want %v", got, local.Now())
+	}
+}
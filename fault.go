@@ -0,0 +1,111 @@
+package clock
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Fault is a way for a reading of a Clock returned by Faulty to go
+// wrong. The zero Fault is a correct reading.
+type Fault struct {
+	// Stale makes the reading return the same time as the previous
+	// one, as if it came from a cache that failed to update.
+	Stale bool
+
+	// Jump moves the clock by the duration, backwards if it's
+	// negative, from this reading on, like a step of the system clock.
+	Jump time.Duration
+
+	// Stall makes the reading block for the duration, as measured by
+	// the base Clock's Sleep, before reading the time.
+	Stall time.Duration
+}
+
+// FaultPolicy picks the Fault, if any, for the nth reading, counting
+// from zero, of a Clock returned by Faulty. now is the base Clock's
+// time.
+type FaultPolicy func(n int, now time.Time) Fault
+
+// ScheduledFaults returns a FaultPolicy that injects faults[n] into the
+// nth reading.
+func ScheduledFaults(faults map[int]Fault) FaultPolicy {
+	return func(n int, now time.Time) Fault {
+		return faults[n]
+	}
+}
+
+// RandomFaults returns a FaultPolicy that injects f into each reading
+// with probability p, picked using seed, so that a Clock read in the
+// same order gets the same faults.
+func RandomFaults(seed int64, p float64, f Fault) FaultPolicy {
+	var mu sync.Mutex
+	r := rand.New(rand.NewSource(seed))
+	return func(n int, now time.Time) Fault {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Float64() < p {
+			return f
+		}
+		return Fault{}
+	}
+}
+
+// Faulty returns a Clock whose readings of the time by Now, NowUnix,
+// NowUnixMilli, NowUnixNano, Since and Until go wrong as policy says,
+// for checking that a service survives a misbehaving clock before it
+// meets one in production. NowMonotonic and the Clock's Timers,
+// Tickers and sleepers are base's.
+func Faulty(base Clock, policy FaultPolicy) Clock {
+	return &faulty{Clock: base, policy: policy}
+}
+
+type faulty struct {
+	Clock
+	policy FaultPolicy
+
+	mu     sync.Mutex
+	n      int
+	offset time.Duration
+	last   time.Time
+}
+
+func (f *faulty) Now() time.Time {
+	f.mu.Lock()
+	n := f.n
+	f.n++
+	f.mu.Unlock()
+	fault := f.policy(n, f.Clock.Now())
+	if fault.Stall > 0 {
+		f.Clock.Sleep(fault.Stall)
+	}
+	t := f.Clock.Now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.offset += fault.Jump
+	if fault.Stale && n > 0 {
+		return f.last
+	}
+	f.last = t.Add(f.offset)
+	return f.last
+}
+
+func (f *faulty) NowUnix() int64 {
+	return f.Now().Unix()
+}
+
+func (f *faulty) NowUnixMilli() int64 {
+	return f.Now().UnixMilli()
+}
+
+func (f *faulty) NowUnixNano() int64 {
+	return f.Now().UnixNano()
+}
+
+func (f *faulty) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *faulty) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFaulty(t *testing.T) {
+	fc := NewFake()
+	start := fc.Now()
+	clk := Faulty(fc, ScheduledFaults(map[int]Fault{
+		1: {Stale: true},
+		2: {Jump: -time.Hour},
+		4: {Stall: time.Minute},
+	}))
+	want := []time.Duration{
+		0,
+		0, // stale
+		2*time.Second - time.Hour,
+		3*time.Second - time.Hour,
+		4*time.Second + time.Minute - time.Hour,
+	}
+	for i, w := range want {
+		if i == 4 {
+			done := make(chan time.Time)
+			go func() { done <- clk.Now() }()
+			fc.BlockUntil(1)
+			fc.Add(time.Minute)
+			if got := (<-done).Sub(start); got != w {
+				t.Errorf("reading %d: got %v past the start, want %v", i, got, w)
+			}
+		} else if got := clk.Since(start); got != w {
+			t.Errorf("reading %d: got %v past the start, want %v", i, got, w)
+		}
+		fc.Add(time.Second)
+	}
+
+	a := Faulty(fc, RandomFaults(7, 0.5, Fault{Jump: time.Second}))
+	b := Faulty(fc, RandomFaults(7, 0.5, Fault{Jump: time.Second}))
+	for i := 0; i < 20; i++ {
+		if got, want := a.Now(), b.Now(); !got.Equal(want) {
+			t.Errorf("reading %d: Faulty clocks with the same RandomFaults seed differ: %v and %v", i, got, want)
+		}
+	}
+	if got := a.Since(fc.Now()); got == 0 || got == 21*time.Second {
+		t.Errorf("RandomFaults with p of 0.5 jumped %v in 21 readings", got)
+	}
+}
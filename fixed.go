@@ -0,0 +1,182 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Fixed returns a Clock whose time is always t, for code such as pure
+// functions that only needs a deterministic Now and would gain nothing
+// from a FakeClock. Its NowMonotonic is always zero. Since its time
+// never moves, its Timers, AfterFunc functions and sleepers only fire
+// if their deadlines are not after t, in which case they fire
+// immediately, and its Tickers never tick. Sleep and SleepUntil with
+// deadlines after t block forever.
+func Fixed(t time.Time) Clock {
+	return fixed{t}
+}
+
+type fixed struct {
+	t time.Time
+}
+
+func (f fixed) Now() time.Time {
+	return f.t
+}
+
+func (f fixed) NowUnix() int64 {
+	return f.t.Unix()
+}
+
+func (f fixed) NowUnixMilli() int64 {
+	return f.t.UnixMilli()
+}
+
+func (f fixed) NowUnixNano() int64 {
+	return f.t.UnixNano()
+}
+
+func (f fixed) NowMonotonic() time.Duration {
+	return 0
+}
+
+func (f fixed) Since(t time.Time) time.Duration {
+	return f.t.Sub(t)
+}
+
+func (f fixed) Until(t time.Time) time.Duration {
+	return t.Sub(f.t)
+}
+
+func (f fixed) Sleep(d time.Duration) {
+	if d > 0 {
+		select {}
+	}
+}
+
+func (f fixed) SleepUntil(t time.Time) {
+	if t.After(f.t) {
+		select {}
+	}
+}
+
+func (f fixed) SleepContext(ctx context.Context, d time.Duration) error {
+	return SleepContextWith(ctx, d, f.NewTimer)
+}
+
+func (f fixed) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f fixed) AfterAt(t time.Time) <-chan time.Time {
+	return f.NewTimerAt(t).C()
+}
+
+func (f fixed) NewTimer(d time.Duration) Timer {
+	return f.NewTimerAt(f.t.Add(d))
+}
+
+func (f fixed) NewTimerAt(t time.Time) Timer {
+	ft := &fixedTimer{clk: f, c: make(chan time.Time, 1)}
+	ft.start(t)
+	return ft
+}
+
+func (f fixed) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return fixedTicker{&fixedTimer{clk: f, c: make(chan time.Time, 1)}}
+}
+
+func (f fixed) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+	return f.NewTicker(d).C()
+}
+
+func (f fixed) AfterFunc(d time.Duration, fn func()) Timer {
+	ft := &fixedTimer{clk: f, fn: fn}
+	ft.start(f.t.Add(d))
+	return ft
+}
+
+// fixedTimer is a Timer of a fixed clock. It is active while its
+// deadline is after the clock's time, which is to say forever.
+type fixedTimer struct {
+	clk fixed
+	c   chan time.Time
+	fn  func()
+
+	mu     sync.Mutex
+	active bool
+}
+
+// start fires ft if t is not after the clock's time, and otherwise makes
+// it active. It must be called with ft's lock held once ft has been
+// returned to its user.
+func (ft *fixedTimer) start(t time.Time) {
+	if t.After(ft.clk.t) {
+		ft.active = true
+		return
+	}
+	if ft.fn != nil {
+		go ft.fn()
+		return
+	}
+	ft.c <- ft.clk.t
+}
+
+func (ft *fixedTimer) C() <-chan time.Time {
+	return ft.c
+}
+
+func (ft *fixedTimer) Stop() bool {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	return ft.halt()
+}
+
+func (ft *fixedTimer) Reset(d time.Duration) bool {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	active := ft.halt()
+	ft.start(ft.clk.t.Add(d))
+	return active
+}
+
+// halt makes ft inactive, discarding any time sent on its channel but
+// not yet received, and reports whether it was active or had such a
+// time, as a FakeClock's Timers do. It must be called with ft's lock
+// held.
+func (ft *fixedTimer) halt() bool {
+	active := ft.active
+	ft.active = false
+	select {
+	case <-ft.c:
+		return true
+	default:
+	}
+	return active
+}
+
+type fixedTicker struct {
+	ft *fixedTimer
+}
+
+func (t fixedTicker) C() <-chan time.Time {
+	return t.ft.c
+}
+
+func (t fixedTicker) Stop() {
+	t.ft.Stop()
+}
+
+func (t fixedTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	t.ft.Stop()
+}
@@ -0,0 +1,76 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFixed(t *testing.T) {
+	at := time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC)
+	clk := Fixed(at)
+	if got := clk.Now(); !got.Equal(at) {
+		t.Errorf("Now() = %v, want %v", got, at)
+	}
+	if got, want := clk.Since(at.Add(-time.Hour)), time.Hour; got != want {
+		t.Errorf("Since = %v, want %v", got, want)
+	}
+	if got := clk.NowUnix(); got != at.Unix() {
+		t.Errorf("NowUnix() = %d, want %d", got, at.Unix())
+	}
+
+	clk.Sleep(0)
+	clk.SleepUntil(at)
+	if got := <-clk.After(0); !got.Equal(at) {
+		t.Errorf("After(0) sent %v, want %v", got, at)
+	}
+	tm := clk.NewTimer(time.Second)
+	select {
+	case <-tm.C():
+		t.Errorf("Timer fired although the time never moves")
+	default:
+	}
+	if !tm.Stop() {
+		t.Errorf("Stop returned false for an active Timer")
+	}
+	if tm.Reset(-time.Second) {
+		t.Errorf("Reset returned true for a stopped Timer")
+	}
+	if got := <-tm.C(); !got.Equal(at) {
+		t.Errorf("Timer Reset into the past sent %v, want %v", got, at)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := clk.SleepContext(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("SleepContext with a done context returned %v", err)
+	}
+	called := make(chan struct{})
+	clk.AfterFunc(0, func() { close(called) })
+	<-called
+}
+
+func TestFixedTimerStopMatchesFake(t *testing.T) {
+	// Stopping or resetting a Timer that fired but wasn't received
+	// reports true, as it does for the FakeClock's own Timers.
+	fc := NewFake()
+	for name, clk := range map[string]Clock{"FakeClock": fc, "Fixed": Fixed(fc.Now())} {
+		tm := clk.NewTimer(0)
+		if !tm.Stop() {
+			t.Errorf("%s: Stop of a fired, unreceived Timer returned false", name)
+		}
+		if tm.Stop() {
+			t.Errorf("%s: second Stop returned true", name)
+		}
+		tm.Reset(0)
+		if !tm.Reset(time.Second) {
+			t.Errorf("%s: Reset of a fired, unreceived Timer returned false", name)
+		}
+		select {
+		case <-tm.C():
+			t.Errorf("%s: Timer kept its time across Reset", name)
+		default:
+		}
+		tm.Stop()
+	}
+}
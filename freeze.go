@@ -0,0 +1,59 @@
+package clock
+
+// FreezableClock is a Clock that follows another Clock, usually the
+// system's, except while it is frozen. It is for tests, such as
+// integration tests, that want time to mostly pass on its own but need
+// to stop the world while they inspect some state.
+type FreezableClock interface {
+	Clock
+
+	// Freeze stops the clock at its current time. While the clock is
+	// frozen, Now and NowMonotonic do not move and its Timers,
+	// Tickers, AfterFunc functions and sleepers do not fire, except
+	// for those whose deadlines had already passed. Freezing a frozen
+	// clock does nothing.
+	Freeze()
+
+	// Unfreeze starts the clock again from the time it was frozen at,
+	// so that it never jumps forward, and from then on it follows its
+	// base clock, behind it by however long it has been frozen for.
+	// What was waiting on the clock fires once the clock's time
+	// reaches its deadline. Unfreezing a clock that isn't frozen does
+	// nothing.
+	Unfreeze()
+
+	// Frozen reports whether the clock is frozen.
+	Frozen() bool
+}
+
+// NewFreezable returns a FreezableClock that, until it is frozen, has
+// the same time as base.
+func NewFreezable(base Clock) FreezableClock {
+	return &freezable{newDerived(base, 0, 1)}
+}
+
+type freezable struct {
+	*derived
+}
+
+func (f *freezable) Freeze() {
+	f.Lock()
+	defer f.Unlock()
+	if f.rate != 0 {
+		f.setRate(0)
+	}
+}
+
+func (f *freezable) Unfreeze() {
+	f.Lock()
+	defer f.Unlock()
+	if f.rate == 0 {
+		f.setRate(1)
+	}
+}
+
+func (f *freezable) Frozen() bool {
+	f.RLock()
+	defer f.RUnlock()
+	return f.rate == 0
+}
@@ -0,0 +1,85 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFreezable(t *testing.T) {
+	base := NewFake()
+	clk := NewFreezable(base)
+	start := clk.Now()
+	tm := clk.NewTimer(10 * time.Second)
+	tk := clk.NewTicker(3 * time.Second)
+	defer tk.Stop()
+
+	base.Add(5 * time.Second)
+	<-tk.C()
+	clk.Freeze()
+	if !clk.Frozen() {
+		t.Errorf("Frozen() = false after Freeze")
+	}
+	mono := clk.NowMonotonic()
+	base.Add(time.Hour)
+	if got, want := clk.Now(), start.Add(5*time.Second); !got.Equal(want) {
+		t.Errorf("frozen clock's Now() = %v, want %v", got, want)
+	}
+	if got := clk.NowMonotonic(); got != mono {
+		t.Errorf("frozen clock's NowMonotonic() moved from %v to %v", mono, got)
+	}
+	select {
+	case <-tm.C():
+		t.Errorf("Timer fired while the clock was frozen")
+	case <-tk.C():
+		t.Errorf("Ticker ticked while the clock was frozen")
+	default:
+	}
+
+	clk.Unfreeze()
+	if clk.Frozen() {
+		t.Errorf("Frozen() = true after Unfreeze")
+	}
+	if got, want := clk.Now(), start.Add(5*time.Second); !got.Equal(want) {
+		t.Errorf("Now() = %v right after Unfreeze, want %v", got, want)
+	}
+	base.Add(time.Second)
+	if got, want := <-tk.C(), start.Add(6*time.Second); !got.Equal(want) {
+		t.Errorf("Ticker after Unfreeze sent %v, want %v", got, want)
+	}
+	base.Add(3 * time.Second)
+	select {
+	case <-tm.C():
+		t.Errorf("Timer fired before its deadline")
+	default:
+	}
+	base.Add(time.Second)
+	if got, want := <-tm.C(), start.Add(10*time.Second); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got, want := clk.NowMonotonic(), mono+5*time.Second; got != want {
+		t.Errorf("NowMonotonic() = %v, want %v", got, want)
+	}
+	if got, want := clk.Since(start), 10*time.Second; got != want {
+		t.Errorf("Since(start) = %v, want %v", got, want)
+	}
+	if tm.Stop() {
+		t.Errorf("Stop returned true for a Timer that fired")
+	}
+
+	fired := make(chan time.Time, 1)
+	clk.AfterFunc(time.Second, func() { fired <- clk.Now() })
+	clk.Freeze()
+	clk.Freeze()
+	base.Add(time.Minute)
+	clk.Unfreeze()
+	clk.Unfreeze()
+	base.Add(time.Second)
+	select {
+	case got := <-fired:
+		if want := start.Add(11 * time.Second); !got.Equal(want) {
+			t.Errorf("AfterFunc called at %v, want %v", got, want)
+		}
+	default:
+		t.Errorf("AfterFunc not called after Unfreeze")
+	}
+}
@@ -0,0 +1,134 @@
+package clock
+
+import "time"
+
+// GPSOffset is how far TAI is ahead of GPS time. GPS time has no leap
+// seconds, and was set to UTC at its epoch, when TAI−UTC was 19
+// seconds.
+const GPSOffset = 19 * time.Second
+
+const (
+	// GPSWeek is the length of a GPS week.
+	GPSWeek = 7 * 24 * time.Hour
+
+	// GPSWeekModulus is the number of weeks after which the 10-bit
+	// week number in the legacy GPS navigation message rolls over to
+	// zero, as it did in 1999 and 2019. GPSWeekModulusCNAV is the same
+	// for the 13-bit week number of the modernized message.
+	GPSWeekModulus     = 1024
+	GPSWeekModulusCNAV = 8192
+)
+
+// gpsEpoch is the reading of the start of GPS week zero, January 6,
+// 1980 00:00:00 UTC, in both GPS time and UTC.
+var gpsEpoch = time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC)
+
+// GPS is a time in GPS time, the time scale of the Global Positioning
+// System, which is always GPSOffset behind TAI. GNSS receivers report
+// it as a week number and a time of week, which GPSFromWeek converts.
+// LeapTables convert GPS times to and from UTC time.Times, as they do
+// TAIs.
+type GPS struct {
+	// t's reading, in UTC, is the GPS time's, and it has no monotonic
+	// clock reading.
+	t time.Time
+}
+
+// GPSFromTAI returns the GPS time of a.
+func GPSFromTAI(a TAI) GPS {
+	return GPS{a.t.Add(-GPSOffset)}
+}
+
+// TAI returns g in TAI.
+func (g GPS) TAI() TAI {
+	return TAI{g.t.Add(GPSOffset)}
+}
+
+// GPSFromWeek returns the GPS time that is tow, the time of week, into
+// week, the number of whole weeks since the start of GPS time. week
+// must already have been resolved from any rolled over week number, as
+// by ResolveGPSWeek.
+func GPSFromWeek(week int, tow time.Duration) GPS {
+	return GPS{gpsEpoch.Add(time.Duration(week)*GPSWeek + tow)}
+}
+
+// Week returns g's week number, counting from zero at the start of GPS
+// time without rolling over, and its time of week.
+func (g GPS) Week() (week int, tow time.Duration) {
+	d := g.t.Sub(gpsEpoch)
+	week = int(d / GPSWeek)
+	tow = d % GPSWeek
+	if tow < 0 {
+		week--
+		tow += GPSWeek
+	}
+	return week, tow
+}
+
+// ResolveGPSWeek returns the GPS time tow into the week whose number,
+// modulo modulus, is week, that is nearest to near, such as the time
+// from another source or when the program was built. Receivers that
+// only report the 10-bit week number, whose modulus is GPSWeekModulus,
+// need their week resolving to a time within about ten years of the
+// right one.
+func ResolveGPSWeek(week, modulus int, tow time.Duration, near GPS) GPS {
+	nearWeek, _ := near.Week()
+	base := nearWeek - nearWeek%modulus
+	if nearWeek%modulus < 0 {
+		base -= modulus
+	}
+	best := GPSFromWeek(base+week%modulus, tow)
+	for _, w := range []int{base + week%modulus - modulus, base + week%modulus + modulus} {
+		g := GPSFromWeek(w, tow)
+		if g.Sub(near).Abs() < best.Sub(near).Abs() {
+			best = g
+		}
+	}
+	return best
+}
+
+// Add returns g plus d.
+func (g GPS) Add(d time.Duration) GPS {
+	return GPS{g.t.Add(d)}
+}
+
+// Sub returns g-h, the time elapsed from h to g.
+func (g GPS) Sub(h GPS) time.Duration {
+	return g.t.Sub(h.t)
+}
+
+// Before reports whether g is before h.
+func (g GPS) Before(h GPS) bool {
+	return g.t.Before(h.t)
+}
+
+// After reports whether g is after h.
+func (g GPS) After(h GPS) bool {
+	return g.t.After(h.t)
+}
+
+// Equal reports whether g and h are the same time.
+func (g GPS) Equal(h GPS) bool {
+	return g.t.Equal(h.t)
+}
+
+// Compare returns -1 if g is before h, +1 if g is after h, and 0 if
+// they are the same time.
+func (g GPS) Compare(h GPS) int {
+	return g.t.Compare(h.t)
+}
+
+// String returns g formatted as "2006-01-02 15:04:05.999999999 GPS".
+func (g GPS) String() string {
+	return g.t.Format("2006-01-02 15:04:05.999999999") + " GPS"
+}
+
+// ToGPS returns the GPS time of the UTC time t.
+func (lt *LeapTable) ToGPS(t time.Time) GPS {
+	return GPSFromTAI(lt.ToTAI(t))
+}
+
+// FromGPS returns the UTC time of the GPS time g, as FromTAI does.
+func (lt *LeapTable) FromGPS(g GPS) (t time.Time, inLeap bool) {
+	return lt.FromTAI(g.TAI())
+}
@@ -0,0 +1,67 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGPS(t *testing.T) {
+	epoch := time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC)
+	if got, want := LeapSeconds.ToGPS(epoch), GPSFromWeek(0, 0); !got.Equal(want) {
+		t.Errorf("ToGPS(GPS epoch) = %v, want %v", got, want)
+	}
+
+	utc := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	g := LeapSeconds.ToGPS(utc)
+	if got, want := g.String(), "2024-03-01 12:00:18 GPS"; got != want {
+		t.Errorf("ToGPS(%v) = %v, want %v", utc, got, want)
+	}
+	week, tow := g.Week()
+	if week != 2303 || tow != 5*24*time.Hour+12*time.Hour+18*time.Second {
+		t.Errorf("Week() = %d, %v, want 2303, 5d12h0m18s", week, tow)
+	}
+	if got := GPSFromWeek(week, tow); !got.Equal(g) {
+		t.Errorf("GPSFromWeek(Week()) = %v, want %v", got, g)
+	}
+	if got, inLeap := LeapSeconds.FromGPS(g); !got.Equal(utc) || inLeap {
+		t.Errorf("FromGPS(%v) = %v, %t, want %v", g, got, inLeap, utc)
+	}
+	if got := g.TAI().Sub(LeapSeconds.ToTAI(utc)); got != 0 {
+		t.Errorf("TAI() is %v off", got)
+	}
+	if got := GPSFromTAI(g.TAI()); !got.Equal(g) {
+		t.Errorf("GPSFromTAI(TAI()) = %v, want %v", got, g)
+	}
+
+	h := g.Add(time.Second)
+	if h.Sub(g) != time.Second || !g.Before(h) || !h.After(g) || g.Compare(h) != -1 || g.Equal(h) {
+		t.Errorf("%v and %v compare wrongly", g, h)
+	}
+	week, tow = GPSFromWeek(0, 0).Add(-time.Second).Week()
+	if week != -1 || tow != GPSWeek-time.Second {
+		t.Errorf("Week() just before the GPS epoch = %d, %v, want -1, %v", week, tow, GPSWeek-time.Second)
+	}
+}
+
+func TestResolveGPSWeek(t *testing.T) {
+	tow := 3 * 24 * time.Hour
+	for _, tc := range []struct {
+		week, modulus int
+		near          GPS
+		want          int
+	}{
+		// 2303 is week 255 of the third 1024-week era.
+		{255, GPSWeekModulus, GPSFromWeek(2300, 0), 2303},
+		{255, GPSWeekModulus, GPSFromWeek(2800, 0), 2303},
+		{255, GPSWeekModulus, GPSFromWeek(2900, 0), 3327},
+		// Just after a rollover, with near just before it.
+		{2, GPSWeekModulus, GPSFromWeek(2047, 0), 2050},
+		{1023, GPSWeekModulus, GPSFromWeek(2049, 0), 2047},
+		{2303, GPSWeekModulusCNAV, GPSFromWeek(100, 0), 2303},
+	} {
+		got := ResolveGPSWeek(tc.week, tc.modulus, tow, tc.near)
+		if w, gotTOW := got.Week(); w != tc.want || gotTOW != tow {
+			t.Errorf("ResolveGPSWeek(%d, %d, %v, %v) is week %d, %v, want %d, %v", tc.week, tc.modulus, tow, tc.near, w, gotTOW, tc.want, tow)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package hlc implements Hybrid Logical Clocks, as described in
+// Kulkarni et al., "Logical Physical Clocks and Consistent Snapshots in
+// Globally Distributed Databases", on top of a clock.Clock.
+//
+// A hybrid logical clock's timestamps stay close to its clock.Clock's
+// wall clock time while also respecting causality: a timestamp taken
+// after receiving a message is always later than the one the message
+// was sent with, even when the sender's wall clock is ahead of the
+// receiver's.
+package hlc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// Timestamp is a hybrid logical clock timestamp. Timestamps are ordered
+// by Wall and then by Logical. The zero Timestamp is earlier than any
+// other a Clock returns.
+type Timestamp struct {
+	// Wall is the highest wall clock time, in nanoseconds since the Unix
+	// epoch, that the Clock had seen when the Timestamp was taken.
+	Wall int64
+
+	// Logical counts the Timestamps taken since Wall last changed.
+	Logical uint32
+}
+
+// Time returns t's wall clock time.
+func (t Timestamp) Time() time.Time {
+	return time.Unix(0, t.Wall)
+}
+
+// IsZero reports whether t is the zero Timestamp.
+func (t Timestamp) IsZero() bool {
+	return t == Timestamp{}
+}
+
+// Compare returns -1 if t is before u, +1 if t is after u, and 0 if
+// they are equal.
+func (t Timestamp) Compare(u Timestamp) int {
+	switch {
+	case t.Wall < u.Wall:
+		return -1
+	case t.Wall > u.Wall:
+		return 1
+	case t.Logical < u.Logical:
+		return -1
+	case t.Logical > u.Logical:
+		return 1
+	}
+	return 0
+}
+
+// Before reports whether t is before u.
+func (t Timestamp) Before(u Timestamp) bool {
+	return t.Compare(u) < 0
+}
+
+// After reports whether t is after u.
+func (t Timestamp) After(u Timestamp) bool {
+	return t.Compare(u) > 0
+}
+
+// String returns t as its Wall and Logical in decimal, separated by a
+// period, such as "1257894000000000000.3". Parse parses it.
+func (t Timestamp) String() string {
+	return strconv.FormatInt(t.Wall, 10) + "." + strconv.FormatUint(uint64(t.Logical), 10)
+}
+
+// Parse parses a Timestamp in the form String returns.
+func Parse(s string) (Timestamp, error) {
+	wall, logical, ok := strings.Cut(s, ".")
+	if !ok {
+		return Timestamp{}, fmt.Errorf("hlc: invalid timestamp %q: missing logical part", s)
+	}
+	w, err := strconv.ParseInt(wall, 10, 64)
+	if err != nil {
+		return Timestamp{}, fmt.Errorf("hlc: invalid timestamp %q: %w", s, err)
+	}
+	l, err := strconv.ParseUint(logical, 10, 32)
+	if err != nil {
+		return Timestamp{}, fmt.Errorf("hlc: invalid timestamp %q: %w", s, err)
+	}
+	return Timestamp{Wall: w, Logical: uint32(l)}, nil
+}
+
+// EncodedLen is the length of a Timestamp's binary encoding.
+const EncodedLen = 12
+
+var errEncodedLen = errors.New("hlc: invalid binary timestamp: wrong length")
+
+// AppendBinary appends t's binary encoding to b: Wall and then Logical,
+// both big-endian. Timestamps with non-negative Walls encode in the
+// same order as they compare, so their encodings can be used as sorted
+// keys.
+func (t Timestamp) AppendBinary(b []byte) ([]byte, error) {
+	b = binary.BigEndian.AppendUint64(b, uint64(t.Wall))
+	return binary.BigEndian.AppendUint32(b, t.Logical), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the encoding
+// AppendBinary uses.
+func (t Timestamp) MarshalBinary() ([]byte, error) {
+	return t.AppendBinary(make([]byte, 0, EncodedLen))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (t *Timestamp) UnmarshalBinary(b []byte) error {
+	if len(b) != EncodedLen {
+		return errEncodedLen
+	}
+	t.Wall = int64(binary.BigEndian.Uint64(b))
+	t.Logical = binary.BigEndian.Uint32(b[8:])
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler with the form String
+// returns.
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *Timestamp) UnmarshalText(b []byte) error {
+	ts, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*t = ts
+	return nil
+}
+
+// Clock is a hybrid logical clock. It is safe for concurrent use.
+type Clock struct {
+	clk clock.Clock
+
+	mu   sync.Mutex
+	last Timestamp
+}
+
+// New returns a Clock whose wall clock time is clk's.
+func New(clk clock.Clock) *Clock {
+	return &Clock{clk: clk}
+}
+
+// Now returns a Timestamp for a local event, such as sending a message.
+// It is later than every Timestamp the Clock has returned before or
+// been given to Update.
+func (c *Clock) Now() Timestamp {
+	pt := c.clk.Now().UnixNano()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if pt > c.last.Wall {
+		c.last = Timestamp{Wall: pt}
+	} else {
+		c.tick(c.last.Logical)
+	}
+	return c.last
+}
+
+// Update records that the Clock has seen remote, such as a Timestamp
+// sent with a message that was received, and returns a Timestamp for
+// the receipt that is later than remote and than every Timestamp the
+// Clock has returned before.
+//
+// Update trusts remote's Wall, however far ahead of the Clock's wall
+// clock time it is. Callers receiving messages from clocks they can't
+// trust to be in sync should check how far ahead remote.Time() is
+// first.
+func (c *Clock) Update(remote Timestamp) Timestamp {
+	pt := c.clk.Now().UnixNano()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case pt > c.last.Wall && pt > remote.Wall:
+		c.last = Timestamp{Wall: pt}
+	case remote.Wall > c.last.Wall:
+		c.last.Wall = remote.Wall
+		c.tick(remote.Logical)
+	case remote.Wall == c.last.Wall:
+		c.tick(max(c.last.Logical, remote.Logical))
+	default:
+		c.tick(c.last.Logical)
+	}
+	return c.last
+}
+
+// Last returns the latest Timestamp the Clock has returned, or the zero
+// Timestamp if it hasn't returned any.
+func (c *Clock) Last() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// tick sets c.last's Logical to one after logical. In the unlikely case
+// that logical can't go any higher, it moves c.last's Wall on a
+// nanosecond instead, so that timestamps keep increasing.
+func (c *Clock) tick(logical uint32) {
+	if logical == math.MaxUint32 {
+		c.last = Timestamp{Wall: c.last.Wall + 1}
+		return
+	}
+	c.last.Logical = logical + 1
+}
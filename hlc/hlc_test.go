@@ -0,0 +1,158 @@
+package hlc
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+func TestNow(t *testing.T) {
+	fc := newFake()
+	c := New(fc)
+	wall := fc.Now().UnixNano()
+	if got, want := c.Now(), (Timestamp{wall, 0}); got != want {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+	if got, want := c.Now(), (Timestamp{wall, 1}); got != want {
+		t.Errorf("second Now() = %v, want %v", got, want)
+	}
+	fc.Add(time.Second)
+	if got, want := c.Now(), (Timestamp{wall + int64(time.Second), 0}); got != want {
+		t.Errorf("Now() after Add = %v, want %v", got, want)
+	}
+	fc.Add(-time.Minute)
+	if got, want := c.Now(), (Timestamp{wall + int64(time.Second), 1}); got != want {
+		t.Errorf("Now() after the clock went back = %v, want %v", got, want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	fc := newFake()
+	c := New(fc)
+	wall := fc.Now().UnixNano()
+	last := c.Now()
+
+	ahead := Timestamp{wall + int64(time.Second), 5}
+	if got, want := c.Update(ahead), (Timestamp{ahead.Wall, 6}); got != want {
+		t.Errorf("Update(%v) = %v, want %v", ahead, got, want)
+	}
+	same := Timestamp{ahead.Wall, 9}
+	if got, want := c.Update(same), (Timestamp{ahead.Wall, 10}); got != want {
+		t.Errorf("Update(%v) = %v, want %v", same, got, want)
+	}
+	if got, want := c.Update(last), (Timestamp{ahead.Wall, 11}); got != want {
+		t.Errorf("Update(%v) with an old timestamp = %v, want %v", last, got, want)
+	}
+	fc.Add(time.Minute)
+	if got, want := c.Update(ahead), (Timestamp{wall + int64(time.Minute), 0}); got != want {
+		t.Errorf("Update(%v) once the clock passed it = %v, want %v", ahead, got, want)
+	}
+	if got := c.Last(); got.Wall != wall+int64(time.Minute) {
+		t.Errorf("Last() = %v, want the last Update's result", got)
+	}
+}
+
+func TestLogicalOverflow(t *testing.T) {
+	fc := newFake()
+	c := New(fc)
+	wall := fc.Now().UnixNano()
+	c.Update(Timestamp{wall, math.MaxUint32 - 1})
+	if got, want := c.Now(), (Timestamp{wall + 1, 0}); got != want {
+		t.Errorf("Now() with Logical at its maximum = %v, want %v", got, want)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ts := []Timestamp{{}, {1, 0}, {1, 1}, {2, 0}}
+	for i, a := range ts {
+		for j, b := range ts {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := a.Compare(b); got != want {
+				t.Errorf("%v.Compare(%v) = %d, want %d", a, b, got, want)
+			}
+			if got := a.Before(b); got != (want < 0) {
+				t.Errorf("%v.Before(%v) = %t", a, b, got)
+			}
+			if got := a.After(b); got != (want > 0) {
+				t.Errorf("%v.After(%v) = %t", a, b, got)
+			}
+		}
+	}
+	if !ts[0].IsZero() || ts[1].IsZero() {
+		t.Errorf("IsZero is wrong")
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	tss := []Timestamp{{}, {1257894000000000000, 3}, {1257894000000000000, 4}, {1257894000000000001, 0}}
+	var prev []byte
+	for _, ts := range tss {
+		b, err := ts.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != EncodedLen {
+			t.Errorf("MarshalBinary(%v) is %d bytes, want %d", ts, len(b), EncodedLen)
+		}
+		if bytes.Compare(prev, b) >= 0 {
+			t.Errorf("encoding of %v doesn't sort after the one before it", ts)
+		}
+		prev = b
+		var got Timestamp
+		if err := got.UnmarshalBinary(b); err != nil || got != ts {
+			t.Errorf("UnmarshalBinary(MarshalBinary(%v)) = %v, %v", ts, got, err)
+		}
+
+		text, _ := ts.MarshalText()
+		got = Timestamp{}
+		if err := got.UnmarshalText(text); err != nil || got != ts {
+			t.Errorf("UnmarshalText(%q) = %v, %v, want %v", text, got, err, ts)
+		}
+	}
+	if got, want := (Timestamp{1257894000000000000, 3}).String(), "1257894000000000000.3"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got := (Timestamp{1, 2}).Time(); !got.Equal(time.Unix(0, 1)) {
+		t.Errorf("Time() = %v", got)
+	}
+
+	var ts Timestamp
+	if err := ts.UnmarshalBinary(make([]byte, EncodedLen-1)); err == nil {
+		t.Errorf("UnmarshalBinary of a short slice didn't fail")
+	}
+	for _, s := range []string{"", "1", "x.1", "1.x", "1.4294967296"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) didn't fail", s)
+		}
+	}
+}
+
+func TestCausality(t *testing.T) {
+	// b's clock is behind a's, but b's receipt of a's message is still
+	// after a sent it.
+	fa := newFake()
+	fb := newFake()
+	fb.Set(fa.Now().Add(-time.Hour))
+	a, b := New(fa), New(fb)
+	sent := a.Now()
+	if got := b.Update(sent); !got.After(sent) {
+		t.Errorf("receipt %v isn't after send %v", got, sent)
+	}
+	if got := b.Now(); !got.After(sent) {
+		t.Errorf("b's next event %v isn't after a's send %v", got, sent)
+	}
+}
+
+// newFake returns a FakeClock that isn't at the Unix epoch, whose wall
+// clock time would equal the zero Timestamp's.
+func newFake() clock.FakeClock {
+	return clock.NewFake(clock.WithStart(time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,32 @@
+package clock
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// requestStartKey is the key for the time a request reached Middleware.
+type requestStartKey struct{}
+
+// Middleware returns HTTP middleware that carries clk in the Context of
+// every request it handles, for handlers to get with FromContext, along
+// with clk's time when the request reached the middleware, for them to
+// get with RequestStart.
+func Middleware(clk Clock) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := NewContext(r.Context(), clk)
+			ctx = context.WithValue(ctx, requestStartKey{}, clk.Now())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestStart returns the time at which the request whose Context is
+// ctx reached Middleware, as read from the Clock given to it. It
+// returns false if the request did not go through Middleware.
+func RequestStart(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(requestStartKey{}).(time.Time)
+	return t, ok
+}
@@ -0,0 +1,34 @@
+package clock
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	fc := NewFake()
+	start := fc.Now()
+	var elapsed time.Duration
+	h := Middleware(fc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clk := FromContext(r.Context())
+		if clk != fc {
+			t.Errorf("FromContext returned %v, want the Clock given to Middleware", clk)
+		}
+		fc.Add(time.Second)
+		at, ok := RequestStart(r.Context())
+		if !ok || !at.Equal(start) {
+			t.Errorf("RequestStart() = %v, %t, want %v, true", at, ok, start)
+		}
+		elapsed = clk.Since(at)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if elapsed != time.Second {
+		t.Errorf("handler measured %v, want %v", elapsed, time.Second)
+	}
+
+	if _, ok := RequestStart(httptest.NewRequest("GET", "/", nil).Context()); ok {
+		t.Errorf("RequestStart returned true for a request that did not go through Middleware")
+	}
+}
@@ -0,0 +1,8 @@
+// Package ids generates unique IDs that sort by the time they were
+// made, ULIDs and Snowflake IDs, reading the time from a clock.Clock so
+// that tests using a clock.FakeClock get the same IDs every run.
+//
+// Both generators keep their IDs increasing even when the clock goes
+// backwards, such as when NTP steps the system clock, by carrying on
+// from the latest time they have used until the clock catches up.
+package ids
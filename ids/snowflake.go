@@ -0,0 +1,115 @@
+package ids
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// Snowflake is a Snowflake ID, as Twitter introduced: a positive int64
+// made of a 41-bit count of milliseconds since its generator's epoch, a
+// 10-bit node number and a 12-bit sequence number, so that Snowflakes
+// sort by time, and those made by different nodes never collide.
+type Snowflake int64
+
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+
+	// MaxNode is the greatest node number a Snowflake can have.
+	MaxNode = 1<<nodeBits - 1
+
+	maxSequence      = 1<<sequenceBits - 1
+	maxSnowflakeTime = 1 << (63 - nodeBits - sequenceBits)
+)
+
+// TwitterEpoch is the epoch of Twitter's Snowflake IDs, from which
+// they count 41 bits of milliseconds, about 69 years.
+var TwitterEpoch = time.UnixMilli(1288834974657)
+
+var errSnowflakeTime = errors.New("ids: time out of a Snowflake's range")
+
+// Millis returns the number of milliseconds after its generator's
+// epoch that s was made at.
+func (s Snowflake) Millis() int64 {
+	return int64(s) >> (nodeBits + sequenceBits)
+}
+
+// Node returns the node number of the generator that made s.
+func (s Snowflake) Node() int {
+	return int(s>>sequenceBits) & MaxNode
+}
+
+// Sequence returns s's sequence number, which tells apart the
+// Snowflakes made by a node within a millisecond.
+func (s Snowflake) Sequence() int {
+	return int(s) & maxSequence
+}
+
+// String returns s in decimal.
+func (s Snowflake) String() string {
+	return strconv.FormatInt(int64(s), 10)
+}
+
+// SnowflakeGenerator makes Snowflakes from a clock.Clock's time. Each
+// Snowflake it makes is greater than the one before. When it has made
+// all 4096 for a millisecond, or the clock has gone backwards, it
+// carries on from the next millisecond after the last Snowflake's
+// rather than waiting for the clock. It is safe for concurrent use.
+type SnowflakeGenerator struct {
+	clk   clock.Clock
+	node  int
+	epoch time.Time
+
+	mu   sync.Mutex
+	last Snowflake
+	used bool
+}
+
+// NewSnowflakeGenerator returns a SnowflakeGenerator for node number
+// node that reads the time from clk and counts from epoch, such as
+// TwitterEpoch. It panics if node is negative or greater than MaxNode.
+func NewSnowflakeGenerator(clk clock.Clock, node int, epoch time.Time) *SnowflakeGenerator {
+	if node < 0 || node > MaxNode {
+		panic(fmt.Sprintf("ids: Snowflake node %d out of range", node))
+	}
+	return &SnowflakeGenerator{clk: clk, node: node, epoch: epoch}
+}
+
+// New returns a new Snowflake. It returns an error if the clock's time
+// is before the generator's epoch or too long after it for a Snowflake
+// to represent.
+func (g *SnowflakeGenerator) New() (Snowflake, error) {
+	now := g.clk.Now().Round(0)
+	if now.Before(g.epoch) {
+		return 0, errSnowflakeTime
+	}
+	ms := now.Sub(g.epoch).Milliseconds()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	seq := 0
+	if g.used && ms <= g.last.Millis() {
+		ms = g.last.Millis()
+		seq = g.last.Sequence() + 1
+		if seq > maxSequence {
+			ms++
+			seq = 0
+		}
+	}
+	if ms >= maxSnowflakeTime {
+		return 0, errSnowflakeTime
+	}
+	s := Snowflake(ms<<(nodeBits+sequenceBits) | int64(g.node)<<sequenceBits | int64(seq))
+	g.last, g.used = s, true
+	return s, nil
+}
+
+// Time returns the time s was made at, to the millisecond, if it was
+// made by a generator with g's epoch.
+func (g *SnowflakeGenerator) Time(s Snowflake) time.Time {
+	return g.epoch.Add(time.Duration(s.Millis()) * time.Millisecond)
+}
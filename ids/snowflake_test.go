@@ -0,0 +1,68 @@
+package ids
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+func TestSnowflakeGenerator(t *testing.T) {
+	fc := clock.NewFakeAt(start)
+	g := NewSnowflakeGenerator(fc, 7, TwitterEpoch)
+	first, err := g.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Node() != 7 || first.Sequence() != 0 || !g.Time(first).Equal(start) {
+		t.Errorf("first Snowflake %v has node %d, sequence %d and time %v, want 7, 0 and %v", first, first.Node(), first.Sequence(), g.Time(first), start)
+	}
+
+	last := first
+	for i := 1; i <= maxSequence; i++ {
+		s, err := g.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s <= last || s.Sequence() != i {
+			t.Fatalf("Snowflake %d is %v, sequence %d, after %v", i, s, s.Sequence(), last)
+		}
+		last = s
+	}
+	// The sequence is used up, so the generator moves on a millisecond.
+	s, _ := g.New()
+	if s <= last || s.Sequence() != 0 || !g.Time(s).Equal(start.Add(time.Millisecond)) {
+		t.Errorf("Snowflake after the sequence ran out = %v at %v, want sequence 0 a millisecond later", s, g.Time(s))
+	}
+	last = s
+
+	fc.Add(-time.Minute)
+	if s, _ := g.New(); s <= last || !g.Time(s).Equal(g.Time(last)) {
+		t.Errorf("Snowflake after the clock went back = %v at %v, want after %v at the same time", s, g.Time(s), last)
+	}
+
+	fc.Add(2 * time.Minute)
+	if s, _ := g.New(); !g.Time(s).Equal(fc.Now()) || s.Sequence() != 0 {
+		t.Errorf("Snowflake after the clock caught up = %v at %v, want sequence 0 at %v", s, g.Time(s), fc.Now())
+	}
+	if got := first.String(); got == "" || got[0] == '-' {
+		t.Errorf("String = %q, want a positive number", got)
+	}
+}
+
+func TestSnowflakeGeneratorErrors(t *testing.T) {
+	g := NewSnowflakeGenerator(clock.NewFakeAt(TwitterEpoch.Add(-time.Microsecond)), 0, TwitterEpoch)
+	if _, err := g.New(); err == nil {
+		t.Error("New before the epoch didn't fail")
+	}
+	g = NewSnowflakeGenerator(clock.NewFakeAt(TwitterEpoch.Add(100*365*24*time.Hour)), 0, TwitterEpoch)
+	if _, err := g.New(); err == nil {
+		t.Error("New a century after the epoch didn't fail")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("NewSnowflakeGenerator with node 1024 didn't panic")
+		}
+	}()
+	NewSnowflakeGenerator(clock.NewFake(), MaxNode+1, TwitterEpoch)
+}
@@ -0,0 +1,195 @@
+package ids
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// ULID is a Universally Unique Lexicographically Sortable Identifier,
+// as specified at https://github.com/ulid/spec: a 48-bit count of
+// milliseconds since the Unix epoch followed by 80 bits of entropy, all
+// big-endian, so that ULIDs, and their strings, sort by time.
+type ULID [16]byte
+
+// ULIDLen is the length of a ULID's string.
+const ULIDLen = 26
+
+// maxULIDTime is the first millisecond a ULID can't represent.
+const maxULIDTime = 1 << 48
+
+// crockford is Crockford's base32 alphabet, which ULIDs are written in.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var (
+	// ErrULIDOverflow is returned by ULIDGenerator.New when the last
+	// ULID it made was the greatest there is.
+	ErrULIDOverflow = errors.New("ids: ULID overflow")
+
+	errULIDTime = errors.New("ids: time out of a ULID's range")
+)
+
+// Time returns the time u was made at, to the millisecond.
+func (u ULID) Time() time.Time {
+	return time.UnixMilli(u.ms())
+}
+
+func (u ULID) ms() int64 {
+	var ms int64
+	for _, b := range u[:6] {
+		ms = ms<<8 | int64(b)
+	}
+	return ms
+}
+
+// Compare returns -1 if u sorts before v, +1 if it sorts after, and 0
+// if they are the same.
+func (u ULID) Compare(v ULID) int {
+	return bytes.Compare(u[:], v[:])
+}
+
+// IsZero reports whether u is the zero ULID, which New never returns.
+func (u ULID) IsZero() bool {
+	return u == ULID{}
+}
+
+// String returns u in its 26-character form.
+func (u ULID) String() string {
+	return string(u.AppendText(make([]byte, 0, ULIDLen)))
+}
+
+// AppendText appends u's string to b.
+func (u ULID) AppendText(b []byte) []byte {
+	// The 128 bits are written as 26 groups of 5, the first with two
+	// leading zero bits.
+	var s [ULIDLen]byte
+	var acc uint32
+	bits := 0
+	i := ULIDLen - 1
+	for j := len(u) - 1; j >= 0; j-- {
+		acc |= uint32(u[j]) << bits
+		bits += 8
+		for bits >= 5 {
+			s[i] = crockford[acc&31]
+			acc >>= 5
+			bits -= 5
+			i--
+		}
+	}
+	s[0] = crockford[acc]
+	return append(b, s[:]...)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u ULID) MarshalText() ([]byte, error) {
+	return u.AppendText(nil), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, with ParseULID.
+func (u *ULID) UnmarshalText(b []byte) error {
+	v, err := ParseULID(string(b))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// ParseULID parses a ULID from its string, in either case.
+func ParseULID(s string) (ULID, error) {
+	if len(s) != ULIDLen {
+		return ULID{}, fmt.Errorf("ids: invalid ULID %q: wrong length", s)
+	}
+	var u ULID
+	var acc uint32
+	bits := 0
+	j := len(u) - 1
+	for i := ULIDLen - 1; i >= 0; i-- {
+		v := strings.IndexByte(crockford, upper(s[i]))
+		if v < 0 || i == 0 && v > 7 {
+			return ULID{}, fmt.Errorf("ids: invalid ULID %q", s)
+		}
+		acc |= uint32(v) << bits
+		bits += 5
+		if bits >= 8 {
+			u[j] = byte(acc)
+			acc >>= 8
+			bits -= 8
+			j--
+		}
+	}
+	return u, nil
+}
+
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// ULIDGenerator makes ULIDs from a clock.Clock's time. Each ULID it
+// makes sorts after the one before: within a millisecond, or when the
+// clock has gone backwards, it adds one to the last ULID rather than
+// drawing new entropy, as the ULID spec's monotonic mode does, carrying
+// into the ULID's time in the unlikely event the entropy overflows. It
+// is safe for concurrent use.
+type ULIDGenerator struct {
+	clk     clock.Clock
+	entropy io.Reader
+
+	mu   sync.Mutex
+	last ULID
+}
+
+// NewULIDGenerator returns a ULIDGenerator that reads the time from clk
+// and entropy from entropy, or from crypto/rand if entropy is nil.
+// Tests can pass a seeded math/rand.Rand for repeatable ULIDs.
+func NewULIDGenerator(clk clock.Clock, entropy io.Reader) *ULIDGenerator {
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	return &ULIDGenerator{clk: clk, entropy: entropy}
+}
+
+// New returns a new ULID. It returns an error if reading entropy fails,
+// if the clock's time is before the Unix epoch or after the year 10889,
+// or, with ErrULIDOverflow, if no ULID sorts after the last one.
+func (g *ULIDGenerator) New() (ULID, error) {
+	ms := g.clk.Now().UnixMilli()
+	if ms < 0 || ms >= maxULIDTime {
+		return ULID{}, errULIDTime
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.last.IsZero() && ms <= g.last.ms() {
+		u := g.last
+		for i := len(u) - 1; ; i-- {
+			if i < 0 {
+				return ULID{}, ErrULIDOverflow
+			}
+			u[i]++
+			if u[i] != 0 {
+				break
+			}
+		}
+		g.last = u
+		return u, nil
+	}
+	var u ULID
+	for i := 0; i < 6; i++ {
+		u[i] = byte(ms >> (40 - 8*i))
+	}
+	if _, err := io.ReadFull(g.entropy, u[6:]); err != nil {
+		return ULID{}, fmt.Errorf("ids: reading entropy: %w", err)
+	}
+	g.last = u
+	return u, nil
+}
@@ -0,0 +1,115 @@
+package ids
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+var start = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestULIDString(t *testing.T) {
+	// From the ULID spec's example.
+	const s = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	u, err := ParseULID(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.String(); got != s {
+		t.Errorf("String = %q, want %q", got, s)
+	}
+	if got, err := ParseULID(strings.ToLower(s)); err != nil || got != u {
+		t.Errorf("ParseULID of lower case = %v, %v, want %v", got, err, u)
+	}
+	if got, want := u.Time(), time.UnixMilli(1469922850259); !got.Equal(want) {
+		t.Errorf("Time = %v, want %v", got, want)
+	}
+	max, err := ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range max {
+		if b != 0xff {
+			t.Fatalf("greatest ULID parsed as %x", max)
+		}
+	}
+
+	var v ULID
+	text, _ := u.MarshalText()
+	if err := v.UnmarshalText(text); err != nil || v != u {
+		t.Errorf("UnmarshalText(%s) = %v, %v, want %v", text, v, err, u)
+	}
+
+	for _, bad := range []string{"", "01ARZ3NDEKTSV4RRFFQ69G5FA", "81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAU"} {
+		if _, err := ParseULID(bad); err == nil {
+			t.Errorf("ParseULID(%q) didn't fail", bad)
+		}
+	}
+}
+
+func TestULIDGenerator(t *testing.T) {
+	fc := clock.NewFakeAt(start)
+	g := NewULIDGenerator(fc, rand.New(rand.NewSource(1)))
+	first, err := g.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Time().Equal(start) {
+		t.Errorf("Time = %v, want %v", first.Time(), start)
+	}
+
+	// The same seed and clock make the same ULIDs.
+	other, _ := NewULIDGenerator(clock.NewFakeAt(start), rand.New(rand.NewSource(1))).New()
+	if other != first {
+		t.Errorf("generators with the same seed made %v and %v", first, other)
+	}
+
+	last := first
+	next := func(what string) ULID {
+		t.Helper()
+		u, err := g.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Compare(last) <= 0 || u.String() <= last.String() {
+			t.Errorf("ULID %s %v doesn't sort after %v", what, u, last)
+		}
+		last = u
+		return u
+	}
+	if u := next("within a millisecond"); !u.Time().Equal(start) {
+		t.Errorf("Time = %v, want %v", u.Time(), start)
+	}
+	fc.Add(-time.Second)
+	if u := next("after the clock went back"); !u.Time().Equal(start) {
+		t.Errorf("Time after the clock went back = %v, want %v", u.Time(), start)
+	}
+	fc.Add(2 * time.Second)
+	if u := next("after the clock caught up"); !u.Time().Equal(fc.Now()) {
+		t.Errorf("Time after the clock caught up = %v, want %v", u.Time(), fc.Now())
+	}
+}
+
+func TestULIDGeneratorErrors(t *testing.T) {
+	g := NewULIDGenerator(clock.NewFakeAt(time.Unix(-1, 0)), nil)
+	if _, err := g.New(); err == nil {
+		t.Error("New before the Unix epoch didn't fail")
+	}
+
+	g = NewULIDGenerator(clock.NewFakeAt(start), strings.NewReader("short"))
+	if _, err := g.New(); err == nil {
+		t.Error("New with too little entropy didn't fail")
+	}
+
+	g = NewULIDGenerator(clock.NewFakeAt(time.UnixMilli(maxULIDTime-1)), strings.NewReader(strings.Repeat("\xff", 10)))
+	if _, err := g.New(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.New(); !errors.Is(err, ErrULIDOverflow) {
+		t.Errorf("New after the greatest ULID = %v, want %v", err, ErrULIDOverflow)
+	}
+}
@@ -0,0 +1,254 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Interval is a span of time that the current time, or the time of some
+// event, is known to be within, as TrueTime in Google's Spanner reports
+// it. Its Earliest is never after its Latest.
+type Interval struct {
+	Earliest time.Time
+	Latest   time.Time
+}
+
+// Contains reports whether t is within i, inclusive of its ends.
+func (i Interval) Contains(t time.Time) bool {
+	return !t.Before(i.Earliest) && !t.After(i.Latest)
+}
+
+// After reports whether all of i is after t, so that when i is the
+// current time, t has definitely passed.
+func (i Interval) After(t time.Time) bool {
+	return i.Earliest.After(t)
+}
+
+// Before reports whether all of i is before t, so that when i is the
+// current time, t has definitely not yet come.
+func (i Interval) Before(t time.Time) bool {
+	return i.Latest.Before(t)
+}
+
+// Width returns how far apart i's Earliest and Latest are.
+func (i Interval) Width() time.Duration {
+	return i.Latest.Sub(i.Earliest)
+}
+
+// IntervalAround returns the Interval from uncertainty before t to
+// uncertainty after it. It panics if uncertainty is negative.
+func IntervalAround(t time.Time, uncertainty time.Duration) Interval {
+	if uncertainty < 0 {
+		panic("clock: negative uncertainty for IntervalAround")
+	}
+	return Interval{Earliest: t.Add(-uncertainty), Latest: t.Add(uncertainty)}
+}
+
+// Midpoint returns the time halfway between i's Earliest and Latest.
+func (i Interval) Midpoint() time.Time {
+	return i.Earliest.Add(i.Width() / 2)
+}
+
+// DefinitelyBefore reports whether all of i is before all of j, so that
+// an event at a time within i definitely happened before one within j.
+func (i Interval) DefinitelyBefore(j Interval) bool {
+	return i.Latest.Before(j.Earliest)
+}
+
+// DefinitelyAfter reports whether all of i is after all of j.
+func (i Interval) DefinitelyAfter(j Interval) bool {
+	return i.Earliest.After(j.Latest)
+}
+
+// Overlaps reports whether i and j have a time in common, inclusive of
+// their ends, so that events within them can't be ordered.
+func (i Interval) Overlaps(j Interval) bool {
+	return !i.DefinitelyBefore(j) && !i.DefinitelyAfter(j)
+}
+
+// Compare returns -1 if i is definitely before j, +1 if it is definitely
+// after j, and 0 if they overlap.
+func (i Interval) Compare(j Interval) int {
+	switch {
+	case i.DefinitelyBefore(j):
+		return -1
+	case i.DefinitelyAfter(j):
+		return +1
+	}
+	return 0
+}
+
+// Intersect returns the times that are within both i and j, and whether
+// there are any.
+func (i Interval) Intersect(j Interval) (Interval, bool) {
+	if !i.Overlaps(j) {
+		return Interval{}, false
+	}
+	return Interval{Earliest: latest(i.Earliest, j.Earliest), Latest: earliest(i.Latest, j.Latest)}, true
+}
+
+// Union returns the smallest Interval that contains both i and j.
+func (i Interval) Union(j Interval) Interval {
+	return Interval{Earliest: earliest(i.Earliest, j.Earliest), Latest: latest(i.Latest, j.Latest)}
+}
+
+// Add returns i moved by d.
+func (i Interval) Add(d time.Duration) Interval {
+	return Interval{Earliest: i.Earliest.Add(d), Latest: i.Latest.Add(d)}
+}
+
+// Widen returns i with d more uncertainty either side of it. It panics
+// if d is negative.
+func (i Interval) Widen(d time.Duration) Interval {
+	if d < 0 {
+		panic("clock: negative uncertainty for Widen")
+	}
+	return Interval{Earliest: i.Earliest.Add(-d), Latest: i.Latest.Add(d)}
+}
+
+// Sub returns the least and greatest durations that could have passed
+// from a time within j to one within i.
+func (i Interval) Sub(j Interval) (lo, hi time.Duration) {
+	return i.Earliest.Sub(j.Latest), i.Latest.Sub(j.Earliest)
+}
+
+func earliest(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// IntervalClock is a Clock that knows how far off its time may be. It
+// is for code that needs timestamps ordered across machines, such as
+// Spanner-style commit wait: a transaction commits with the Latest of
+// NowInterval as its timestamp, and only reports success after
+// WaitUntilAfter that Interval, so that any transaction starting
+// afterwards, on any machine, gets a later timestamp.
+type IntervalClock interface {
+	Clock
+
+	// NowInterval returns an Interval that the current time is within:
+	// Now with the clock's uncertainty on either side of it.
+	NowInterval() Interval
+
+	// WaitUntilAfter blocks until the whole of the clock's NowInterval
+	// is after i's Latest, which is at least twice the uncertainty
+	// after i was the current time.
+	WaitUntilAfter(i Interval)
+}
+
+// NewIntervalClock returns an IntervalClock whose time is base's, and
+// which is never off by more than uncertainty, such as a bound on the
+// system clock's error that its NTP daemon reports. It panics if
+// uncertainty is negative.
+func NewIntervalClock(base Clock, uncertainty time.Duration) IntervalClock {
+	return intervalClock{base, newIntervals(base, uncertainty)}
+}
+
+type intervalClock struct {
+	Clock
+	*intervals
+}
+
+// FakeIntervalClock is a FakeClock that is also an IntervalClock, whose
+// uncertainty tests control.
+type FakeIntervalClock interface {
+	FakeClock
+	IntervalClock
+
+	// SetUncertainty changes how far either side of Now NowInterval's
+	// Interval goes. Calls to WaitUntilAfter that the new uncertainty
+	// lets through return without the clock being moved. It panics if
+	// d is negative.
+	SetUncertainty(d time.Duration)
+
+	// Uncertainty returns how far either side of Now NowInterval's
+	// Interval goes.
+	Uncertainty() time.Duration
+}
+
+// NewFakeInterval returns a FakeIntervalClock with the given starting
+// uncertainty, made by NewFake with opts. It panics if uncertainty is
+// negative.
+func NewFakeInterval(uncertainty time.Duration, opts ...Option) FakeIntervalClock {
+	fc := NewFake(opts...)
+	return fakeIntervalClock{fc, newIntervals(fc, uncertainty)}
+}
+
+type fakeIntervalClock struct {
+	FakeClock
+	*intervals
+}
+
+// intervals implements the methods IntervalClock and FakeIntervalClock
+// add to a Clock.
+type intervals struct {
+	base Clock
+
+	mu          sync.Mutex
+	uncertainty time.Duration
+	// changed is closed, and replaced, whenever uncertainty changes, to
+	// wake calls to WaitUntilAfter.
+	changed chan struct{}
+}
+
+func newIntervals(base Clock, uncertainty time.Duration) *intervals {
+	if uncertainty < 0 {
+		panic("clock: negative uncertainty for IntervalClock")
+	}
+	return &intervals{base: base, uncertainty: uncertainty, changed: make(chan struct{})}
+}
+
+func (iv *intervals) NowInterval() Interval {
+	now := iv.base.Now()
+	iv.mu.Lock()
+	u := iv.uncertainty
+	iv.mu.Unlock()
+	return IntervalAround(now, u)
+}
+
+func (iv *intervals) WaitUntilAfter(i Interval) {
+	for {
+		now := iv.base.Now()
+		iv.mu.Lock()
+		u, changed := iv.uncertainty, iv.changed
+		iv.mu.Unlock()
+		// Earliest must be strictly after i.Latest, so wait a
+		// nanosecond past the time it would equal it.
+		d := i.Latest.Sub(now.Add(-u)) + 1
+		if d <= 0 {
+			return
+		}
+		t := iv.base.NewTimer(d)
+		select {
+		case <-t.C():
+		case <-changed:
+			t.Stop()
+		}
+	}
+}
+
+func (iv *intervals) SetUncertainty(d time.Duration) {
+	if d < 0 {
+		panic("clock: negative uncertainty for FakeIntervalClock.SetUncertainty")
+	}
+	iv.mu.Lock()
+	defer iv.mu.Unlock()
+	iv.uncertainty = d
+	close(iv.changed)
+	iv.changed = make(chan struct{})
+}
+
+func (iv *intervals) Uncertainty() time.Duration {
+	iv.mu.Lock()
+	defer iv.mu.Unlock()
+	return iv.uncertainty
+}
@@ -0,0 +1,164 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterval(t *testing.T) {
+	start := time.Date(2012, 9, 1, 0, 0, 0, 0, time.UTC)
+	i := Interval{Earliest: start, Latest: start.Add(2 * time.Second)}
+	if got := i.Width(); got != 2*time.Second {
+		t.Errorf("Width() = %v, want 2s", got)
+	}
+	for _, tc := range []struct {
+		t                       time.Time
+		contains, after, before bool
+	}{
+		{start.Add(-time.Second), false, true, false},
+		{start, true, false, false},
+		{start.Add(time.Second), true, false, false},
+		{start.Add(2 * time.Second), true, false, false},
+		{start.Add(3 * time.Second), false, false, true},
+	} {
+		if got := i.Contains(tc.t); got != tc.contains {
+			t.Errorf("Contains(%v) = %t, want %t", tc.t, got, tc.contains)
+		}
+		if got := i.After(tc.t); got != tc.after {
+			t.Errorf("After(%v) = %t, want %t", tc.t, got, tc.after)
+		}
+		if got := i.Before(tc.t); got != tc.before {
+			t.Errorf("Before(%v) = %t, want %t", tc.t, got, tc.before)
+		}
+	}
+}
+
+func TestIntervalCompare(t *testing.T) {
+	start := time.Date(2012, 9, 1, 0, 0, 0, 0, time.UTC)
+	i := IntervalAround(start, time.Second)
+	for _, tc := range []struct {
+		j             Interval
+		before, after bool
+		compare       int
+		intersect     Interval
+		lo, hi        time.Duration
+	}{
+		{IntervalAround(start.Add(3*time.Second), time.Second), true, false, -1, Interval{}, -5 * time.Second, -time.Second},
+		{IntervalAround(start.Add(-3*time.Second), time.Second), false, true, +1, Interval{}, time.Second, 5 * time.Second},
+		{IntervalAround(start.Add(2*time.Second), time.Second), false, false, 0, Interval{start.Add(time.Second), start.Add(time.Second)}, -4 * time.Second, 0},
+		{IntervalAround(start, time.Millisecond), false, false, 0, IntervalAround(start, time.Millisecond), -time.Second - time.Millisecond, time.Second + time.Millisecond},
+	} {
+		if got := i.DefinitelyBefore(tc.j); got != tc.before {
+			t.Errorf("DefinitelyBefore(%v) = %t, want %t", tc.j, got, tc.before)
+		}
+		if got := i.DefinitelyAfter(tc.j); got != tc.after {
+			t.Errorf("DefinitelyAfter(%v) = %t, want %t", tc.j, got, tc.after)
+		}
+		if got := i.Overlaps(tc.j); got != (tc.compare == 0) {
+			t.Errorf("Overlaps(%v) = %t, want %t", tc.j, got, tc.compare == 0)
+		}
+		if got := i.Compare(tc.j); got != tc.compare {
+			t.Errorf("Compare(%v) = %d, want %d", tc.j, got, tc.compare)
+		}
+		if got, ok := i.Intersect(tc.j); got != tc.intersect || ok != (tc.compare == 0) {
+			t.Errorf("Intersect(%v) = %v, %t, want %v, %t", tc.j, got, ok, tc.intersect, tc.compare == 0)
+		}
+		if lo, hi := i.Sub(tc.j); lo != tc.lo || hi != tc.hi {
+			t.Errorf("Sub(%v) = %v, %v, want %v, %v", tc.j, lo, hi, tc.lo, tc.hi)
+		}
+	}
+
+	j := IntervalAround(start.Add(5*time.Second), time.Second)
+	if got, want := i.Union(j), (Interval{start.Add(-time.Second), start.Add(6 * time.Second)}); got != want {
+		t.Errorf("Union = %v, want %v", got, want)
+	}
+	if got, want := i.Add(time.Minute), IntervalAround(start.Add(time.Minute), time.Second); got != want {
+		t.Errorf("Add = %v, want %v", got, want)
+	}
+	if got, want := i.Widen(time.Second), IntervalAround(start, 2*time.Second); got != want {
+		t.Errorf("Widen = %v, want %v", got, want)
+	}
+	if got := i.Midpoint(); !got.Equal(start) {
+		t.Errorf("Midpoint = %v, want %v", got, start)
+	}
+}
+
+func TestFakeIntervalClock(t *testing.T) {
+	fc := NewFakeInterval(time.Second)
+	now := fc.Now()
+	got := fc.NowInterval()
+	if want := (Interval{now.Add(-time.Second), now.Add(time.Second)}); got != want {
+		t.Errorf("NowInterval() = %v, want %v", got, want)
+	}
+	fc.SetUncertainty(0)
+	if got := fc.NowInterval(); !got.Earliest.Equal(now) || !got.Latest.Equal(now) {
+		t.Errorf("NowInterval() with no uncertainty = %v, want %v on both ends", got, now)
+	}
+	if got := fc.Uncertainty(); got != 0 {
+		t.Errorf("Uncertainty() = %v, want 0", got)
+	}
+}
+
+func TestWaitUntilAfter(t *testing.T) {
+	fc := NewFakeInterval(time.Second)
+	commit := fc.NowInterval()
+	done := make(chan struct{})
+	go func() {
+		fc.WaitUntilAfter(commit)
+		close(done)
+	}()
+
+	fc.BlockUntil(1)
+	fc.Add(2 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("WaitUntilAfter returned with Earliest equal to the Interval's Latest")
+	case <-time.After(10 * time.Millisecond):
+	}
+	fc.BlockUntil(1)
+	fc.Add(time.Nanosecond)
+	<-done
+	if !fc.NowInterval().After(commit.Latest) {
+		t.Errorf("WaitUntilAfter returned before %v had passed", commit.Latest)
+	}
+}
+
+func TestWaitUntilAfterUncertaintyChange(t *testing.T) {
+	fc := NewFakeInterval(time.Second)
+	commit := fc.NowInterval()
+	done := make(chan struct{})
+	go func() {
+		fc.WaitUntilAfter(commit)
+		close(done)
+	}()
+
+	fc.BlockUntil(1)
+	fc.Add(time.Second + 1)
+	fc.SetUncertainty(time.Second / 2)
+	select {
+	case <-done:
+		t.Fatal("WaitUntilAfter returned while the Interval was still within the clock's")
+	case <-time.After(10 * time.Millisecond):
+	}
+	fc.SetUncertainty(0)
+	<-done
+	if got := fc.Waiters(); got != 0 {
+		t.Errorf("WaitUntilAfter left %d waiters on the clock", got)
+	}
+}
+
+func TestNewIntervalClock(t *testing.T) {
+	fc := NewFake()
+	clk := NewIntervalClock(fc, time.Minute)
+	if got := clk.NowInterval(); got.Width() != 2*time.Minute || !got.Contains(fc.Now()) {
+		t.Errorf("NowInterval() = %v, want a minute each side of %v", got, fc.Now())
+	}
+	clk.WaitUntilAfter(Interval{fc.Now().Add(-2 * time.Minute), fc.Now().Add(-time.Minute - 1)})
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewIntervalClock with a negative uncertainty didn't panic")
+		}
+	}()
+	NewIntervalClock(fc, -1)
+}
@@ -0,0 +1,57 @@
+package clock
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Jitter returns a Clock whose Now returns base's time moved by a
+// pseudo-random amount between -max and max, picked afresh for every
+// reading, so that tests can check code copes with small differences
+// between timestamps. The amounts are picked using seed, so a test
+// using the same seed and reading the clock in the same order sees the
+// same ones. NowMonotonic and the Clock's Timers, Tickers and sleepers
+// are base's, without jitter.
+func Jitter(base Clock, max time.Duration, seed int64) Clock {
+	return &jitter{Clock: base, max: max, r: rand.New(rand.NewSource(seed))}
+}
+
+type jitter struct {
+	Clock
+	max time.Duration
+
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (j *jitter) Now() time.Time {
+	t := j.Clock.Now()
+	if j.max <= 0 {
+		return t
+	}
+	j.mu.Lock()
+	d := time.Duration(j.r.Int63n(2*int64(j.max)+1)) - j.max
+	j.mu.Unlock()
+	return t.Add(d)
+}
+
+func (j *jitter) NowUnix() int64 {
+	return j.Now().Unix()
+}
+
+func (j *jitter) NowUnixMilli() int64 {
+	return j.Now().UnixMilli()
+}
+
+func (j *jitter) NowUnixNano() int64 {
+	return j.Now().UnixNano()
+}
+
+func (j *jitter) Since(t time.Time) time.Duration {
+	return j.Now().Sub(t)
+}
+
+func (j *jitter) Until(t time.Time) time.Duration {
+	return t.Sub(j.Now())
+}
@@ -0,0 +1,32 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	fc := NewFake()
+	a := Jitter(fc, time.Millisecond, 42)
+	b := Jitter(fc, time.Millisecond, 42)
+	var varied bool
+	for i := 0; i < 100; i++ {
+		got := a.Since(fc.Now())
+		if got < -time.Millisecond || got > time.Millisecond {
+			t.Fatalf("jitter of %v is more than %v", got, time.Millisecond)
+		}
+		if got != 0 {
+			varied = true
+		}
+		if other := b.Since(fc.Now()); other != got {
+			t.Errorf("reading %d: Jitter clocks with the same seed differ: %v and %v", i, got, other)
+		}
+	}
+	if !varied {
+		t.Errorf("Jitter never moved Now")
+	}
+
+	if got := Jitter(fc, 0, 1).Now(); !got.Equal(fc.Now()) {
+		t.Errorf("Jitter with max of zero moved Now to %v", got)
+	}
+}
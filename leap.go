@@ -0,0 +1,213 @@
+package clock
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LeapSecond is an entry of a LeapTable: a change in TAI−UTC, the
+// number of seconds by which International Atomic Time is ahead of
+// UTC.
+type LeapSecond struct {
+	// Time is the UTC time from which TAIOffset applies, the start of
+	// the day after the leap second.
+	Time time.Time
+
+	// TAIOffset is TAI−UTC, in seconds, from Time on.
+	TAIOffset int
+}
+
+// LeapTable is a table of leap seconds, for converting between UTC and
+// TAI. LeapSeconds is the table built into the package, and
+// ParseLeapSecondsList reads the tables IERS and NIST publish, which
+// include leap seconds announced since the package was built.
+type LeapTable struct {
+	leaps   []LeapSecond
+	expires time.Time
+}
+
+// LeapSeconds is the table of leap seconds as of when the package was
+// written, from the first, when TAI−UTC became 10 seconds at the start
+// of 1972, to the latest, when it became 37 at the start of 2017. It
+// has no expiry, so programs that must know of leap seconds announced
+// since should load a current leap-seconds.list with
+// ParseLeapSecondsList instead.
+var LeapSeconds = mustLeapTable([]LeapSecond{
+	{time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), 10},
+	{time.Date(1972, 7, 1, 0, 0, 0, 0, time.UTC), 11},
+	{time.Date(1973, 1, 1, 0, 0, 0, 0, time.UTC), 12},
+	{time.Date(1974, 1, 1, 0, 0, 0, 0, time.UTC), 13},
+	{time.Date(1975, 1, 1, 0, 0, 0, 0, time.UTC), 14},
+	{time.Date(1976, 1, 1, 0, 0, 0, 0, time.UTC), 15},
+	{time.Date(1977, 1, 1, 0, 0, 0, 0, time.UTC), 16},
+	{time.Date(1978, 1, 1, 0, 0, 0, 0, time.UTC), 17},
+	{time.Date(1979, 1, 1, 0, 0, 0, 0, time.UTC), 18},
+	{time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC), 19},
+	{time.Date(1981, 7, 1, 0, 0, 0, 0, time.UTC), 20},
+	{time.Date(1982, 7, 1, 0, 0, 0, 0, time.UTC), 21},
+	{time.Date(1983, 7, 1, 0, 0, 0, 0, time.UTC), 22},
+	{time.Date(1985, 7, 1, 0, 0, 0, 0, time.UTC), 23},
+	{time.Date(1988, 1, 1, 0, 0, 0, 0, time.UTC), 24},
+	{time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), 25},
+	{time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC), 26},
+	{time.Date(1992, 7, 1, 0, 0, 0, 0, time.UTC), 27},
+	{time.Date(1993, 7, 1, 0, 0, 0, 0, time.UTC), 28},
+	{time.Date(1994, 7, 1, 0, 0, 0, 0, time.UTC), 29},
+	{time.Date(1996, 1, 1, 0, 0, 0, 0, time.UTC), 30},
+	{time.Date(1997, 7, 1, 0, 0, 0, 0, time.UTC), 31},
+	{time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), 32},
+	{time.Date(2006, 1, 1, 0, 0, 0, 0, time.UTC), 33},
+	{time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC), 34},
+	{time.Date(2012, 7, 1, 0, 0, 0, 0, time.UTC), 35},
+	{time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC), 36},
+	{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37},
+}, time.Time{})
+
+func mustLeapTable(leaps []LeapSecond, expires time.Time) *LeapTable {
+	lt, err := NewLeapTable(leaps, expires)
+	if err != nil {
+		panic(err)
+	}
+	return lt
+}
+
+// NewLeapTable returns a LeapTable of leaps, which expires, if it isn't
+// the zero time, when the table's source can no longer promise that no
+// leap seconds after its last one have been announced. leaps must not
+// be empty, must be in order of Time, and, after the first, each must
+// change TAIOffset by a single leap second, either way.
+func NewLeapTable(leaps []LeapSecond, expires time.Time) (*LeapTable, error) {
+	if len(leaps) == 0 {
+		return nil, errors.New("clock: empty leap second table")
+	}
+	for i := 1; i < len(leaps); i++ {
+		if !leaps[i].Time.After(leaps[i-1].Time) {
+			return nil, fmt.Errorf("clock: leap second at %v is out of order", leaps[i].Time)
+		}
+		if d := leaps[i].TAIOffset - leaps[i-1].TAIOffset; d != 1 && d != -1 {
+			return nil, fmt.Errorf("clock: leap second at %v changes TAI−UTC by %d seconds", leaps[i].Time, d)
+		}
+	}
+	leaps = slices.Clone(leaps)
+	for i := range leaps {
+		leaps[i].Time = leaps[i].Time.Round(0).UTC()
+	}
+	return &LeapTable{leaps: leaps, expires: expires}, nil
+}
+
+// ntpEpoch is the start of the NTP era that leap-seconds.list times
+// are seconds since.
+var ntpEpoch = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ParseLeapSecondsList parses a leap-seconds.list file, as published
+// by IERS and NIST and installed with many systems' time zone data,
+// such as at /usr/share/zoneinfo/leap-seconds.list. Its expiry is the
+// file's.
+func ParseLeapSecondsList(r io.Reader) (*LeapTable, error) {
+	var leaps []LeapSecond
+	var expires time.Time
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		if rest, ok := strings.CutPrefix(line, "#@"); ok {
+			secs, err := strconv.ParseInt(strings.TrimSpace(rest), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("clock: leap-seconds.list line %d: invalid expiry: %w", n, err)
+			}
+			expires = ntpEpoch.Add(time.Duration(secs) * time.Second)
+			continue
+		}
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("clock: leap-seconds.list line %d: want a time and an offset", n)
+		}
+		secs, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("clock: leap-seconds.list line %d: invalid time: %w", n, err)
+		}
+		offset, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("clock: leap-seconds.list line %d: invalid offset: %w", n, err)
+		}
+		leaps = append(leaps, LeapSecond{ntpEpoch.Add(time.Duration(secs) * time.Second), offset})
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("clock: reading leap-seconds.list: %w", err)
+	}
+	return NewLeapTable(leaps, expires)
+}
+
+// Leaps returns the table's entries, in order of Time.
+func (lt *LeapTable) Leaps() []LeapSecond {
+	return slices.Clone(lt.leaps)
+}
+
+// Expires returns when the table expires, or the zero time if it
+// doesn't.
+func (lt *LeapTable) Expires() time.Time {
+	return lt.expires
+}
+
+// Offset returns TAI−UTC at the UTC time t. Before the table's first
+// entry, it is the first entry's TAIOffset. That is only approximately
+// right before 1972, when UTC's seconds were stretched rather than
+// leapt to follow the Earth's rotation.
+func (lt *LeapTable) Offset(t time.Time) time.Duration {
+	i, found := slices.BinarySearchFunc(lt.leaps, t, func(l LeapSecond, t time.Time) int {
+		return l.Time.Compare(t)
+	})
+	if !found {
+		i--
+	}
+	return time.Duration(lt.leaps[max(i, 0)].TAIOffset) * time.Second
+}
+
+// ToTAI returns the TAI time of the UTC time t.
+func (lt *LeapTable) ToTAI(t time.Time) TAI {
+	t = t.Round(0).UTC()
+	return TAI{t.Add(lt.Offset(t))}
+}
+
+// FromTAI returns the UTC time of the TAI time a. time.Time can't
+// represent the leap second 23:59:60 itself, so if a is during one,
+// FromTAI returns the second before it, 23:59:59 with the same
+// fraction, and reports inLeap, so that UTC times repeat the second
+// before a leap second, as most systems' clocks do.
+func (lt *LeapTable) FromTAI(a TAI) (t time.Time, inLeap bool) {
+	for i := len(lt.leaps) - 1; i >= 0; i-- {
+		l := lt.leaps[i]
+		t := a.t.Add(-time.Duration(l.TAIOffset) * time.Second)
+		if !t.Before(l.Time) || i == 0 {
+			return t, false
+		}
+		// During a positive leap second, a is too early to be after
+		// this entry's Time at its offset, but not at the previous
+		// entry's.
+		prev := a.t.Add(-time.Duration(lt.leaps[i-1].TAIOffset) * time.Second)
+		if !prev.Before(l.Time) {
+			return prev.Add(-time.Second), true
+		}
+	}
+	panic("unreachable")
+}
+
+// FormatRFC3339 formats the TAI time a as its UTC time in RFC 3339's
+// format, like time.RFC3339Nano, but with the seconds of a leap second
+// written as 60, as RFC 3339 allows, rather than repeating 59.
+func (lt *LeapTable) FormatRFC3339(a TAI) string {
+	t, inLeap := lt.FromTAI(a)
+	if !inLeap {
+		return t.Format(time.RFC3339Nano)
+	}
+	return t.Format("2006-01-02T15:04:") + "60" + t.Format(".999999999") + "Z"
+}
@@ -0,0 +1,247 @@
+package clock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLeapTable(t *testing.T) {
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		utc  time.Time
+		want time.Duration
+	}{
+		{time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), 10 * time.Second},
+		{time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), 10 * time.Second},
+		{time.Date(1999, 6, 1, 0, 0, 0, 0, time.UTC), 32 * time.Second},
+		{leap.Add(-time.Nanosecond), 36 * time.Second},
+		{leap, 37 * time.Second},
+		{time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), 37 * time.Second},
+	} {
+		if got := LeapSeconds.Offset(tc.utc); got != tc.want {
+			t.Errorf("Offset(%v) = %v, want %v", tc.utc, got, tc.want)
+		}
+	}
+	if got := len(LeapSeconds.Leaps()); got != 28 {
+		t.Errorf("LeapSeconds has %d entries, want 28", got)
+	}
+	if !LeapSeconds.Expires().IsZero() {
+		t.Errorf("LeapSeconds expires at %v, want never", LeapSeconds.Expires())
+	}
+}
+
+func TestLeapTableFromTAI(t *testing.T) {
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := LeapSeconds.ToTAI(leap.Add(-time.Second / 2))
+	after := LeapSeconds.ToTAI(leap)
+	if got := after.Sub(before); got != 1500*time.Millisecond {
+		t.Errorf("TAI time across the leap second = %v, want 1.5s", got)
+	}
+	for _, tc := range []struct {
+		a      TAI
+		want   time.Time
+		inLeap bool
+	}{
+		{before, leap.Add(-time.Second / 2), false},
+		{before.Add(time.Second / 2), leap.Add(-time.Second), true},
+		{before.Add(time.Second), leap.Add(-time.Second / 2), true},
+		{after, leap, false},
+		{TAIFromReading(time.Date(1960, 1, 1, 0, 0, 10, 0, time.UTC)), time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), false},
+	} {
+		got, inLeap := LeapSeconds.FromTAI(tc.a)
+		if !got.Equal(tc.want) || inLeap != tc.inLeap {
+			t.Errorf("FromTAI(%v) = %v, %t, want %v, %t", tc.a, got, inLeap, tc.want, tc.inLeap)
+		}
+	}
+}
+
+func TestLeapTableNegative(t *testing.T) {
+	// A negative leap second skips 23:59:59.
+	leap := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	lt, err := NewLeapTable([]LeapSecond{{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37}, {leap, 36}}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lt.ToTAI(leap).Sub(lt.ToTAI(leap.Add(-2 * time.Second))); got != time.Second {
+		t.Errorf("TAI time from 23:59:58 to 00:00:00 across a negative leap second = %v, want 1s", got)
+	}
+	a := lt.ToTAI(leap)
+	if got, inLeap := lt.FromTAI(a); !got.Equal(leap) || inLeap {
+		t.Errorf("FromTAI(%v) = %v, %t, want %v, false", a, got, inLeap, leap)
+	}
+}
+
+func TestNewLeapTableErrors(t *testing.T) {
+	t1 := time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, leaps := range [][]LeapSecond{
+		nil,
+		{{t2, 36}, {t1, 37}},
+		{{t1, 36}, {t2, 38}},
+	} {
+		if _, err := NewLeapTable(leaps, time.Time{}); err == nil {
+			t.Errorf("NewLeapTable(%v) didn't fail", leaps)
+		}
+	}
+}
+
+func TestParseLeapSecondsList(t *testing.T) {
+	const list = `#	Leap second list excerpt
+#$	 3676924800
+#@	3960057600
+#
+2272060800	10	# 1 Jan 1972
+2287785600	11	# 1 Jul 1972
+
+2303683200	12	# 1 Jan 1973
+#h	16edd0f0 3666784f 37db6bdd e74ced87 59af48f1
+`
+	lt, err := ParseLeapSecondsList(strings.NewReader(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LeapSecond{
+		{time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), 10},
+		{time.Date(1972, 7, 1, 0, 0, 0, 0, time.UTC), 11},
+		{time.Date(1973, 1, 1, 0, 0, 0, 0, time.UTC), 12},
+	}
+	got := lt.Leaps()
+	if len(got) != len(want) {
+		t.Fatalf("Leaps() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) || got[i].TAIOffset != want[i].TAIOffset {
+			t.Errorf("Leaps()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if got, want := lt.Expires(), time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expires() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{
+		"",
+		"#@ soon\n2272060800 10\n",
+		"2272060800\n",
+		"x 10\n",
+		"2272060800 ten\n",
+	} {
+		if _, err := ParseLeapSecondsList(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseLeapSecondsList(%q) didn't fail", bad)
+		}
+	}
+}
+
+func TestLeapTableFormatRFC3339(t *testing.T) {
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := LeapSeconds.ToTAI(leap)
+	for _, tc := range []struct {
+		a    TAI
+		want string
+	}{
+		{a.Add(-1500 * time.Millisecond), "2016-12-31T23:59:59.5Z"},
+		{a.Add(-time.Second), "2016-12-31T23:59:60Z"},
+		{a.Add(-500 * time.Millisecond), "2016-12-31T23:59:60.5Z"},
+		{a, "2017-01-01T00:00:00Z"},
+	} {
+		if got := LeapSeconds.FormatRFC3339(tc.a); got != tc.want {
+			t.Errorf("FormatRFC3339(%v) = %q, want %q", tc.a, got, tc.want)
+		}
+	}
+}
+
+func TestFakeLeapSecond(t *testing.T) {
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := leap.Add(-1500 * time.Millisecond)
+	fc := NewFakeAt(start, WithLeapSeconds(LeapSeconds))
+	timer := fc.NewTimer(2 * time.Second)
+
+	fc.Add(time.Second)
+	if got, want := fc.Now(), leap.Add(-500*time.Millisecond); !got.Equal(want) {
+		t.Fatalf("Now before the leap second = %v, want %v", got, want)
+	}
+	before := fc.NowTAI()
+
+	fc.Add(time.Second)
+	if got, want := fc.Now(), leap.Add(-500*time.Millisecond); !got.Equal(want) {
+		t.Errorf("Now during the leap second = %v, want the repeated %v", got, want)
+	}
+	if got := fc.NowTAI().Sub(before); got != time.Second {
+		t.Errorf("NowTAI moved by %v during the leap second, want 1s", got)
+	}
+	if got, want := LeapSeconds.FormatRFC3339(fc.NowTAI()), "2016-12-31T23:59:60.5Z"; got != want {
+		t.Errorf("FormatRFC3339(NowTAI()) = %q, want %q", got, want)
+	}
+	select {
+	case got := <-timer.C():
+		if !got.Equal(fc.Now()) {
+			t.Errorf("timer sent %v, want %v", got, fc.Now())
+		}
+	default:
+		t.Error("2s timer didn't fire after 2s across the leap second")
+	}
+
+	fc.Add(time.Second)
+	if got, want := fc.Now(), leap.Add(500*time.Millisecond); !got.Equal(want) {
+		t.Errorf("Now after the leap second = %v, want %v", got, want)
+	}
+	if got := fc.Since(start); got != time.Second*2 {
+		t.Errorf("Since across the leap second = %v, want 2s", got)
+	}
+	if got := fc.NowMonotonic(); got != 3*time.Second {
+		t.Errorf("NowMonotonic = %v, want 3s", got)
+	}
+
+	// Going back before the leap second steps for it again.
+	fc.Set(start)
+	fc.Set(leap)
+	if got := fc.NowTAI().Sub(LeapSeconds.ToTAI(start)); got != 2500*time.Millisecond {
+		t.Errorf("TAI time from Set(%v) to Set(%v) = %v, want 2.5s", start, leap, got)
+	}
+}
+
+func TestFakeLeapSecondSet(t *testing.T) {
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := NewFakeAt(leap.Add(-2*time.Second), WithLeapSeconds(LeapSeconds))
+	timer := fc.NewTimer(3500 * time.Millisecond)
+	fc.Set(leap.Add(time.Second))
+	if got, want := fc.Now(), leap.Add(time.Second); !got.Equal(want) {
+		t.Errorf("Now after Set = %v, want %v", got, want)
+	}
+	select {
+	case <-timer.C():
+	default:
+		t.Error("3.5s timer didn't fire after Set moved the clock 4s across the leap second")
+	}
+}
+
+func TestFakeNegativeLeapSecond(t *testing.T) {
+	leap := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	lt, err := NewLeapTable([]LeapSecond{{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37}, {leap, 36}}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc := NewFakeAt(leap.Add(-1500*time.Millisecond), WithLeapSeconds(lt))
+	timer := fc.NewTimer(time.Second)
+	fc.Add(time.Second)
+	if got, want := fc.Now(), leap.Add(500*time.Millisecond); !got.Equal(want) {
+		t.Errorf("Now after a negative leap second = %v, want %v", got, want)
+	}
+	select {
+	case <-timer.C():
+	default:
+		t.Error("1s timer didn't fire after 1s across the negative leap second")
+	}
+	if got := fc.NowMonotonic(); got != time.Second {
+		t.Errorf("NowMonotonic = %v, want 1s", got)
+	}
+}
+
+func TestFakeNowTAI(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := NewFakeAt(now)
+	if got, want := fc.NowTAI(), LeapSeconds.ToTAI(now); !got.Equal(want) {
+		t.Errorf("NowTAI = %v, want %v", got, want)
+	}
+	var _ TAIClock = fc
+}
@@ -0,0 +1,8 @@
+// Package logical provides Lamport and vector clocks, logical clocks
+// that order events by causality rather than by time, for code that
+// sends messages between processes and for tests simulating it
+// alongside a clock.FakeClock.
+//
+// For timestamps that also stay close to a clock.Clock's time, see
+// package hlc.
+package logical
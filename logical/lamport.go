@@ -0,0 +1,36 @@
+package logical
+
+import "sync/atomic"
+
+// Lamport is a Lamport clock. If one event happened before another,
+// its Lamport time is lower, though a lower time doesn't mean an event
+// happened before one with a higher time. The zero Lamport is ready to
+// use, at time 0, and it is safe for concurrent use.
+type Lamport struct {
+	t atomic.Uint64
+}
+
+// Tick records a local event, such as sending a message, and returns
+// its time.
+func (l *Lamport) Tick() uint64 {
+	return l.t.Add(1)
+}
+
+// Observe records the receipt of a message sent at remote, and returns
+// the receipt's time, which is after both remote and every event l has
+// recorded before.
+func (l *Lamport) Observe(remote uint64) uint64 {
+	for {
+		cur := l.t.Load()
+		next := max(cur, remote) + 1
+		if l.t.CompareAndSwap(cur, next) {
+			return next
+		}
+	}
+}
+
+// Current returns the time of the latest event l has recorded, or 0 if
+// it hasn't recorded any.
+func (l *Lamport) Current() uint64 {
+	return l.t.Load()
+}
@@ -0,0 +1,47 @@
+package logical
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLamport(t *testing.T) {
+	var a, b Lamport
+	if got := a.Current(); got != 0 {
+		t.Errorf("zero Lamport's Current() = %d, want 0", got)
+	}
+	if got := a.Tick(); got != 1 {
+		t.Errorf("Tick() = %d, want 1", got)
+	}
+	sent := a.Tick()
+	if got, want := b.Observe(sent), sent+1; got != want {
+		t.Errorf("Observe(%d) = %d, want %d", sent, got, want)
+	}
+	b.Tick()
+	b.Tick()
+	if got, want := b.Observe(1), sent+4; got != want {
+		t.Errorf("Observe of an old time = %d, want %d", got, want)
+	}
+	if got, want := b.Current(), sent+4; got != want {
+		t.Errorf("Current() = %d, want %d", got, want)
+	}
+}
+
+func TestLamportConcurrent(t *testing.T) {
+	var l Lamport
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				l.Tick()
+				l.Observe(0)
+			}
+		}()
+	}
+	wg.Wait()
+	if got, want := l.Current(), uint64(2000); got != want {
+		t.Errorf("Current() = %d, want %d", got, want)
+	}
+}
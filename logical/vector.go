@@ -0,0 +1,113 @@
+package logical
+
+// Vector is a vector clock: for each node, by ID, the number of events
+// on that node that an event has seen, counting itself. A node missing
+// from a Vector has a count of 0. The nil Vector is the time before any
+// events, and like other maps, a Vector isn't safe for concurrent
+// writes.
+type Vector map[string]uint64
+
+// Tick records a local event on node, such as sending a message, and
+// returns v, which is allocated if it was nil. Send a Copy of the result
+// with the message.
+func (v Vector) Tick(node string) Vector {
+	if v == nil {
+		v = make(Vector)
+	}
+	v[node]++
+	return v
+}
+
+// Merge records that v has seen everything remote has, and returns v,
+// which is allocated if it was nil. A node receiving a message merges
+// the message's Vector into its own and then Ticks itself.
+func (v Vector) Merge(remote Vector) Vector {
+	if v == nil && len(remote) > 0 {
+		v = make(Vector, len(remote))
+	}
+	for node, n := range remote {
+		if n > v[node] {
+			v[node] = n
+		}
+	}
+	return v
+}
+
+// Copy returns a copy of v.
+func (v Vector) Copy() Vector {
+	if v == nil {
+		return nil
+	}
+	c := make(Vector, len(v))
+	for node, n := range v {
+		c[node] = n
+	}
+	return c
+}
+
+// Ordering is the causal relation between two Vectors.
+type Ordering int
+
+const (
+	// Equal means the Vectors have seen the same events.
+	Equal Ordering = iota
+	// Before means the first Vector happened before the second: the
+	// second has seen every event the first has, and more.
+	Before
+	// After means the second Vector happened before the first.
+	After
+	// Concurrent means each Vector has seen events the other hasn't,
+	// so neither happened before the other.
+	Concurrent
+)
+
+func (o Ordering) String() string {
+	switch o {
+	case Equal:
+		return "Equal"
+	case Before:
+		return "Before"
+	case After:
+		return "After"
+	case Concurrent:
+		return "Concurrent"
+	}
+	return "Ordering(?)"
+}
+
+// Compare returns how v relates to w.
+func (v Vector) Compare(w Vector) Ordering {
+	less, greater := false, false
+	for node, n := range v {
+		if m := w[node]; n < m {
+			less = true
+		} else if n > m {
+			greater = true
+		}
+	}
+	for node, m := range w {
+		if _, ok := v[node]; !ok && m > 0 {
+			less = true
+		}
+	}
+	switch {
+	case less && greater:
+		return Concurrent
+	case less:
+		return Before
+	case greater:
+		return After
+	}
+	return Equal
+}
+
+// HappenedBefore reports whether v happened before w.
+func (v Vector) HappenedBefore(w Vector) bool {
+	return v.Compare(w) == Before
+}
+
+// ConcurrentWith reports whether neither of v and w happened before the
+// other, and they aren't Equal.
+func (v Vector) ConcurrentWith(w Vector) bool {
+	return v.Compare(w) == Concurrent
+}
@@ -0,0 +1,60 @@
+package logical
+
+import "testing"
+
+func TestVector(t *testing.T) {
+	var a, b Vector
+	a = a.Tick("a")
+	sent := a.Copy()
+	a = a.Tick("a")
+	b = b.Tick("b")
+	if got := a.Compare(b); got != Concurrent {
+		t.Errorf("a.Compare(b) before any messages = %v, want Concurrent", got)
+	}
+	if !a.ConcurrentWith(b) || !b.ConcurrentWith(a) {
+		t.Errorf("a and b aren't ConcurrentWith each other")
+	}
+
+	b = b.Merge(sent).Tick("b")
+	if got, want := b, (Vector{"a": 1, "b": 2}); got.Compare(want) != Equal {
+		t.Errorf("b after receiving = %v, want %v", got, want)
+	}
+	if !sent.HappenedBefore(b) {
+		t.Errorf("send %v didn't happen before receipt %v", sent, b)
+	}
+	if got := b.Compare(sent); got != After {
+		t.Errorf("b.Compare(sent) = %v, want After", got)
+	}
+	if got := a.Compare(b); got != Concurrent {
+		t.Errorf("a.Compare(b) after a's later event = %v, want Concurrent", got)
+	}
+
+	if got := sent["a"]; got != 1 {
+		t.Errorf("Copy was changed by later Ticks: %v", sent)
+	}
+}
+
+func TestVectorMissingNodes(t *testing.T) {
+	var zero Vector
+	if got := zero.Compare(Vector{"a": 0}); got != Equal {
+		t.Errorf("nil.Compare({a: 0}) = %v, want Equal", got)
+	}
+	if got := zero.Compare(Vector{"a": 1}); got != Before {
+		t.Errorf("nil.Compare({a: 1}) = %v, want Before", got)
+	}
+	if got := (Vector{"a": 1}).Compare(zero); got != After {
+		t.Errorf("{a: 1}.Compare(nil) = %v, want After", got)
+	}
+	if got := zero.Merge(nil); got != nil {
+		t.Errorf("nil.Merge(nil) = %v, want nil", got)
+	}
+	if got := zero.Merge(Vector{"a": 2}); got["a"] != 2 {
+		t.Errorf("nil.Merge({a: 2}) = %v", got)
+	}
+	if got := zero.Copy(); got != nil {
+		t.Errorf("nil.Copy() = %v, want nil", got)
+	}
+	if got := Ordering(9).String(); got != "Ordering(?)" {
+		t.Errorf("String of an unknown Ordering = %q", got)
+	}
+}
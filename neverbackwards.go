@@ -0,0 +1,65 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// NeverBackwards returns a Clock whose Now never returns a time with a
+// wall clock reading earlier than one it has returned before, for code
+// such as token issuance that relies on timestamps being ordered. When
+// base's wall clock goes backwards, such as when NTP steps the system
+// clock or an operator sets it, Now returns its latest time again until
+// base catches up, and calls report, if it isn't nil, with how far
+// behind that time base is. NowMonotonic and the Clock's Timers,
+// Tickers and sleepers are base's.
+func NeverBackwards(base Clock, report func(behind time.Duration)) Clock {
+	return &neverBackwards{Clock: base, report: report}
+}
+
+type neverBackwards struct {
+	Clock
+	report func(behind time.Duration)
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (n *neverBackwards) Now() time.Time {
+	t := n.Clock.Now()
+	n.mu.Lock()
+	// Compare wall clock readings, since that's what leaves the
+	// process, even though the monotonic ones never go backwards.
+	behind := n.last.Round(0).Sub(t.Round(0))
+	if behind <= 0 {
+		n.last = t
+		n.mu.Unlock()
+		return t
+	}
+	t = n.last
+	n.mu.Unlock()
+	if n.report != nil {
+		n.report(behind)
+	}
+	return t
+}
+
+func (n *neverBackwards) NowUnix() int64 {
+	return n.Now().Unix()
+}
+
+func (n *neverBackwards) NowUnixMilli() int64 {
+	return n.Now().UnixMilli()
+}
+
+func (n *neverBackwards) NowUnixNano() int64 {
+	return n.Now().UnixNano()
+}
+
+func (n *neverBackwards) Since(t time.Time) time.Duration {
+	return n.Now().Sub(t)
+}
+
+func (n *neverBackwards) Until(t time.Time) time.Duration {
+	return t.Sub(n.Now())
+}
@@ -0,0 +1,37 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNeverBackwards(t *testing.T) {
+	fc := NewFake()
+	var reports []time.Duration
+	clk := NeverBackwards(fc, func(behind time.Duration) {
+		reports = append(reports, behind)
+	})
+	start := clk.Now()
+	fc.Add(time.Minute)
+	if got, want := clk.Now(), start.Add(time.Minute); !got.Equal(want) {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+
+	fc.Set(start.Add(20 * time.Second))
+	if got, want := clk.Now(), start.Add(time.Minute); !got.Equal(want) {
+		t.Errorf("Now() after the base clock went back = %v, want %v", got, want)
+	}
+	fc.Add(30 * time.Second)
+	if got, want := clk.Since(start), time.Minute; got != want {
+		t.Errorf("Since(start) while the base clock catches up = %v, want %v", got, want)
+	}
+	fc.Add(20 * time.Second)
+	if got, want := clk.Now(), start.Add(70*time.Second); !got.Equal(want) {
+		t.Errorf("Now() once the base clock caught up = %v, want %v", got, want)
+	}
+
+	want := []time.Duration{40 * time.Second, 10 * time.Second}
+	if len(reports) != len(want) || reports[0] != want[0] || reports[1] != want[1] {
+		t.Errorf("got reports %v, want %v", reports, want)
+	}
+}
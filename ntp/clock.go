@@ -0,0 +1,278 @@
+package ntp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// ErrUnsynchronized is returned by Clock.ReadTime when the Clock hasn't
+// synced with a server recently enough to trust its time.
+var ErrUnsynchronized = errors.New("ntp: clock is unsynchronized")
+
+// Option configures New and NewDisciplined.
+type Option func(*options)
+
+type options struct {
+	interval time.Duration
+	timeout  time.Duration
+	maxAge   time.Duration
+
+	// Only used by Disciplined.
+	poll     PollPolicy
+	slewRate float64
+	onAdjust []func(Adjustment)
+}
+
+// WithInterval sets how long Run waits between syncs. The default is 64
+// seconds. For a Disciplined clock, it is the same as
+// WithPollPolicy(FixedPoll(d)).
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// WithTimeout sets how long Sync waits for each server to respond. The
+// default is 5 seconds. Since it bounds reads from the network, it is
+// measured on the system clock, even when the base clock is a
+// clock.FakeClock.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithMaxAge sets how long after its last successful sync a Clock's
+// ReadTime stops trusting it. The default is four times its interval.
+func WithMaxAge(d time.Duration) Option {
+	return func(o *options) {
+		o.maxAge = d
+	}
+}
+
+// Stats describes a Clock's syncs.
+type Stats struct {
+	// Server is the server the Clock's offset was last measured from,
+	// and Offset and RTT are that measurement's.
+	Server string
+	Offset time.Duration
+	RTT    time.Duration
+
+	// LastSync is the base clock's time at the last successful sync, or
+	// the zero time if there hasn't been one.
+	LastSync time.Time
+
+	// Err is the error the latest sync failed with, or nil if it
+	// succeeded.
+	Err error
+}
+
+// Clock is a clock.Clock whose time is its base clock's, corrected by
+// the offset to NTP servers it last measured with Sync. Until its first
+// successful Sync, its time is its base clock's. It is a
+// clock.TimeSource, so that clock.Fallback can fall back from it to
+// another clock when it is unsynchronized.
+//
+// NowMonotonic, and the Clock's durations, Timers, Tickers and sleepers,
+// are its base clock's, and the times its Timers and Tickers send are
+// the base clock's times. SleepUntil, AfterAt and NewTimerAt convert
+// the times they're given to the base clock's with the Clock's offset
+// when they're called.
+type Clock struct {
+	base    clock.Clock
+	servers []string
+	opts    options
+
+	mu     sync.Mutex
+	offset time.Duration
+	stats  Stats
+}
+
+// New returns a Clock whose time is base's corrected by its offset to
+// servers, each a host with an optional port. It makes no requests
+// until Sync or Run is called.
+func New(base clock.Clock, servers []string, opts ...Option) *Clock {
+	o := options{interval: 64 * time.Second, timeout: 5 * time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxAge == 0 {
+		o.maxAge = 4 * o.interval
+	}
+	return &Clock{base: base, servers: servers, opts: o}
+}
+
+// Sync queries each of the Clock's servers at once and, if any respond,
+// sets the Clock's offset to the one measured over the shortest round
+// trip, since it has the smallest possible error. It returns an error
+// joining those of every server if none respond.
+func (c *Clock) Sync(ctx context.Context) error {
+	server, resp, err := queryBest(ctx, c.base, c.servers, c.opts.timeout)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.stats.Err = err
+		return err
+	}
+	c.offset = resp.Offset
+	c.stats = Stats{
+		Server:   server,
+		Offset:   resp.Offset,
+		RTT:      resp.RTT,
+		LastSync: c.base.Now(),
+	}
+	return nil
+}
+
+// queryBest queries each of servers at once on clk, giving each timeout
+// to respond, and returns the response with the shortest round trip,
+// along with the server that sent it, or an error joining those of
+// every server if none respond.
+func queryBest(ctx context.Context, clk clock.Clock, servers []string, timeout time.Duration) (string, Response, error) {
+	type result struct {
+		server string
+		resp   Response
+		err    error
+	}
+	results := make(chan result, len(servers))
+	for _, server := range servers {
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			resp, err := Query(ctx, clk, server)
+			results <- result{server, resp, err}
+		}()
+	}
+	var best *result
+	var errs []error
+	for range servers {
+		r := <-results
+		if r.err != nil {
+			errs = append(errs, r.err)
+		} else if best == nil || r.resp.RTT < best.resp.RTT {
+			best = &r
+		}
+	}
+	if best == nil {
+		if len(errs) == 0 {
+			return "", Response{}, errors.New("ntp: no servers to sync with")
+		}
+		return "", Response{}, errors.Join(errs...)
+	}
+	return best.server, best.resp, nil
+}
+
+// Run calls Sync, and then again every interval on the base clock, until
+// ctx is done, when it returns ctx's error. A failed Sync leaves the
+// Clock's offset as it was, and is reported by Stats and, once the last
+// successful one is too old, by ReadTime.
+func (c *Clock) Run(ctx context.Context) error {
+	for {
+		c.Sync(ctx)
+		t := c.base.NewTimer(c.opts.interval)
+		select {
+		case <-t.C():
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Stats returns the Clock's Stats.
+func (c *Clock) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Offset returns how far ahead of the base clock the Clock's time is.
+func (c *Clock) Offset() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.offset
+}
+
+// ReadTime returns the Clock's time, or ErrUnsynchronized if it hasn't
+// synced successfully within its max age.
+func (c *Clock) ReadTime() (time.Time, error) {
+	c.mu.Lock()
+	last := c.stats.LastSync
+	c.mu.Unlock()
+	if last.IsZero() || c.base.Since(last) >= c.opts.maxAge {
+		return time.Time{}, ErrUnsynchronized
+	}
+	return c.Now(), nil
+}
+
+func (c *Clock) Now() time.Time {
+	return c.base.Now().Add(c.Offset())
+}
+
+func (c *Clock) NowUnix() int64 {
+	return c.Now().Unix()
+}
+
+func (c *Clock) NowUnixMilli() int64 {
+	return c.Now().UnixMilli()
+}
+
+func (c *Clock) NowUnixNano() int64 {
+	return c.Now().UnixNano()
+}
+
+func (c *Clock) NowMonotonic() time.Duration {
+	return c.base.NowMonotonic()
+}
+
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *Clock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+func (c *Clock) Sleep(d time.Duration) {
+	c.base.Sleep(d)
+}
+
+func (c *Clock) SleepUntil(t time.Time) {
+	c.base.SleepUntil(t.Add(-c.Offset()))
+}
+
+func (c *Clock) SleepContext(ctx context.Context, d time.Duration) error {
+	return c.base.SleepContext(ctx, d)
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.base.After(d)
+}
+
+func (c *Clock) AfterAt(t time.Time) <-chan time.Time {
+	return c.base.AfterAt(t.Add(-c.Offset()))
+}
+
+func (c *Clock) NewTimer(d time.Duration) clock.Timer {
+	return c.base.NewTimer(d)
+}
+
+func (c *Clock) NewTimerAt(t time.Time) clock.Timer {
+	return c.base.NewTimerAt(t.Add(-c.Offset()))
+}
+
+func (c *Clock) NewTicker(d time.Duration) clock.Ticker {
+	return c.base.NewTicker(d)
+}
+
+func (c *Clock) Tick(d time.Duration) <-chan time.Time {
+	return c.base.Tick(d)
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	return c.base.AfterFunc(d, f)
+}
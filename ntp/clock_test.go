@@ -0,0 +1,104 @@
+package ntp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+func TestClockSync(t *testing.T) {
+	fc := clock.NewFake(clock.WithStart(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	var mu sync.Mutex
+	offset := time.Minute
+	serverTime := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return fc.Now().Add(offset)
+	}
+	s := newServer(t, at(serverTime))
+	// A server that never answers mustn't hold up the sync.
+	dead := newServer(t, silent)
+
+	c := New(fc, []string{s.addr(), dead.addr()}, WithInterval(time.Minute), WithTimeout(100*time.Millisecond), WithMaxAge(3*time.Minute))
+	if _, err := c.ReadTime(); err != ErrUnsynchronized {
+		t.Errorf("ReadTime() before syncing returned error %v, want ErrUnsynchronized", err)
+	}
+	if got := c.Now(); !got.Equal(fc.Now()) {
+		t.Errorf("Now() before syncing = %v, want the base clock's %v", got, fc.Now())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+	fc.BlockUntil(1)
+	if got := c.Since(fc.Now()); got.Round(time.Millisecond) != time.Minute {
+		t.Errorf("Since(base time) = %v, want 1m", got)
+	}
+	st := c.Stats()
+	if st.Server != s.addr() || st.Err != nil || !st.LastSync.Equal(fc.Now()) {
+		t.Errorf("Stats() = %+v, want a sync with %s just now", st, s.addr())
+	}
+	if got, err := c.ReadTime(); err != nil || !got.Equal(c.Now()) {
+		t.Errorf("ReadTime() = %v, %v, want %v", got, err, c.Now())
+	}
+
+	mu.Lock()
+	offset = -time.Second
+	mu.Unlock()
+	fc.Add(time.Minute)
+	fc.BlockUntil(1)
+	if got := c.Offset().Round(time.Millisecond); got != -time.Second {
+		t.Errorf("Offset() after the next sync = %v, want -1s", got)
+	}
+	at := c.Now().Add(time.Second)
+	tm := c.NewTimerAt(at)
+	fc.Add(time.Second)
+	<-tm.C()
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v, want context.Canceled", err)
+	}
+}
+
+func TestClockSyncFails(t *testing.T) {
+	fc := clock.NewFake()
+	s := newServer(t, at(fc.Now))
+	c := New(fc, []string{s.addr()}, WithInterval(time.Minute))
+	if err := c.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	s.conn.Close()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	c.servers = []string{conn.LocalAddr().String()}
+	c.opts.timeout = 50 * time.Millisecond
+	if err := c.Sync(context.Background()); err == nil {
+		t.Fatal("Sync with no servers answering succeeded")
+	}
+	st := c.Stats()
+	if st.Err == nil || st.LastSync.IsZero() {
+		t.Errorf("Stats() after a failed sync = %+v, want an error and the earlier sync", st)
+	}
+	if _, err := c.ReadTime(); err != nil {
+		t.Errorf("ReadTime() soon after a failed sync returned %v", err)
+	}
+	fc.Add(4 * time.Minute)
+	if _, err := c.ReadTime(); !errors.Is(err, ErrUnsynchronized) {
+		t.Errorf("ReadTime() past the max age returned %v, want ErrUnsynchronized", err)
+	}
+
+	fb := clock.Fallback(c, fc)
+	if _, primary, _ := fb.NowFrom(); primary {
+		t.Errorf("Fallback used an unsynchronized Clock")
+	}
+}
@@ -0,0 +1,402 @@
+package ntp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+const (
+	// defaultSlewRate is the rate, as a fraction, at which a Disciplined
+	// clock slews away an offset by default. It is ntpd's.
+	defaultSlewRate = 500e-6
+
+	// maxFrequency is the largest drift, as a fraction, that a
+	// Disciplined clock corrects for. Oscillators drifting further than
+	// this are broken rather than inaccurate.
+	maxFrequency = 500e-6
+
+	// frequencyGain is the fraction of a newly measured frequency error
+	// that a Disciplined clock corrects at each adjustment, so that one
+	// noisy measurement can't throw its frequency off.
+	frequencyGain = 0.5
+)
+
+// WithPollPolicy sets the PollPolicy a Disciplined clock's Run uses to
+// pick how long to wait between syncs.
+func WithPollPolicy(p PollPolicy) Option {
+	return func(o *options) {
+		o.poll = p
+	}
+}
+
+// WithSlewRate sets how fast a Disciplined clock slews away an offset,
+// in parts per million of its base clock's rate. The default is 500,
+// which takes about half an hour to slew away a second. It panics if
+// ppm isn't positive or is 1e6 or more, since the clock would stop or
+// run backwards while slewing.
+func WithSlewRate(ppm float64) Option {
+	if ppm <= 0 || ppm >= 1e6 {
+		panic("ntp: invalid slew rate for WithSlewRate")
+	}
+	return func(o *options) {
+		o.slewRate = ppm / 1e6
+	}
+}
+
+// WithOnAdjust makes a Disciplined clock call hook after each of its
+// syncs, with the Adjustment it made, such as for logging or exporting
+// metrics. WithOnAdjust may be given more than once to add several
+// hooks. The hooks are called without the clock's lock held, so they
+// may use the clock.
+func WithOnAdjust(hook func(Adjustment)) Option {
+	return func(o *options) {
+		o.onAdjust = append(o.onAdjust, hook)
+	}
+}
+
+// Adjustment describes a Disciplined clock's response to a sync.
+type Adjustment struct {
+	// Time is the Disciplined clock's time when it adjusted.
+	Time time.Time
+
+	// Server is the server the offset was measured from, and Offset and
+	// RTT are that measurement's. Offset is how far ahead of the
+	// Disciplined clock the server is, which the clock now slews away.
+	// Server is empty for offsets given to Adjust.
+	Server string
+	Offset time.Duration
+	RTT    time.Duration
+
+	// Frequency is the clock's new estimate of how fast its base clock
+	// drifts, in parts per million: positive if the base clock is slow,
+	// and negative if it is fast. The clock runs that much faster than
+	// its base clock to correct for it.
+	Frequency float64
+
+	// Err is the error the sync failed with, if it did. The clock goes
+	// on slewing and correcting for drift as it was, and nothing else
+	// in the Adjustment but Time is set.
+	Err error
+}
+
+// PollPolicy picks how long a Disciplined clock's Run waits after a
+// sync, given the Adjustment it made, before syncing again.
+type PollPolicy func(a Adjustment) time.Duration
+
+// FixedPoll returns a PollPolicy that always waits d.
+func FixedPoll(d time.Duration) PollPolicy {
+	return func(Adjustment) time.Duration {
+		return d
+	}
+}
+
+// AdaptivePoll returns a PollPolicy that, like ntpd, polls less often
+// as the clock settles: after each sync measuring an offset no bigger
+// than tolerance, it waits twice as long as it last did, starting from
+// minWait and up to maxWait, and after any other sync, including failed
+// ones, it goes back to waiting minWait. It
+// keeps state, so each Disciplined clock needs its own.
+func AdaptivePoll(minWait, maxWait, tolerance time.Duration) PollPolicy {
+	var mu sync.Mutex
+	d := minWait
+	return func(a Adjustment) time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		if a.Err != nil || a.Offset.Abs() > tolerance {
+			d = minWait
+			return d
+		}
+		d = min(2*d, maxWait)
+		return d
+	}
+}
+
+// Disciplined is a clock.Clock kept in sync with NTP servers, like
+// Clock, but that never steps its time. Instead, it slews away each
+// offset it measures by running slightly faster or slower than its base
+// clock until it has made it up, and corrects for its base clock's
+// drift, which it estimates from how the offsets it measures change
+// over time. Its time never goes backwards, and so it suits
+// long-running processes that can't tolerate steps, at the cost of
+// taking a while to correct large errors.
+//
+// Until its first sync, a Disciplined clock's time is its base clock's.
+// NowMonotonic, and the clock's durations, Timers, Tickers and sleepers,
+// are its base clock's, which, at the rates it slews and corrects
+// drift at, are within a thousandth of its own. SleepUntil, AfterAt and
+// NewTimerAt wait for base clock's duration until the clock's time
+// would reach theirs at the rate it is running when they're called.
+type Disciplined struct {
+	base    clock.Clock
+	servers []string
+	opts    options
+
+	mu sync.Mutex
+	// The clock's time was t when its base clock's was baseT, and from
+	// then it runs 1+freq times as fast as its base clock, plus slewRate
+	// faster, or slower if pending is negative, until pending is made
+	// up.
+	t, baseT time.Time
+	freq     float64
+	pending  time.Duration
+	// lastAdjust is the base clock's time at the last adjustment, or
+	// the zero time if there hasn't been one.
+	lastAdjust time.Time
+	stats      Stats
+}
+
+// NewDisciplined returns a Disciplined clock starting at base's time,
+// that syncs with servers, each a host with an optional port. It makes
+// no requests until Sync or Run is called.
+func NewDisciplined(base clock.Clock, servers []string, opts ...Option) *Disciplined {
+	o := options{interval: 64 * time.Second, timeout: 5 * time.Second, slewRate: defaultSlewRate}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxAge == 0 {
+		o.maxAge = 4 * o.interval
+	}
+	if o.poll == nil {
+		o.poll = FixedPoll(o.interval)
+	}
+	now := base.Now()
+	return &Disciplined{base: base, servers: servers, opts: o, t: now, baseT: now}
+}
+
+// Sync measures the clock's offset to its servers, as Clock's Sync
+// does, and Adjusts for it.
+func (d *Disciplined) Sync(ctx context.Context) error {
+	_, err := d.sync(ctx)
+	return err
+}
+
+func (d *Disciplined) sync(ctx context.Context) (Adjustment, error) {
+	server, resp, err := queryBest(ctx, d, d.servers, d.opts.timeout)
+	if err != nil {
+		d.mu.Lock()
+		d.stats.Err = err
+		a := Adjustment{Time: d.now(d.base.Now()), Err: err}
+		d.mu.Unlock()
+		d.report(a)
+		return a, err
+	}
+	return d.adjust(server, resp.Offset, resp.RTT), nil
+}
+
+// Adjust makes the clock slew away offset, how far ahead of it the true
+// time is, measured some way other than by syncing with its servers,
+// and updates its estimate of its base clock's drift to match. It
+// returns the Adjustment made.
+func (d *Disciplined) Adjust(offset time.Duration) Adjustment {
+	return d.adjust("", offset, 0)
+}
+
+func (d *Disciplined) adjust(server string, offset, rtt time.Duration) Adjustment {
+	d.mu.Lock()
+	b := d.base.Now()
+	d.reanchor(b)
+	if !d.lastAdjust.IsZero() {
+		// What slewing hadn't yet made up of the last offset should
+		// still be there. Anything more built up because the base clock
+		// drifted more than was corrected for.
+		if elapsed := b.Sub(d.lastAdjust); elapsed > 0 {
+			d.freq += frequencyGain * float64(offset-d.pending) / float64(elapsed)
+			d.freq = max(-maxFrequency, min(d.freq, maxFrequency))
+		}
+	}
+	d.pending = offset
+	d.lastAdjust = b
+	d.stats = Stats{Server: server, Offset: offset, RTT: rtt, LastSync: b}
+	a := Adjustment{Time: d.t, Server: server, Offset: offset, RTT: rtt, Frequency: d.freq * 1e6}
+	d.mu.Unlock()
+	d.report(a)
+	return a
+}
+
+func (d *Disciplined) report(a Adjustment) {
+	for _, hook := range d.opts.onAdjust {
+		hook(a)
+	}
+}
+
+// Run calls Sync, and then again after however long the clock's
+// PollPolicy picks on the base clock, until ctx is done, when it returns
+// ctx's error.
+func (d *Disciplined) Run(ctx context.Context) error {
+	for {
+		a, _ := d.sync(ctx)
+		t := d.base.NewTimer(d.opts.poll(a))
+		select {
+		case <-t.C():
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Stats returns the clock's Stats. Its Offset is the last one measured,
+// not what is left of it to slew away.
+func (d *Disciplined) Stats() Stats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
+
+// Pending returns how much of the offsets the clock has measured it has
+// yet to slew away.
+func (d *Disciplined) Pending() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reanchor(d.base.Now())
+	return d.pending
+}
+
+// Frequency returns the clock's estimate of its base clock's drift, in
+// parts per million, as in Adjustment.
+func (d *Disciplined) Frequency() float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.freq * 1e6
+}
+
+// ReadTime returns the clock's time, or ErrUnsynchronized if it hasn't
+// synced successfully within its max age.
+func (d *Disciplined) ReadTime() (time.Time, error) {
+	d.mu.Lock()
+	last := d.lastAdjust
+	d.mu.Unlock()
+	if last.IsZero() || d.base.Since(last) >= d.opts.maxAge {
+		return time.Time{}, ErrUnsynchronized
+	}
+	return d.Now(), nil
+}
+
+// now returns the clock's time when its base clock's is b. It must be
+// called with d's lock held.
+func (d *Disciplined) now(b time.Time) time.Time {
+	e := b.Sub(d.baseT)
+	return d.t.Add(e + time.Duration(float64(e)*d.freq) + d.slewed(e))
+}
+
+// slewed returns how much of pending the clock slews away in e of its
+// base clock's time since it was anchored. It must be called with d's
+// lock held.
+func (d *Disciplined) slewed(e time.Duration) time.Duration {
+	s := time.Duration(float64(e) * d.opts.slewRate)
+	if s >= d.pending.Abs() {
+		return d.pending
+	}
+	if d.pending < 0 {
+		return -s
+	}
+	return s
+}
+
+// reanchor moves the clock's anchor to when its base clock's time is b,
+// taking what has been slewed by then off pending. It must be called
+// with d's lock held.
+func (d *Disciplined) reanchor(b time.Time) {
+	e := b.Sub(d.baseT)
+	if e <= 0 {
+		return
+	}
+	d.t = d.now(b)
+	d.pending -= d.slewed(e)
+	d.baseT = b
+}
+
+// rate returns how many times as fast as its base clock the clock is
+// running. It must be called with d's lock held.
+func (d *Disciplined) rate() float64 {
+	r := 1 + d.freq
+	switch {
+	case d.pending > 0:
+		r += d.opts.slewRate
+	case d.pending < 0:
+		r -= d.opts.slewRate
+	}
+	return r
+}
+
+func (d *Disciplined) Now() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.now(d.base.Now())
+}
+
+func (d *Disciplined) NowUnix() int64 {
+	return d.Now().Unix()
+}
+
+func (d *Disciplined) NowUnixMilli() int64 {
+	return d.Now().UnixMilli()
+}
+
+func (d *Disciplined) NowUnixNano() int64 {
+	return d.Now().UnixNano()
+}
+
+func (d *Disciplined) NowMonotonic() time.Duration {
+	return d.base.NowMonotonic()
+}
+
+func (d *Disciplined) Since(t time.Time) time.Duration {
+	return d.Now().Sub(t)
+}
+
+func (d *Disciplined) Until(t time.Time) time.Duration {
+	return t.Sub(d.Now())
+}
+
+// baseUntil returns how long on the base clock it will take for the
+// clock's time to reach t, at the rate it is running now.
+func (d *Disciplined) baseUntil(t time.Time) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	until := t.Sub(d.now(d.base.Now()))
+	return time.Duration(float64(until) / d.rate())
+}
+
+func (d *Disciplined) Sleep(dur time.Duration) {
+	d.base.Sleep(dur)
+}
+
+func (d *Disciplined) SleepUntil(t time.Time) {
+	d.base.Sleep(d.baseUntil(t))
+}
+
+func (d *Disciplined) SleepContext(ctx context.Context, dur time.Duration) error {
+	return d.base.SleepContext(ctx, dur)
+}
+
+func (d *Disciplined) After(dur time.Duration) <-chan time.Time {
+	return d.base.After(dur)
+}
+
+func (d *Disciplined) AfterAt(t time.Time) <-chan time.Time {
+	return d.base.After(d.baseUntil(t))
+}
+
+func (d *Disciplined) NewTimer(dur time.Duration) clock.Timer {
+	return d.base.NewTimer(dur)
+}
+
+func (d *Disciplined) NewTimerAt(t time.Time) clock.Timer {
+	return d.base.NewTimer(d.baseUntil(t))
+}
+
+func (d *Disciplined) NewTicker(dur time.Duration) clock.Ticker {
+	return d.base.NewTicker(dur)
+}
+
+func (d *Disciplined) Tick(dur time.Duration) <-chan time.Time {
+	return d.base.Tick(dur)
+}
+
+func (d *Disciplined) AfterFunc(dur time.Duration, f func()) clock.Timer {
+	return d.base.AfterFunc(dur, f)
+}
@@ -0,0 +1,153 @@
+package ntp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+func TestDisciplinedSlews(t *testing.T) {
+	fc := clock.NewFake()
+	d := NewDisciplined(fc, nil, WithSlewRate(1000))
+	start := d.Now()
+	if !start.Equal(fc.Now()) {
+		t.Errorf("Now() before adjusting = %v, want the base clock's %v", start, fc.Now())
+	}
+
+	d.Adjust(time.Second)
+	fc.Add(500 * time.Second)
+	if got, want := d.Since(start), 500*time.Second+500*time.Millisecond; got != want {
+		t.Errorf("time passed halfway through slewing = %v, want %v", got, want)
+	}
+	if got, want := d.Pending(), 500*time.Millisecond; got != want {
+		t.Errorf("Pending() halfway through slewing = %v, want %v", got, want)
+	}
+	fc.Add(1000 * time.Second)
+	if got, want := d.Since(start), 1501*time.Second; got != want {
+		t.Errorf("time passed after slewing = %v, want %v", got, want)
+	}
+	if got := d.Pending(); got != 0 {
+		t.Errorf("Pending() after slewing = %v, want 0", got)
+	}
+
+	// A negative offset slows the clock down, rather than stepping it
+	// back.
+	before := d.Now()
+	d.Adjust(-time.Hour)
+	fc.Add(time.Second)
+	if got := d.Since(before); got <= 0 || got >= time.Second {
+		t.Errorf("a second after a negative adjustment, %v passed, want a little under 1s", got)
+	}
+}
+
+func TestDisciplinedCorrectsDrift(t *testing.T) {
+	// The base clock loses 100µs a second against the true time, fc's.
+	fc := clock.NewFake()
+	d := NewDisciplined(clock.Drift(fc, -100), nil)
+	var a Adjustment
+	for range 30 {
+		fc.Add(1000 * time.Second)
+		a = d.Adjust(fc.Now().Sub(d.Now()))
+	}
+	if got := d.Frequency(); got < 99 || got > 101 {
+		t.Errorf("Frequency() = %v ppm, want about 100", got)
+	}
+	if a.Offset.Abs() > time.Millisecond {
+		t.Errorf("offset after settling = %v, want under a millisecond", a.Offset)
+	}
+	if a.Frequency != d.Frequency() {
+		t.Errorf("Adjustment's Frequency = %v, want %v", a.Frequency, d.Frequency())
+	}
+}
+
+func TestDisciplinedRun(t *testing.T) {
+	fc := clock.NewFake(clock.WithStart(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	s := newServer(t, at(func() time.Time { return fc.Now().Add(time.Minute) }))
+	adjustments := make(chan Adjustment, 10)
+	var polls []time.Duration
+	poll := func(a Adjustment) time.Duration {
+		polls = append(polls, time.Duration(len(polls)+1)*time.Minute)
+		return polls[len(polls)-1]
+	}
+	d := NewDisciplined(fc, []string{s.addr()}, WithPollPolicy(poll), WithOnAdjust(func(a Adjustment) {
+		adjustments <- a
+	}))
+	if _, err := d.ReadTime(); err != ErrUnsynchronized {
+		t.Errorf("ReadTime() before syncing returned error %v, want ErrUnsynchronized", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Run(ctx) }()
+	a := <-adjustments
+	if a.Server != s.addr() || a.Err != nil || a.Offset.Round(time.Millisecond) != time.Minute {
+		t.Errorf("first Adjustment = %+v, want a minute's offset from %s", a, s.addr())
+	}
+	fc.BlockUntil(1)
+	if _, err := d.ReadTime(); err != nil {
+		t.Errorf("ReadTime() after syncing returned %v", err)
+	}
+	if st := d.Stats(); st.Server != s.addr() || st.Offset != a.Offset {
+		t.Errorf("Stats() = %+v, want the sync's", st)
+	}
+
+	s.conn.Close()
+	d.opts.timeout = 10 * time.Millisecond
+	fc.Add(time.Minute)
+	if a := <-adjustments; a.Err == nil {
+		t.Errorf("Adjustment after the server went away = %+v, want an error", a)
+	}
+	fc.BlockUntil(1)
+	if d.Stats().Err == nil {
+		t.Errorf("Stats() after a failed sync has no error")
+	}
+	fc.Add(2 * time.Minute)
+	<-adjustments
+	fc.BlockUntil(1)
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v, want context.Canceled", err)
+	}
+	if len(polls) != 3 {
+		t.Errorf("PollPolicy called %d times, want 3", len(polls))
+	}
+}
+
+func TestDisciplinedAt(t *testing.T) {
+	fc := clock.NewFake()
+	d := NewDisciplined(fc, nil, WithSlewRate(1e5))
+	d.Adjust(time.Hour)
+	tm := d.NewTimerAt(d.Now().Add(110 * time.Second))
+	fc.Add(99 * time.Second)
+	select {
+	case <-tm.C():
+		t.Fatal("NewTimerAt's Timer fired early")
+	default:
+	}
+	fc.Add(time.Second)
+	<-tm.C()
+}
+
+func TestAdaptivePoll(t *testing.T) {
+	p := AdaptivePoll(time.Minute, 4*time.Minute, time.Millisecond)
+	for i, tc := range []struct {
+		a    Adjustment
+		want time.Duration
+	}{
+		{Adjustment{Offset: time.Second}, time.Minute},
+		{Adjustment{Offset: time.Microsecond}, 2 * time.Minute},
+		{Adjustment{Offset: -time.Microsecond}, 4 * time.Minute},
+		{Adjustment{}, 4 * time.Minute},
+		{Adjustment{Err: ErrUnsynchronized}, time.Minute},
+	} {
+		if got := p(tc.a); got != tc.want {
+			t.Errorf("poll %d: waited %v, want %v", i, got, tc.want)
+		}
+	}
+	if got := FixedPoll(time.Hour)(Adjustment{}); got != time.Hour {
+		t.Errorf("FixedPoll(1h) waited %v", got)
+	}
+}
@@ -0,0 +1,177 @@
+// Package ntp provides a clock.Clock kept in sync with NTP servers, for
+// programs, such as those in containers, that can't trust the system
+// clock they run on.
+//
+// Query makes a single SNTP (RFC 4330) request to a server. A Clock
+// makes them periodically, to a set of servers, and serves its base
+// clock's time corrected by the latest offset they measured, stepping
+// to each new one. A Disciplined clock slews to them instead, and
+// corrects for its base clock's drift, so that its time never jumps.
+package ntp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+const (
+	packetLen = 48
+
+	// ntpEpochOffset is the number of seconds from the NTP epoch, the
+	// start of 1900, to the Unix epoch.
+	ntpEpochOffset = 2208988800
+
+	modeClient = 3
+	modeServer = 4
+	version    = 4
+
+	leapUnsynchronized = 3
+	maxStratum         = 15
+)
+
+// Response is what a server replied to Query with, and what was
+// measured of it.
+type Response struct {
+	// Time is the server's time when it sent the response.
+	Time time.Time
+
+	// Offset is how far ahead of the clock Query was given the server's
+	// clock is, estimated assuming the request and response took as long
+	// as each other to arrive. Adding it to the clock's time gives the
+	// server's.
+	Offset time.Duration
+
+	// RTT is how long the request and response took to arrive, not
+	// counting the time the server spent between receiving one and
+	// sending the other. Offset is off by at most half of it.
+	RTT time.Duration
+
+	// Stratum is how many servers away from a reference clock, such as
+	// a GPS receiver, the server is. Stratum 1 servers have one.
+	Stratum uint8
+
+	// RootDelay and RootDispersion are the server's own estimates of
+	// the round trip time to, and its error relative to, its reference
+	// clock.
+	RootDelay      time.Duration
+	RootDispersion time.Duration
+}
+
+// Query sends an SNTP request to server, a host with an optional port
+// that defaults to 123, and waits for its response, timing both on clk.
+// The request's deadline is ctx's.
+//
+// Query returns an error if the server doesn't respond, or its reply
+// isn't a response to the request, or it says it isn't synchronized,
+// including when it sends a kiss-of-death asking clients to back off.
+func Query(ctx context.Context, clk clock.Clock, server string) (Response, error) {
+	addr := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		addr = net.JoinHostPort(server, "123")
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return Response{}, fmt.Errorf("ntp: querying %s: %w", server, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	// The request's transmit timestamp is random, rather than clk's
+	// time, so that it doesn't reveal the time and a reply forged
+	// without seeing it can't echo it back.
+	req := make([]byte, packetLen)
+	req[0] = version<<3 | modeClient
+	rand.Read(req[40:48])
+
+	t1 := clk.Now()
+	if _, err := conn.Write(req); err != nil {
+		return Response{}, fmt.Errorf("ntp: querying %s: %w", server, err)
+	}
+	resp := make([]byte, 2*packetLen)
+	for {
+		n, err := conn.Read(resp)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return Response{}, fmt.Errorf("ntp: querying %s: %w", server, err)
+		}
+		t4 := clk.Now()
+		if n < packetLen || string(resp[24:32]) != string(req[40:48]) {
+			// Not a reply to this request, such as a late one to an
+			// earlier request from the same port.
+			continue
+		}
+		r, err := parse(resp[:n], t1, t4)
+		if err != nil {
+			return Response{}, fmt.Errorf("ntp: querying %s: %w", server, err)
+		}
+		return r, nil
+	}
+}
+
+// parse parses the response packet b to a request sent at t1 and
+// received at t4.
+func parse(b []byte, t1, t4 time.Time) (Response, error) {
+	if mode := b[0] & 0x7; mode != modeServer {
+		return Response{}, fmt.Errorf("reply has mode %d, not a server's", mode)
+	}
+	stratum := b[1]
+	if stratum == 0 {
+		return Response{}, fmt.Errorf("server sent kiss code %q", b[12:16])
+	}
+	if stratum > maxStratum {
+		return Response{}, fmt.Errorf("server has invalid stratum %d", stratum)
+	}
+	if leap := b[0] >> 6; leap == leapUnsynchronized {
+		return Response{}, errors.New("server is unsynchronized")
+	}
+	if binary.BigEndian.Uint64(b[40:48]) == 0 {
+		return Response{}, errors.New("reply has no transmit timestamp")
+	}
+	t2 := ntpTime(binary.BigEndian.Uint64(b[32:40]))
+	t3 := ntpTime(binary.BigEndian.Uint64(b[40:48]))
+	rtt := t4.Sub(t1) - t3.Sub(t2)
+	if rtt < 0 {
+		rtt = 0
+	}
+	return Response{
+		Time:           t3,
+		Offset:         (t2.Sub(t1) + t3.Sub(t4)) / 2,
+		RTT:            rtt,
+		Stratum:        stratum,
+		RootDelay:      ntpShort(binary.BigEndian.Uint32(b[4:8])),
+		RootDispersion: ntpShort(binary.BigEndian.Uint32(b[8:12])),
+	}, nil
+}
+
+// ntpTime converts an NTP timestamp, 32.32 fixed point seconds since
+// the NTP epoch, to a time. Timestamps with the top bit clear are taken
+// to be in the era starting in 2036, when the seconds wrap around.
+func ntpTime(ts uint64) time.Time {
+	secs := int64(ts >> 32)
+	if secs&0x80000000 == 0 {
+		secs += 1 << 32
+	}
+	frac := int64(ts & 0xffffffff)
+	return time.Unix(secs-ntpEpochOffset, frac*1e9>>32).UTC()
+}
+
+// ntpShort converts a 16.16 fixed point NTP duration to a Duration.
+func ntpShort(v uint32) time.Duration {
+	return time.Duration(int64(v) * int64(time.Second) >> 16)
+}
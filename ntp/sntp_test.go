@@ -0,0 +1,170 @@
+package ntp
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// testServer is an SNTP server on localhost.
+type testServer struct {
+	conn net.PacketConn
+}
+
+// newServer returns a testServer that sends reply's response to each
+// request, or nothing if it returns nil. reply is called from the
+// server's goroutine.
+func newServer(t *testing.T, reply func(req []byte) []byte) *testServer {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testServer{conn: conn}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := reply(buf[:n]); resp != nil {
+				conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+	return s
+}
+
+func (s *testServer) addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+// at returns a reply function for newServer with the server's clock
+// at now.
+func at(now func() time.Time) func(req []byte) []byte {
+	return func(req []byte) []byte {
+		return response(req, now())
+	}
+}
+
+// silent is a reply function for newServer that never replies.
+func silent([]byte) []byte {
+	return nil
+}
+
+// response returns a valid stratum 2 response to req, received and sent
+// at now.
+func response(req []byte, now time.Time) []byte {
+	resp := make([]byte, packetLen)
+	resp[0] = version<<3 | modeServer
+	resp[1] = 2
+	binary.BigEndian.PutUint32(resp[4:8], 1<<15)  // half a second
+	binary.BigEndian.PutUint32(resp[8:12], 1<<14) // a quarter second
+	copy(resp[24:32], req[40:48])
+	binary.BigEndian.PutUint64(resp[32:40], ntpTimestamp(now))
+	binary.BigEndian.PutUint64(resp[40:48], ntpTimestamp(now))
+	return resp
+}
+
+func ntpTimestamp(t time.Time) uint64 {
+	secs := uint64(t.Unix()+ntpEpochOffset) & 0xffffffff
+	frac := uint64(t.Nanosecond()) << 32 / 1e9
+	return secs<<32 | frac
+}
+
+func TestQuery(t *testing.T) {
+	fc := clock.NewFake(clock.WithStart(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	serverTime := fc.Now().Add(time.Hour + 500*time.Millisecond)
+	s := newServer(t, at(func() time.Time { return serverTime }))
+
+	r, err := Query(context.Background(), fc, s.addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.Offset, time.Hour+500*time.Millisecond; got < want-time.Microsecond || got > want+time.Microsecond {
+		t.Errorf("Offset = %v, want %v", got, want)
+	}
+	if r.RTT != 0 {
+		t.Errorf("RTT = %v on a FakeClock that didn't move, want 0", r.RTT)
+	}
+	if got := r.Time.Sub(serverTime).Abs(); got > time.Microsecond {
+		t.Errorf("Time = %v, want %v", r.Time, serverTime)
+	}
+	if r.Stratum != 2 || r.RootDelay != time.Second/2 || r.RootDispersion != time.Second/4 {
+		t.Errorf("Stratum, RootDelay, RootDispersion = %d, %v, %v, want 2, 500ms, 250ms", r.Stratum, r.RootDelay, r.RootDispersion)
+	}
+}
+
+func TestQueryRejects(t *testing.T) {
+	fc := clock.NewFake()
+	for _, tc := range []struct {
+		name  string
+		edit  func(resp []byte)
+		error string
+	}{
+		{"client mode", func(resp []byte) { resp[0] = version<<3 | modeClient }, "mode 3"},
+		{"kiss of death", func(resp []byte) { resp[1] = 0; copy(resp[12:16], "RATE") }, `kiss code "RATE"`},
+		{"bad stratum", func(resp []byte) { resp[1] = 16 }, "stratum 16"},
+		{"unsynchronized", func(resp []byte) { resp[0] |= leapUnsynchronized << 6 }, "unsynchronized"},
+		{"no transmit time", func(resp []byte) { clear(resp[40:48]) }, "no transmit timestamp"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newServer(t, func(req []byte) []byte {
+				resp := response(req, time.Now())
+				tc.edit(resp)
+				return resp
+			})
+			_, err := Query(context.Background(), fc, s.addr())
+			if err == nil || !strings.Contains(err.Error(), tc.error) {
+				t.Errorf("Query error = %v, want one containing %q", err, tc.error)
+			}
+		})
+	}
+}
+
+func TestQueryIgnoresOtherReplies(t *testing.T) {
+	fc := clock.NewFake()
+	s := newServer(t, func(req []byte) []byte {
+		resp := response(req, time.Now())
+		resp[24]++
+		return resp
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := Query(ctx, fc, s.addr()); err == nil {
+		t.Errorf("Query accepted a reply that didn't echo its transmit timestamp")
+	}
+}
+
+func TestQueryCanceled(t *testing.T) {
+	s := newServer(t, silent)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := Query(ctx, clock.NewFake(), s.addr())
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("Query error = %v, want one wrapping context.Canceled", err)
+	}
+}
+
+func TestNTPTime(t *testing.T) {
+	for _, want := range []time.Time{
+		time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2036, 2, 7, 6, 28, 15, 0, time.UTC),
+		time.Date(2036, 2, 7, 6, 28, 16, 0, time.UTC),
+		time.Date(2050, 6, 1, 0, 0, 0, 500000000, time.UTC),
+	} {
+		if got := ntpTime(ntpTimestamp(want)); got.Sub(want).Abs() > time.Nanosecond {
+			t.Errorf("ntpTime(ntpTimestamp(%v)) = %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package clock
+
+import "time"
+
+// Offset returns a Clock whose time is always base's time plus d, such
+// as for running a staging service in the future to exercise the
+// expiry of certificates or the end of billing cycles. Its NowMonotonic
+// is base's. Its Timers, Tickers, AfterFunc functions and sleepers fire
+// when base's would, and the times they send are the Offset clock's.
+//
+// If base is a FakeClock, Offset returns base.Child(d), and if base is
+// one of a FakeClock's Children, another Child further offset by d.
+func Offset(base Clock, d time.Duration) Clock {
+	switch c := base.(type) {
+	case *fake:
+		return c.Child(d)
+	case *child:
+		o := *c
+		o.tag.offset += d
+		return &o
+	}
+	return newDerived(base, d, 1)
+}
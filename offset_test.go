@@ -0,0 +1,44 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOffset(t *testing.T) {
+	fc := NewFake()
+	if _, ok := Offset(fc, time.Hour).(*child); !ok {
+		t.Errorf("Offset of a FakeClock is not one of its Children")
+	}
+	if got, want := Offset(Offset(fc, time.Hour), time.Minute).Since(fc.Now()), time.Hour+time.Minute; got != want {
+		t.Errorf("Offset of a Child is %v ahead of the FakeClock, want %v", got, want)
+	}
+
+	// A base Clock that Offset knows nothing about.
+	base := StripMonotonic(fc)
+	clk := Offset(base, 24*time.Hour)
+	start := fc.Now()
+	if got, want := clk.Now(), start.Add(24*time.Hour); !got.Equal(want) {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+	if got, want := clk.NowMonotonic(), fc.NowMonotonic(); got != want {
+		t.Errorf("NowMonotonic() = %v, want %v", got, want)
+	}
+
+	tm := clk.NewTimerAt(start.Add(24*time.Hour + time.Minute))
+	tk := clk.NewTicker(time.Second)
+	defer tk.Stop()
+	fc.Add(time.Second)
+	if got, want := <-tk.C(), start.Add(24*time.Hour+time.Second); !got.Equal(want) {
+		t.Errorf("Ticker sent %v, want %v", got, want)
+	}
+	fc.Add(time.Minute)
+	if got, want := <-tm.C(), start.Add(24*time.Hour+time.Minute); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+
+	fc.Set(start.Add(-time.Hour))
+	if got, want := clk.Now(), start.Add(23*time.Hour); !got.Equal(want) {
+		t.Errorf("Now() after the base clock was set back = %v, want %v", got, want)
+	}
+}
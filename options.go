@@ -0,0 +1,274 @@
+package clock
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Option configures a FakeClock created by NewFake or NewFakeAt.
+type Option func(*fake)
+
+// fakeConfig holds the settings made by Options.
+type fakeConfig struct {
+	missedTicks MissedTicks
+	syncFuncs   bool
+	autoAdvance bool
+	yield       bool
+	yieldSleep  time.Duration
+	rewindGuard bool
+	increment   time.Duration
+	loc         *time.Location
+	leaps       *LeapTable
+
+	watchdog       time.Duration
+	watchdogReport func(msg string)
+	stacks         bool
+
+	onNow       []func(now time.Time)
+	onAdvance   []func(from, to time.Time)
+	onTimerFire []func(info TimerInfo)
+}
+
+// WithStart sets the FakeClock's initial time to t, as NewFakeAt does.
+func WithStart(t time.Time) Option {
+	return func(f *fake) {
+		f.t = t
+	}
+}
+
+// WithLocation sets the Location of the FakeClock's initial time to
+// loc, without changing the instant it represents. Times given to Set
+// keep their own Location.
+func WithLocation(loc *time.Location) Option {
+	return func(f *fake) {
+		f.loc = loc
+	}
+}
+
+// WithLeapSeconds makes the FakeClock step for the leap seconds in
+// table as they are reached, as most systems' clocks do: moving past a
+// positive leap second, its time goes back a second, repeating the
+// second before the leap second, and moving past a negative one, it
+// skips the last second of the day. Its monotonic time, and what is
+// waiting on it, are unaffected, so that after Add(d) across a leap
+// second, NowMonotonic has moved by d and Timers of d have fired, while
+// differences between times from Now, such as Since's, are a second
+// off, as between a real clock's readings without a monotonic part.
+// Set still moves the clock to the UTC time it is given. NowTAI tells
+// the repeated second apart from the one before it, and FormatRFC3339
+// on table shows it as 23:59:60.
+func WithLeapSeconds(table *LeapTable) Option {
+	return func(f *fake) {
+		f.leaps = table
+	}
+}
+
+// randomStartMin and randomStartMax bound the times picked by
+// WithRandomStart. They span the Unix epoch and the year 2038 so that
+// tests run on both sides of them.
+var (
+	randomStartMin = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+	randomStartMax = time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// WithRandomStart sets the FakeClock's initial time to a pseudo-random
+// instant between the years 1900 and 2100, picked using seed. Starting
+// every test at the Unix epoch hides bugs around year boundaries,
+// daylight saving changes and times before 1970.
+//
+// If seed is zero, a seed is chosen from the real time. Either way,
+// the seed and the time picked are passed to logf, if it is not nil,
+// so that a failing test can be reproduced by passing the same seed.
+// A testing.T's Logf works well as a logf.
+func WithRandomStart(seed int64, logf func(format string, args ...any)) Option {
+	return func(f *fake) {
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		span := randomStartMax.Sub(randomStartMin)
+		r := rand.New(rand.NewSource(seed))
+		f.t = randomStartMin.Add(time.Duration(r.Int63n(int64(span))))
+		if logf != nil {
+			logf("clock: FakeClock starting at %v from WithRandomStart seed %d", f.t, seed)
+		}
+	}
+}
+
+// MissedTicks is how a FakeClock's Tickers behave when a single call to
+// Add or Set spans more than one of their periods.
+type MissedTicks int
+
+const (
+	// CoalesceMissedTicks drops ticks while the receiver has yet to
+	// take the previous one, so a lagging receiver gets the oldest
+	// missed tick, like a time.Ticker whose receiver has fallen
+	// behind. It is the default.
+	CoalesceMissedTicks MissedTicks = iota
+
+	// DeliverMissedTicks delivers one tick per elapsed period, each
+	// with the time of its period boundary, in order. Ticks that do
+	// not fit in the Ticker's channel are queued and sent as the
+	// receiver catches up, so Add and Set never block on them.
+	DeliverMissedTicks
+
+	// ReplaceMissedTicks replaces a tick the receiver has yet to take
+	// with each new one, so a lagging receiver gets only the latest
+	// missed tick.
+	ReplaceMissedTicks
+)
+
+// WithAutoAdvance makes the FakeClock move itself forward, as Add does,
+// whenever something waits on it with Sleep, SleepUntil, SleepContext,
+// After or AfterAt, to exactly the deadline of the wait, so that it
+// ends immediately. Everything else due by then fires, too. Timers,
+// Tickers and AfterFunc functions do not move the clock. It lets code
+// that waits run at full speed without a test having to call Add.
+func WithAutoAdvance() Option {
+	return func(f *fake) {
+		f.autoAdvance = true
+	}
+}
+
+// WithWatchdog makes the FakeClock report goroutines that have been
+// blocked in its Sleep, SleepUntil or SleepContext for d of real time
+// during which nothing called Add or Set. Such goroutines are usually
+// waiting on a test that will never move the clock, and the test would
+// otherwise hang until the test binary times out. The report names the
+// blocked calls and includes the stack of each goroutine blocked on
+// the clock at the time.
+//
+// The report is passed to report, which is called on its own
+// goroutine, so a testing.T's Error, but not Fatal or FailNow, is safe
+// to call from it. If report is nil, the watchdog panics with the
+// report instead, ending the test binary straight away.
+func WithWatchdog(d time.Duration, report func(msg string)) Option {
+	return func(f *fake) {
+		f.watchdog = d
+		f.watchdogReport = report
+	}
+}
+
+// WithYield makes the FakeClock give other goroutines a chance to run
+// after each Timer, Ticker or sleeper it fires while Add or Set is
+// moving it, so that goroutines woken by one deadline can react before
+// the next one fires. If sleep is zero, it yields with
+// runtime.Gosched. Otherwise, it sleeps for sleep of real time, which
+// is slower but more reliable, since Gosched alone does not guarantee
+// the woken goroutines run.
+func WithYield(sleep time.Duration) Option {
+	return func(f *fake) {
+		f.yield = true
+		f.yieldSleep = sleep
+	}
+}
+
+// WithRewindGuard makes Set, and Add with a negative duration, panic if
+// they would move the FakeClock backwards while anything is waiting on
+// it. Moving the clock backwards pushes every Timer, Ticker and
+// sleeper further from its deadline, which usually shows up as a
+// confusing hang rather than an error.
+func WithRewindGuard() Option {
+	return func(f *fake) {
+		f.rewindGuard = true
+	}
+}
+
+// WithAutoIncrement makes the FakeClock move itself forward by step
+// after every reading of its time, whether by Now, the NowUnix methods,
+// NowMonotonic, Since or Until, so that no two readings are the same.
+// Code that uses times as ordering or map keys needs that. The
+// increments are included in NowMonotonic but do not fire anything
+// that comes due because of them until the next call to Add or Set.
+func WithAutoIncrement(step time.Duration) Option {
+	return func(f *fake) {
+		f.increment = step
+	}
+}
+
+// WithLeakCheck registers a call to the FakeClock's AssertNoPending
+// with t.Cleanup, so that t fails if the test ends with any Timers,
+// Tickers, AfterFunc functions or sleepers still waiting on the clock.
+func WithLeakCheck(t TB) Option {
+	return func(f *fake) {
+		t.Cleanup(func() {
+			t.Helper()
+			f.AssertNoPending(t)
+		})
+	}
+}
+
+// WithSynchronousAfterFunc makes the FakeClock call functions passed to
+// AfterFunc on the goroutine that moved the clock past their deadline,
+// usually the one calling Add or Set, instead of in a new goroutine.
+// That lets the functions use testing.T's FailNow and lets their
+// panics reach the test. A function whose deadline has already passed
+// when AfterFunc is called, or when its Timer is Reset, is still called
+// in a new goroutine, as without WithSynchronousAfterFunc, since calling
+// it before AfterFunc returns would deadlock callers holding a lock the
+// function takes, such as the Clocks made by Scale and Drift.
+func WithSynchronousAfterFunc() Option {
+	return func(f *fake) {
+		f.syncFuncs = true
+	}
+}
+
+// WithMissedTicks sets how the FakeClock's Tickers handle ticks missed
+// within a single call to Add or Set.
+func WithMissedTicks(m MissedTicks) Option {
+	return func(f *fake) {
+		f.missedTicks = m
+	}
+}
+
+// WithStrictMonotonic makes every reading of the FakeClock's time
+// differ from the last. It is WithAutoIncrement(time.Nanosecond).
+func WithStrictMonotonic() Option {
+	return WithAutoIncrement(time.Nanosecond)
+}
+
+// WithOnNow makes the FakeClock call hook with the time returned by
+// every call to Now, including those made by the methods built on it,
+// such as Since and NowUnix, and by its Children. It can be used to
+// check how many times the code under test reads the clock. WithOnNow
+// may be given more than once to add several hooks.
+func WithOnNow(hook func(now time.Time)) Option {
+	return func(f *fake) {
+		f.onNow = append(f.onNow, hook)
+	}
+}
+
+// WithOnAdvance makes the FakeClock call hook with its times before and
+// after every call to Add, Set, AdvanceToNextTimer and RunUntilIdle,
+// once the call has finished moving the clock. WithOnAdvance may be
+// given more than once to add several hooks.
+func WithOnAdvance(hook func(from, to time.Time)) Option {
+	return func(f *fake) {
+		f.onAdvance = append(f.onAdvance, hook)
+	}
+}
+
+// WithCreationStacks makes the FakeClock record the stack of the code
+// that creates each of its Timers, Tickers, AfterFunc functions and
+// sleepers, for the Stack of the TimerInfos describing them and for
+// Dump. Recording a stack is slow next to creating a timer, so the
+// stacks are otherwise only recorded for clocks made with WithWatchdog.
+func WithCreationStacks() Option {
+	return func(f *fake) {
+		f.stacks = true
+	}
+}
+
+// WithOnTimerFire makes the FakeClock call hook after each of its
+// Timers, Tickers, AfterFunc functions and sleepers fires, with a
+// description of it as it was just before. The description's Deadline
+// is the clock's time as it fired. Together with WithOnAdvance, it can
+// log a timeline of a test's events for when the test fails.
+// WithOnTimerFire may be given more than once to add several hooks.
+//
+// The hooks are called without the clock's lock held, so they may use
+// the clock, but they must not move it.
+func WithOnTimerFire(hook func(info TimerInfo)) Option {
+	return func(f *fake) {
+		f.onTimerFire = append(f.onTimerFire, hook)
+	}
+}
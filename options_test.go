@@ -0,0 +1,73 @@
+package clock
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithRandomStart(t *testing.T) {
+	var logged string
+	logf := func(format string, args ...any) {
+		logged = fmt.Sprintf(format, args...)
+	}
+	a := NewFake(WithRandomStart(42, logf)).Now()
+	if !strings.Contains(logged, "seed 42") {
+		t.Errorf("seed was not logged: %q", logged)
+	}
+	if b := NewFake(WithRandomStart(42, nil)).Now(); !a.Equal(b) {
+		t.Errorf("the same seed gave different times: %v and %v", a, b)
+	}
+	if a.Before(randomStartMin) || !a.Before(randomStartMax) {
+		t.Errorf("random start %v out of range", a)
+	}
+
+	NewFake(WithRandomStart(0, logf))
+	if strings.Contains(logged, "seed 0") {
+		t.Errorf("a zero seed should be replaced by a chosen one: %q", logged)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var events []string
+	var reads int
+	clk := NewFake(
+		WithOnNow(func(time.Time) { reads++ }),
+		WithOnAdvance(func(from, to time.Time) {
+			events = append(events, fmt.Sprintf("advance %v", to.Sub(from)))
+		}),
+		WithOnTimerFire(func(info TimerInfo) {
+			events = append(events, fmt.Sprintf("%v fired at %v", info.Kind, info.Deadline.Unix()))
+		}),
+		WithSynchronousAfterFunc(),
+	)
+	start := clk.Now()
+	clk.Since(start)
+	clk.NowUnix()
+	if reads != 3 {
+		t.Errorf("got %d reads of the clock, want 3", reads)
+	}
+
+	tk := clk.NewTicker(2 * time.Second)
+	defer tk.Stop()
+	clk.AfterFunc(3*time.Second, func() { clk.Now() })
+	clk.After(0)
+	clk.Add(4 * time.Second)
+	clk.Set(start.Add(5 * time.Second))
+	if reads != 4 {
+		t.Errorf("got %d reads of the clock, want 4", reads)
+	}
+	want := []string{
+		"Timer fired at 0",
+		"Ticker fired at 2",
+		"AfterFunc fired at 3",
+		"Ticker fired at 4",
+		"advance 4s",
+		"advance 1s",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("got events %q, want %q", events, want)
+	}
+}
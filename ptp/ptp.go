@@ -0,0 +1,124 @@
+// Package ptp provides a clock.Clock that reads the time from a PTP
+// hardware clock (PHC), such as the one on a network card that an IEEE
+// 1588 daemon like ptp4l keeps in sync with a grandmaster, for code
+// that needs timestamps more precise than the system clock's.
+//
+// PHCs usually keep TAI, which is ahead of UTC by the leap seconds
+// since 1972, rather than UTC. A Clock reports its PHC's time as it is;
+// wrap it with clock.Offset to convert it.
+package ptp
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// Clock is a clock.Clock whose time is read from a PHC, as returned by
+// Open. It is a clock.TimeSource, so that clock.Fallback can fall back
+// from it to another clock when the PHC can't be read, and when it
+// can't be, its Now returns the system clock's time.
+//
+// A PHC's time can be stepped by the daemon keeping it in sync, and so
+// has no monotonic reading. NowMonotonic, and the Clock's durations,
+// Timers, Tickers and sleepers, are the system clock's. SleepUntil,
+// AfterAt and NewTimerAt convert the times they're given to the system
+// clock's with the PHC's offset from it when they're called.
+type Clock struct {
+	read  func() (time.Time, error)
+	close func() error
+	base  clock.Clock
+}
+
+// Close closes the PHC device. The Clock must not be used afterwards.
+func (c *Clock) Close() error {
+	return c.close()
+}
+
+// ReadTime returns the PHC's time, or an error if it can't be read.
+func (c *Clock) ReadTime() (time.Time, error) {
+	return c.read()
+}
+
+// offset returns how far ahead of the base clock the PHC is, or 0 if it
+// can't be read.
+func (c *Clock) offset() time.Duration {
+	t, err := c.read()
+	if err != nil {
+		return 0
+	}
+	return t.Sub(c.base.Now().Round(0))
+}
+
+func (c *Clock) Now() time.Time {
+	t, err := c.read()
+	if err != nil {
+		return c.base.Now()
+	}
+	return t
+}
+
+func (c *Clock) NowUnix() int64 {
+	return c.Now().Unix()
+}
+
+func (c *Clock) NowUnixMilli() int64 {
+	return c.Now().UnixMilli()
+}
+
+func (c *Clock) NowUnixNano() int64 {
+	return c.Now().UnixNano()
+}
+
+func (c *Clock) NowMonotonic() time.Duration {
+	return c.base.NowMonotonic()
+}
+
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *Clock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+func (c *Clock) Sleep(d time.Duration) {
+	c.base.Sleep(d)
+}
+
+func (c *Clock) SleepUntil(t time.Time) {
+	c.base.SleepUntil(t.Add(-c.offset()))
+}
+
+func (c *Clock) SleepContext(ctx context.Context, d time.Duration) error {
+	return c.base.SleepContext(ctx, d)
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.base.After(d)
+}
+
+func (c *Clock) AfterAt(t time.Time) <-chan time.Time {
+	return c.base.AfterAt(t.Add(-c.offset()))
+}
+
+func (c *Clock) NewTimer(d time.Duration) clock.Timer {
+	return c.base.NewTimer(d)
+}
+
+func (c *Clock) NewTimerAt(t time.Time) clock.Timer {
+	return c.base.NewTimerAt(t.Add(-c.offset()))
+}
+
+func (c *Clock) NewTicker(d time.Duration) clock.Ticker {
+	return c.base.NewTicker(d)
+}
+
+func (c *Clock) Tick(d time.Duration) <-chan time.Time {
+	return c.base.Tick(d)
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	return c.base.AfterFunc(d, f)
+}
@@ -0,0 +1,54 @@
+package ptp
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jmhodges/clock"
+	"golang.org/x/sys/unix"
+)
+
+// Open opens the PHC device at path, such as /dev/ptp0, and returns a
+// Clock reading its time with clock_gettime on its dynamic clock ID.
+// `ethtool -T` shows which PHC a network interface has.
+func Open(path string) (*Clock, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ptp: %w", err)
+	}
+	rc, err := f.SyscallConn()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("ptp: %w", err)
+	}
+	read := func() (time.Time, error) {
+		var ts unix.Timespec
+		var err error
+		cerr := rc.Control(func(fd uintptr) {
+			err = unix.ClockGettime(clockID(fd), &ts)
+		})
+		if cerr != nil {
+			return time.Time{}, fmt.Errorf("ptp: reading %s: %w", path, cerr)
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("ptp: reading %s: %w", path, err)
+		}
+		return time.Unix(ts.Unix()), nil
+	}
+	c := &Clock{read: read, close: f.Close, base: clock.Default()}
+	// Check that the device is a clock at all, so that Open fails
+	// rather than every read.
+	if _, err := read(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// clockID returns the dynamic clock ID of the POSIX clock device open
+// as fd, as the kernel's FD_TO_CLOCKID does.
+func clockID(fd uintptr) int32 {
+	const clockFD = 3
+	return int32(^int32(fd)<<3 | clockFD)
+}
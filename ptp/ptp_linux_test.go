@@ -0,0 +1,40 @@
+package ptp
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	if _, err := Open("/dev/ptp-does-not-exist"); err == nil {
+		t.Errorf("Open of a missing device succeeded")
+	}
+	// A file that isn't a clock device fails the first read.
+	if _, err := Open(os.Args[0]); err == nil {
+		t.Errorf("Open of a regular file succeeded")
+	}
+
+	c, err := Open("/dev/ptp0")
+	if err != nil {
+		t.Skipf("no PHC to test with: %v", err)
+	}
+	defer c.Close()
+	now, err := c.ReadTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A PHC in TAI is 37 seconds ahead, and one that no daemon has
+	// set may be anywhere, but its time mustn't stand still.
+	time.Sleep(time.Millisecond)
+	if !c.Now().After(now) {
+		t.Errorf("PHC time didn't move")
+	}
+}
+
+func TestClockID(t *testing.T) {
+	// From the kernel's FD_TO_CLOCKID: ((~(clockid_t) (fd)) << 3) | CLOCKFD.
+	if got, want := clockID(3), int32(-29); got != want {
+		t.Errorf("clockID(3) = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package ptp
+
+import "errors"
+
+// Open opens the PHC device at path. PHC devices are only supported on
+// Linux, and elsewhere Open always returns an error.
+func Open(path string) (*Clock, error) {
+	return nil, errors.New("ptp: PHC devices are only supported on Linux")
+}
@@ -0,0 +1,52 @@
+package ptp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// newTestClock returns a Clock whose PHC is phc's time, or err if it
+// isn't nil.
+func newTestClock(base clock.Clock, phc func() time.Time, err *error) *Clock {
+	return &Clock{
+		read: func() (time.Time, error) {
+			if *err != nil {
+				return time.Time{}, *err
+			}
+			return phc(), nil
+		},
+		close: func() error { return nil },
+		base:  base,
+	}
+}
+
+func TestClock(t *testing.T) {
+	fc := clock.NewFake()
+	tai := func() time.Time { return fc.Now().Add(37 * time.Second) }
+	var err error
+	c := newTestClock(fc, tai, &err)
+
+	if got := c.Now(); !got.Equal(tai()) {
+		t.Errorf("Now() = %v, want the PHC's %v", got, tai())
+	}
+	if got, gotErr := c.ReadTime(); gotErr != nil || !got.Equal(tai()) {
+		t.Errorf("ReadTime() = %v, %v, want %v", got, gotErr, tai())
+	}
+	tm := c.NewTimerAt(tai().Add(time.Second))
+	fc.Add(time.Second)
+	<-tm.C()
+
+	err = errors.New("device gone")
+	if _, gotErr := c.ReadTime(); gotErr != err {
+		t.Errorf("ReadTime() of a failing PHC returned error %v, want %v", gotErr, err)
+	}
+	if got := c.Now(); !got.Equal(fc.Now()) {
+		t.Errorf("Now() of a failing PHC = %v, want the system clock's %v", got, fc.Now())
+	}
+	if got := c.Close(); got != nil {
+		t.Errorf("Close() = %v", got)
+	}
+}
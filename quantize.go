@@ -0,0 +1,65 @@
+package clock
+
+import "time"
+
+// Quantize returns a Clock whose time moves only in whole steps of
+// quantum, counted from base's time when Quantize was called, however
+// often it is read. For example, a Clock from Quantize(clk, time.Second)
+// reads the same time throughout each second after Quantize was called.
+// That makes for stable cache keys and log buckets, and lets tests
+// reproduce many reads landing in the same quantum. Unlike those from
+// Truncate, its times keep base's monotonic clock readings, and its
+// NowMonotonic moves in the same steps. Its Timers, Tickers and sleepers
+// are base's. Quantize panics if quantum is not positive.
+func Quantize(base Clock, quantum time.Duration) Clock {
+	if quantum <= 0 {
+		panic("clock: non-positive quantum for Quantize")
+	}
+	return &quantized{Clock: base, quantum: quantum, start: base.Now(), mono: base.NowMonotonic()}
+}
+
+type quantized struct {
+	Clock
+	quantum time.Duration
+
+	// start and mono are base's readings when the clock was made.
+	start time.Time
+	mono  time.Duration
+}
+
+// floor rounds d down to a whole number of quanta.
+func (q *quantized) floor(d time.Duration) time.Duration {
+	r := d % q.quantum
+	if r < 0 {
+		r += q.quantum
+	}
+	return d - r
+}
+
+func (q *quantized) Now() time.Time {
+	return q.start.Add(q.floor(q.Clock.Now().Sub(q.start)))
+}
+
+func (q *quantized) NowUnix() int64 {
+	return q.Now().Unix()
+}
+
+func (q *quantized) NowUnixMilli() int64 {
+	return q.Now().UnixMilli()
+}
+
+func (q *quantized) NowUnixNano() int64 {
+	return q.Now().UnixNano()
+}
+
+func (q *quantized) NowMonotonic() time.Duration {
+	return q.mono + q.floor(q.Clock.NowMonotonic()-q.mono)
+}
+
+func (q *quantized) Since(t time.Time) time.Duration {
+	return q.Now().Sub(t)
+}
+
+func (q *quantized) Until(t time.Time) time.Duration {
+	return t.Sub(q.Now())
+}
@@ -0,0 +1,35 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuantize(t *testing.T) {
+	fc := NewFakeAt(time.Date(2024, time.June, 1, 0, 0, 0, 300, time.UTC))
+	start := fc.Now()
+	clk := Quantize(fc, time.Second)
+	tests := []struct {
+		add  time.Duration
+		want time.Duration
+	}{
+		{0, 0},
+		{999 * time.Millisecond, 0},
+		{time.Millisecond, time.Second},
+		{1500 * time.Millisecond, 2 * time.Second},
+		{-3 * time.Second, -time.Second},
+	}
+	for i, tc := range tests {
+		if tc.add < 0 {
+			fc.Set(fc.Now().Add(tc.add))
+		} else {
+			fc.Add(tc.add)
+		}
+		if got := clk.Since(start); got != tc.want {
+			t.Errorf("#%d: clock is %v past its start, want %v", i, got, tc.want)
+		}
+	}
+	if got, want := clk.NowMonotonic(), 2*time.Second; got != want {
+		t.Errorf("NowMonotonic() = %v, want %v", got, want)
+	}
+}
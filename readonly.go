@@ -0,0 +1,15 @@
+package clock
+
+// ReadOnly returns a Clock that behaves exactly like fc, but that can't
+// be type asserted back into a FakeClock, for handing to code under test
+// that mustn't move the clock it is given.
+func ReadOnly(fc FakeClock) Clock {
+	if f, ok := fc.(*fake); ok {
+		return &child{f: f}
+	}
+	return readOnly{fc}
+}
+
+type readOnly struct {
+	Clock
+}
@@ -0,0 +1,25 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadOnly(t *testing.T) {
+	fc := NewFake()
+	for _, clk := range []Clock{ReadOnly(fc), ReadOnly(struct{ FakeClock }{fc})} {
+		if _, ok := clk.(FakeClock); ok {
+			t.Errorf("ReadOnly returned a %T that is a FakeClock", clk)
+		}
+	}
+
+	clk := ReadOnly(fc)
+	tm := clk.NewTimer(time.Second)
+	fc.Add(time.Second)
+	if got, want := <-tm.C(), fc.Now(); !got.Equal(want) {
+		t.Errorf("Timer sent %v, want %v", got, want)
+	}
+	if got, want := clk.Now(), fc.Now(); !got.Equal(want) {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+}
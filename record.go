@@ -0,0 +1,187 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Event is a call to a Clock returned by Record.
+type Event struct {
+	// Op is the name of the method called, such as "Now" or
+	// "NewTimer".
+	Op string
+
+	// Time is the clock's time as read by Now, NowUnix, NowUnixMilli,
+	// NowUnixNano, Since and Until, or the time given to SleepUntil,
+	// AfterAt and NewTimerAt. It is the zero Time for other methods.
+	Time time.Time
+
+	// Duration is the reading returned by NowMonotonic, the duration
+	// returned by Since and Until, or the duration given to Sleep,
+	// SleepContext, After, NewTimer, NewTicker, Tick and AfterFunc. It
+	// is zero for other methods.
+	Duration time.Duration
+
+	// File and Line are where the method was called from.
+	File string
+	Line int
+}
+
+func (e Event) String() string {
+	var s string
+	switch e.Op {
+	case "Now", "NowUnix", "NowUnixMilli", "NowUnixNano":
+		s = fmt.Sprintf("%s() read %v", e.Op, e.Time)
+	case "Since", "Until":
+		s = fmt.Sprintf("%s() = %v at %v", e.Op, e.Duration, e.Time)
+	case "NowMonotonic":
+		s = fmt.Sprintf("%s() = %v", e.Op, e.Duration)
+	case "SleepUntil", "AfterAt", "NewTimerAt":
+		s = fmt.Sprintf("%s(%v)", e.Op, e.Time)
+	default:
+		s = fmt.Sprintf("%s(%v)", e.Op, e.Duration)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, s)
+}
+
+// Record returns a Clock that behaves exactly like clk, except that it
+// calls record with an Event for every call to one of its methods, on
+// the goroutine making the call and before the call returns, except
+// for Sleep, SleepUntil and SleepContext, which are recorded as they
+// start. Recording how code uses its Clock shows how much it depends on
+// time, and a Recording of it can be replayed. record may log or stream
+// the Events, or be a Recording's Add method to keep them in memory.
+func Record(clk Clock, record func(Event)) Clock {
+	return &recorder{clk: clk, record: record}
+}
+
+// Recording is an in-memory log of Events, safe for concurrent use. Its
+// Add method can be given to Record.
+type Recording struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// Add appends e to the Recording.
+func (r *Recording) Add(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+// Events returns the Events added to the Recording so far, in the order
+// they were added.
+func (r *Recording) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+type recorder struct {
+	clk    Clock
+	record func(Event)
+}
+
+// add records a call to op from the caller of the recorder's method.
+func (r *recorder) add(op string, t time.Time, d time.Duration) {
+	e := Event{Op: op, Time: t, Duration: d}
+	_, e.File, e.Line, _ = runtime.Caller(2)
+	r.record(e)
+}
+
+func (r *recorder) Now() time.Time {
+	t := r.clk.Now()
+	r.add("Now", t, 0)
+	return t
+}
+
+func (r *recorder) NowUnix() int64 {
+	t := r.clk.Now()
+	r.add("NowUnix", t, 0)
+	return t.Unix()
+}
+
+func (r *recorder) NowUnixMilli() int64 {
+	t := r.clk.Now()
+	r.add("NowUnixMilli", t, 0)
+	return t.UnixMilli()
+}
+
+func (r *recorder) NowUnixNano() int64 {
+	t := r.clk.Now()
+	r.add("NowUnixNano", t, 0)
+	return t.UnixNano()
+}
+
+func (r *recorder) NowMonotonic() time.Duration {
+	d := r.clk.NowMonotonic()
+	r.add("NowMonotonic", time.Time{}, d)
+	return d
+}
+
+func (r *recorder) Since(t time.Time) time.Duration {
+	now := r.clk.Now()
+	d := now.Sub(t)
+	r.add("Since", now, d)
+	return d
+}
+
+func (r *recorder) Until(t time.Time) time.Duration {
+	now := r.clk.Now()
+	d := t.Sub(now)
+	r.add("Until", now, d)
+	return d
+}
+
+func (r *recorder) Sleep(d time.Duration) {
+	r.add("Sleep", time.Time{}, d)
+	r.clk.Sleep(d)
+}
+
+func (r *recorder) SleepUntil(t time.Time) {
+	r.add("SleepUntil", t, 0)
+	r.clk.SleepUntil(t)
+}
+
+func (r *recorder) SleepContext(ctx context.Context, d time.Duration) error {
+	r.add("SleepContext", time.Time{}, d)
+	return r.clk.SleepContext(ctx, d)
+}
+
+func (r *recorder) After(d time.Duration) <-chan time.Time {
+	r.add("After", time.Time{}, d)
+	return r.clk.After(d)
+}
+
+func (r *recorder) AfterAt(t time.Time) <-chan time.Time {
+	r.add("AfterAt", t, 0)
+	return r.clk.AfterAt(t)
+}
+
+func (r *recorder) NewTimer(d time.Duration) Timer {
+	r.add("NewTimer", time.Time{}, d)
+	return r.clk.NewTimer(d)
+}
+
+func (r *recorder) NewTimerAt(t time.Time) Timer {
+	r.add("NewTimerAt", t, 0)
+	return r.clk.NewTimerAt(t)
+}
+
+func (r *recorder) NewTicker(d time.Duration) Ticker {
+	r.add("NewTicker", time.Time{}, d)
+	return r.clk.NewTicker(d)
+}
+
+func (r *recorder) Tick(d time.Duration) <-chan time.Time {
+	r.add("Tick", time.Time{}, d)
+	return r.clk.Tick(d)
+}
+
+func (r *recorder) AfterFunc(d time.Duration, fn func()) Timer {
+	r.add("AfterFunc", time.Time{}, d)
+	return r.clk.AfterFunc(d, fn)
+}
@@ -0,0 +1,42 @@
+package clock
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	fc := NewFake()
+	var rec Recording
+	clk := Record(fc, rec.Add)
+	start := clk.Now()
+	fc.Add(time.Minute)
+	clk.Since(start)
+	clk.Sleep(0)
+	tm := clk.NewTimer(time.Second)
+	defer tm.Stop()
+
+	events := rec.Events()
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want 4: %v", len(events), events)
+	}
+	want := []Event{
+		{Op: "Now", Time: start},
+		{Op: "Since", Time: start.Add(time.Minute), Duration: time.Minute},
+		{Op: "Sleep"},
+		{Op: "NewTimer", Duration: time.Second},
+	}
+	for i, e := range events {
+		if e.Op != want[i].Op || !e.Time.Equal(want[i].Time) || e.Duration != want[i].Duration {
+			t.Errorf("event %d: got %v, want %v", i, e, want[i])
+		}
+		if filepath.Base(e.File) != "record_test.go" || e.Line == 0 {
+			t.Errorf("event %d called from %s:%d, want record_test.go", i, e.File, e.Line)
+		}
+	}
+	if got := events[1].String(); !strings.HasSuffix(got, ": Since() = 1m0s at 1970-01-01 00:01:00 +0000 UTC") {
+		t.Errorf("String() = %q", got)
+	}
+}
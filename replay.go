@@ -0,0 +1,153 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReplayClock is a Clock that replays the times read from another
+// Clock, as returned by Replay.
+type ReplayClock interface {
+	Clock
+
+	// Remaining returns the number of times read in the recording
+	// that have yet to be replayed.
+	Remaining() int
+}
+
+// Replay returns a ReplayClock whose successive readings are those in
+// events, a recording made with Record, so that the exact timing of a
+// production incident can be replayed in a test. Each call to Now,
+// NowUnix, NowUnixMilli, NowUnixNano, Since or Until returns the next
+// time read by any of them in the recording, and each call to
+// NowMonotonic returns the next NowMonotonic reading. Once the
+// recording runs out, the last reading is returned again, or the Unix
+// epoch or zero if there were none. Events for other methods are
+// ignored.
+//
+// The clock's time only moves as its readings are replayed, and never
+// backwards. Its Timers, Tickers and AfterFunc functions fire once a
+// replayed reading reaches their deadlines, so code that waits on one
+// without reading the clock should not be replayed. Sleep and SleepUntil
+// return immediately, since how long the recorded code slept shows in
+// the readings that follow.
+func Replay(events []Event) ReplayClock {
+	r := &replay{}
+	for _, e := range events {
+		switch e.Op {
+		case "Now", "NowUnix", "NowUnixMilli", "NowUnixNano", "Since", "Until":
+			r.times = append(r.times, e.Time)
+		case "NowMonotonic":
+			r.monos = append(r.monos, e.Duration)
+		}
+	}
+	start := time.Unix(0, 0).UTC()
+	if len(r.times) > 0 {
+		start = r.times[0]
+	}
+	r.f = NewFakeAt(start).(*fake)
+	return r
+}
+
+type replay struct {
+	f *fake
+
+	mu    sync.Mutex
+	times []time.Time
+	monos []time.Duration
+	t     int
+	mono  int
+}
+
+func (r *replay) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.times) - r.t
+}
+
+func (r *replay) Now() time.Time {
+	r.mu.Lock()
+	if len(r.times) == 0 {
+		r.mu.Unlock()
+		return r.f.Now()
+	}
+	t := r.times[min(r.t, len(r.times)-1)]
+	if r.t < len(r.times) {
+		r.t++
+	}
+	r.mu.Unlock()
+	if t.After(r.f.Now()) {
+		r.f.Set(t)
+	}
+	return t
+}
+
+func (r *replay) NowUnix() int64 {
+	return r.Now().Unix()
+}
+
+func (r *replay) NowUnixMilli() int64 {
+	return r.Now().UnixMilli()
+}
+
+func (r *replay) NowUnixNano() int64 {
+	return r.Now().UnixNano()
+}
+
+func (r *replay) NowMonotonic() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.monos) == 0 {
+		return 0
+	}
+	d := r.monos[min(r.mono, len(r.monos)-1)]
+	if r.mono < len(r.monos) {
+		r.mono++
+	}
+	return d
+}
+
+func (r *replay) Since(t time.Time) time.Duration {
+	return r.Now().Sub(t)
+}
+
+func (r *replay) Until(t time.Time) time.Duration {
+	return t.Sub(r.Now())
+}
+
+func (r *replay) Sleep(d time.Duration) {}
+
+func (r *replay) SleepUntil(t time.Time) {}
+
+func (r *replay) SleepContext(ctx context.Context, d time.Duration) error {
+	return ctx.Err()
+}
+
+func (r *replay) After(d time.Duration) <-chan time.Time {
+	return r.f.After(d)
+}
+
+func (r *replay) AfterAt(t time.Time) <-chan time.Time {
+	return r.f.AfterAt(t)
+}
+
+func (r *replay) NewTimer(d time.Duration) Timer {
+	return r.f.NewTimer(d)
+}
+
+func (r *replay) NewTimerAt(t time.Time) Timer {
+	return r.f.NewTimerAt(t)
+}
+
+func (r *replay) NewTicker(d time.Duration) Ticker {
+	return r.f.NewTicker(d)
+}
+
+func (r *replay) Tick(d time.Duration) <-chan time.Time {
+	return r.f.Tick(d)
+}
+
+func (r *replay) AfterFunc(d time.Duration, fn func()) Timer {
+	return r.f.AfterFunc(d, fn)
+}
@@ -0,0 +1,54 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplay(t *testing.T) {
+	// Record some code using a clock, then replay it.
+	fc := NewFake()
+	var rec Recording
+	recorded := Record(fc, rec.Add)
+	var want []time.Time
+	for i := 0; i < 3; i++ {
+		want = append(want, recorded.Now())
+		recorded.NowMonotonic()
+		fc.Add(time.Duration(i+1) * time.Second)
+	}
+	recorded.After(time.Second)
+
+	clk := Replay(rec.Events())
+	if got := clk.Remaining(); got != 3 {
+		t.Errorf("Remaining() = %d, want 3", got)
+	}
+	tm := clk.NewTimer(time.Second)
+	if got := clk.Now(); !got.Equal(want[0]) {
+		t.Errorf("first Now() = %v, want %v", got, want[0])
+	}
+	if got := clk.NowUnixNano(); got != want[1].UnixNano() {
+		t.Errorf("second reading, NowUnixNano() = %d, want %d", got, want[1].UnixNano())
+	}
+	select {
+	case got := <-tm.C():
+		if !got.Equal(want[1]) {
+			t.Errorf("Timer sent %v, want %v", got, want[1])
+		}
+	default:
+		t.Errorf("Timer did not fire once the replay passed its deadline")
+	}
+	if got, want := clk.Since(want[0]), 3*time.Second; got != want {
+		t.Errorf("third reading, Since = %v, want %v", got, want)
+	}
+	if got := clk.Now(); !got.Equal(want[2]) {
+		t.Errorf("Now() after the recording ran out = %v, want %v", got, want[2])
+	}
+	if got := clk.Remaining(); got != 0 {
+		t.Errorf("Remaining() = %d, want 0", got)
+	}
+	for i, want := range []time.Duration{0, time.Second, 3 * time.Second, 3 * time.Second} {
+		if got := clk.NowMonotonic(); got != want {
+			t.Errorf("NowMonotonic() #%d = %v, want %v", i, got, want)
+		}
+	}
+}
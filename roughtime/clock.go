@@ -0,0 +1,329 @@
+package roughtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+var (
+	// ErrUnsynchronized is returned by Clock.ReadTime when the Clock
+	// hasn't synced with its servers yet.
+	ErrUnsynchronized = errors.New("roughtime: clock is unsynchronized")
+
+	// ErrInconsistent is returned by Clock.Sync when the times its
+	// servers responded with don't agree, so that at least one of them
+	// is wrong.
+	ErrInconsistent = errors.New("roughtime: servers' times are inconsistent")
+)
+
+// maxTime is the latest time a time.Time can hold.
+var maxTime = time.Unix(1<<63-1-62135596800, 999999999)
+
+// Option configures New.
+type Option func(*options)
+
+type options struct {
+	interval time.Duration
+	timeout  time.Duration
+	maxDrift float64
+}
+
+// WithInterval sets how long Run waits between syncs. The default is an
+// hour.
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// WithTimeout sets how long Sync waits for each server to respond. The
+// default is 5 seconds. Since it bounds reads from the network, it is
+// measured on the system clock, even when the base clock is a
+// clock.FakeClock.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithMaxDrift sets how fast, in parts per million, the Clock assumes
+// its base clock may drift at most, and so how fast its uncertainty
+// grows after each sync. The default is 100, which is generous for a
+// quartz oscillator.
+func WithMaxDrift(ppm float64) Option {
+	return func(o *options) {
+		o.maxDrift = ppm / 1e6
+	}
+}
+
+// Stats describes a Clock's syncs.
+type Stats struct {
+	// Servers is how many servers responded to the last successful
+	// sync, and Offset and Uncertainty are what the sync measured the
+	// base clock's offset from the true time to be, and how far off
+	// that may be.
+	Servers     int
+	Offset      time.Duration
+	Uncertainty time.Duration
+
+	// LastSync is the base clock's time at the last successful sync, or
+	// the zero time if there hasn't been one.
+	LastSync time.Time
+
+	// Err is the error the latest sync failed with, or nil if it
+	// succeeded. A sync succeeds if any servers respond with times that
+	// agree, and Err then joins the errors of any that didn't respond.
+	Err error
+}
+
+// Clock is a clock.IntervalClock whose time is its base clock's,
+// corrected by the offset to the true time that it last measured with
+// Sync, and whose uncertainty is bounded by the Radius and RTT of the
+// servers' responses, plus however far the base clock may have drifted
+// since. Until its first successful Sync, its time is its base clock's,
+// and its NowInterval is unbounded: from the zero time to the latest a
+// time.Time can hold. It is a clock.TimeSource, so that clock.Fallback
+// can fall back from it to another clock when it is unsynchronized.
+//
+// NowMonotonic, and the Clock's durations, Timers, Tickers and sleepers,
+// are its base clock's, and the times its Timers and Tickers send are
+// the base clock's times. SleepUntil, AfterAt and NewTimerAt convert
+// the times they're given to the base clock's with the Clock's offset
+// when they're called.
+type Clock struct {
+	base    clock.Clock
+	servers []Server
+	opts    options
+
+	mu    sync.Mutex
+	stats Stats
+	// changed is closed, and replaced, whenever a sync succeeds, to wake
+	// calls to WaitUntilAfter.
+	changed chan struct{}
+}
+
+// New returns a Clock whose time is base's corrected by what servers
+// respond with. It makes no requests until Sync or Run is called.
+func New(base clock.Clock, servers []Server, opts ...Option) *Clock {
+	o := options{interval: time.Hour, timeout: 5 * time.Second, maxDrift: 100e-6}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Clock{base: base, servers: servers, opts: o, changed: make(chan struct{})}
+}
+
+// Sync queries each of the Clock's servers at once, and sets the Clock's
+// offset and uncertainty to the narrowest Interval that all of the
+// Intervals of their responses, moved to the base clock's time, are
+// within. It returns ErrInconsistent if there is no such Interval,
+// leaving the Clock as it was, and an error joining those of every
+// server if none respond.
+func (c *Clock) Sync(ctx context.Context) error {
+	type result struct {
+		resp Response
+		err  error
+	}
+	results := make(chan result, len(c.servers))
+	for _, server := range c.servers {
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, c.opts.timeout)
+			defer cancel()
+			resp, err := Query(ctx, c.base, server)
+			results <- result{resp, err}
+		}()
+	}
+	var lo, hi time.Duration
+	var errs []error
+	n := 0
+	for range c.servers {
+		r := <-results
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		// The offsets from the base clock's time at which the true
+		// time could be, by this response.
+		i := r.resp.Interval()
+		rlo, rhi := i.Earliest.Sub(r.resp.Received), i.Latest.Sub(r.resp.Received)
+		if n == 0 {
+			lo, hi = rlo, rhi
+		} else {
+			lo, hi = max(lo, rlo), min(hi, rhi)
+		}
+		n++
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case n == 0 && len(errs) == 0:
+		c.stats.Err = errors.New("roughtime: no servers to sync with")
+		return c.stats.Err
+	case n == 0:
+		c.stats.Err = errors.Join(errs...)
+		return c.stats.Err
+	case lo > hi:
+		c.stats.Err = ErrInconsistent
+		return c.stats.Err
+	}
+	c.stats = Stats{
+		Servers:     n,
+		Offset:      lo + (hi-lo)/2,
+		Uncertainty: (hi - lo + 1) / 2,
+		LastSync:    c.base.Now(),
+		Err:         errors.Join(errs...),
+	}
+	close(c.changed)
+	c.changed = make(chan struct{})
+	return nil
+}
+
+// Run calls Sync, and then again every interval on the base clock, until
+// ctx is done, when it returns ctx's error. A failed Sync leaves the
+// Clock as it was, and is reported by Stats.
+func (c *Clock) Run(ctx context.Context) error {
+	for {
+		c.Sync(ctx)
+		t := c.base.NewTimer(c.opts.interval)
+		select {
+		case <-t.C():
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Stats returns the Clock's Stats.
+func (c *Clock) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// ReadTime returns the Clock's time, or ErrUnsynchronized if it hasn't
+// synced successfully yet.
+func (c *Clock) ReadTime() (time.Time, error) {
+	c.mu.Lock()
+	last := c.stats.LastSync
+	c.mu.Unlock()
+	if last.IsZero() {
+		return time.Time{}, ErrUnsynchronized
+	}
+	return c.Now(), nil
+}
+
+// nowInterval returns the Clock's time and NowInterval, and a channel
+// closed at its next successful sync.
+func (c *Clock) nowInterval() (time.Time, clock.Interval, <-chan struct{}) {
+	b := c.base.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stats.LastSync.IsZero() {
+		return b, clock.Interval{Latest: maxTime}, c.changed
+	}
+	now := b.Add(c.stats.Offset)
+	drift := time.Duration(float64(b.Sub(c.stats.LastSync).Abs()) * c.opts.maxDrift)
+	u := c.stats.Uncertainty + drift
+	return now, clock.Interval{Earliest: now.Add(-u), Latest: now.Add(u)}, c.changed
+}
+
+func (c *Clock) NowInterval() clock.Interval {
+	_, i, _ := c.nowInterval()
+	return i
+}
+
+func (c *Clock) WaitUntilAfter(i clock.Interval) {
+	for {
+		_, now, changed := c.nowInterval()
+		if now.After(i.Latest) {
+			return
+		}
+		// The uncertainty grows while waiting, so this may not be long
+		// enough, but it is never too long.
+		t := c.base.NewTimer(i.Latest.Sub(now.Earliest) + 1)
+		select {
+		case <-t.C():
+		case <-changed:
+			t.Stop()
+		}
+	}
+}
+
+func (c *Clock) offset() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats.Offset
+}
+
+func (c *Clock) Now() time.Time {
+	return c.base.Now().Add(c.offset())
+}
+
+func (c *Clock) NowUnix() int64 {
+	return c.Now().Unix()
+}
+
+func (c *Clock) NowUnixMilli() int64 {
+	return c.Now().UnixMilli()
+}
+
+func (c *Clock) NowUnixNano() int64 {
+	return c.Now().UnixNano()
+}
+
+func (c *Clock) NowMonotonic() time.Duration {
+	return c.base.NowMonotonic()
+}
+
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *Clock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+func (c *Clock) Sleep(d time.Duration) {
+	c.base.Sleep(d)
+}
+
+func (c *Clock) SleepUntil(t time.Time) {
+	c.base.SleepUntil(t.Add(-c.offset()))
+}
+
+func (c *Clock) SleepContext(ctx context.Context, d time.Duration) error {
+	return c.base.SleepContext(ctx, d)
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.base.After(d)
+}
+
+func (c *Clock) AfterAt(t time.Time) <-chan time.Time {
+	return c.base.AfterAt(t.Add(-c.offset()))
+}
+
+func (c *Clock) NewTimer(d time.Duration) clock.Timer {
+	return c.base.NewTimer(d)
+}
+
+func (c *Clock) NewTimerAt(t time.Time) clock.Timer {
+	return c.base.NewTimerAt(t.Add(-c.offset()))
+}
+
+func (c *Clock) NewTicker(d time.Duration) clock.Ticker {
+	return c.base.NewTicker(d)
+}
+
+func (c *Clock) Tick(d time.Duration) <-chan time.Time {
+	return c.base.Tick(d)
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	return c.base.AfterFunc(d, f)
+}
@@ -0,0 +1,113 @@
+package roughtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+var _ clock.IntervalClock = (*Clock)(nil)
+
+func TestClockSync(t *testing.T) {
+	fc := clock.NewFake(clock.WithStart(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	s := newSigner(t)
+	// Two servers whose Intervals overlap from 1h-0.5s to 1h+1s ahead
+	// of the base clock.
+	a := newServer(t, s.publicKey(), at(s, func() time.Time { return fc.Now().Add(time.Hour) }, time.Second))
+	b := newServer(t, s.publicKey(), at(s, func() time.Time { return fc.Now().Add(time.Hour + 1500*time.Millisecond) }, 2*time.Second))
+	c := New(fc, []Server{a.server(), b.server()}, WithMaxDrift(1000))
+
+	if _, err := c.ReadTime(); err != ErrUnsynchronized {
+		t.Errorf("ReadTime() before syncing returned error %v, want ErrUnsynchronized", err)
+	}
+	if got := c.NowInterval(); !got.Contains(time.Time{}) || !got.Contains(fc.Now().AddDate(1000, 0, 0)) {
+		t.Errorf("NowInterval() before syncing = %v, want it unbounded", got)
+	}
+
+	if err := c.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := clock.Interval{Earliest: fc.Now().Add(time.Hour - 500*time.Millisecond), Latest: fc.Now().Add(time.Hour + time.Second)}
+	if got := c.NowInterval(); got.Earliest.Sub(want.Earliest).Abs() > 1 || got.Latest.Sub(want.Latest).Abs() > 1 {
+		t.Errorf("NowInterval() = %v, want %v", got, want)
+	}
+	if got, err := c.ReadTime(); err != nil || !got.Equal(c.Now()) {
+		t.Errorf("ReadTime() = %v, %v, want %v", got, err, c.Now())
+	}
+	if st := c.Stats(); st.Servers != 2 || st.Offset != time.Hour+250*time.Millisecond || st.Err != nil {
+		t.Errorf("Stats() = %+v, want 2 servers with an offset of 1h0m0.25s", st)
+	}
+
+	// The uncertainty grows with the base clock's possible drift.
+	fc.Add(1000 * time.Second)
+	if got, want := c.NowInterval().Width(), 3500*time.Millisecond; got < want-1 || got > want+1 {
+		t.Errorf("NowInterval().Width() 1000s later = %v, want %v", got, want)
+	}
+}
+
+func TestClockSyncInconsistent(t *testing.T) {
+	fc := clock.NewFake(clock.WithStart(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	s := newSigner(t)
+	a := newServer(t, s.publicKey(), at(s, fc.Now, time.Second))
+	b := newServer(t, s.publicKey(), at(s, func() time.Time { return fc.Now().Add(time.Minute) }, time.Second))
+	c := New(fc, []Server{a.server(), b.server()})
+	if err := c.Sync(context.Background()); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("Sync() with servers a minute apart returned %v, want ErrInconsistent", err)
+	}
+	if _, err := c.ReadTime(); err != ErrUnsynchronized {
+		t.Errorf("ReadTime() after an inconsistent sync returned %v, want ErrUnsynchronized", err)
+	}
+}
+
+func TestClockRun(t *testing.T) {
+	fc := clock.NewFake(clock.WithStart(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	s := newSigner(t)
+	srv := newServer(t, s.publicKey(), at(s, func() time.Time { return fc.Now().Add(time.Minute) }, time.Second))
+	dead := newServer(t, s.publicKey(), func([]byte) []byte { return nil })
+	c := New(fc, []Server{srv.server(), dead.server()}, WithInterval(time.Minute), WithTimeout(50*time.Millisecond))
+
+	// A commit wait that started before the clock knew the time ends
+	// once it does.
+	waited := make(chan struct{})
+	go func() {
+		c.WaitUntilAfter(clock.Interval{Earliest: fc.Now(), Latest: fc.Now()})
+		close(waited)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+	<-waited
+	fc.BlockUntil(1)
+	if st := c.Stats(); st.Servers != 1 || st.Err == nil {
+		t.Errorf("Stats() = %+v, want one server and the other's error", st)
+	}
+	if got := c.Since(fc.Now()); got != time.Minute {
+		t.Errorf("Since(base time) = %v, want 1m", got)
+	}
+
+	commit := c.NowInterval()
+	waited = make(chan struct{})
+	go func() {
+		c.WaitUntilAfter(commit)
+		close(waited)
+	}()
+	fc.BlockUntil(2)
+	fc.Add(2 * time.Second)
+	select {
+	case <-waited:
+		t.Fatal("WaitUntilAfter returned before its Interval had passed")
+	case <-time.After(10 * time.Millisecond):
+	}
+	fc.BlockUntil(2)
+	fc.Add(time.Second)
+	<-waited
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v, want context.Canceled", err)
+	}
+}
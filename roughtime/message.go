@@ -0,0 +1,90 @@
+package roughtime
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+)
+
+// Tags of the message fields used, each its name's bytes read as a
+// little-endian uint32.
+const (
+	tagSIG  = 0x00474953 // "SIG\x00"
+	tagNONC = 0x434e4f4e // "NONC"
+	tagDELE = 0x454c4544 // "DELE"
+	tagPATH = 0x48544150 // "PATH"
+	tagRADI = 0x49444152 // "RADI"
+	tagPUBK = 0x4b425550 // "PUBK"
+	tagMIDP = 0x5044494d // "MIDP"
+	tagSREP = 0x50455253 // "SREP"
+	tagMINT = 0x544e494d // "MINT"
+	tagROOT = 0x544f4f52 // "ROOT"
+	tagCERT = 0x54524543 // "CERT"
+	tagMAXT = 0x5458414d // "MAXT"
+	tagINDX = 0x58444e49 // "INDX"
+	tagPAD  = 0xff444150 // "PAD\xff"
+)
+
+var errMalformed = errors.New("malformed message")
+
+// encodeMessage encodes msg, whose values' lengths must be multiples of
+// four, as a Roughtime message: the number of fields, the offset of
+// each value after the first, the tags in ascending order, and the
+// values, all little-endian.
+func encodeMessage(msg map[uint32][]byte) []byte {
+	tags := make([]uint32, 0, len(msg))
+	for tag := range msg {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	b := binary.LittleEndian.AppendUint32(nil, uint32(len(tags)))
+	offset := 0
+	for i, tag := range tags {
+		if i > 0 {
+			b = binary.LittleEndian.AppendUint32(b, uint32(offset))
+		}
+		offset += len(msg[tag])
+	}
+	for _, tag := range tags {
+		b = binary.LittleEndian.AppendUint32(b, tag)
+	}
+	for _, tag := range tags {
+		b = append(b, msg[tag]...)
+	}
+	return b
+}
+
+// parseMessage parses a Roughtime message. The values point into b.
+func parseMessage(b []byte) (map[uint32][]byte, error) {
+	if len(b) < 4 || len(b)%4 != 0 {
+		return nil, errMalformed
+	}
+	n := int(binary.LittleEndian.Uint32(b))
+	if n == 0 {
+		return map[uint32][]byte{}, nil
+	}
+	header := 8 * n
+	if n > len(b)/8 || header > len(b) {
+		return nil, errMalformed
+	}
+	values := b[header:]
+	msg := make(map[uint32][]byte, n)
+	var prevTag uint32
+	start := 0
+	for i := range n {
+		end := len(values)
+		if i < n-1 {
+			end = int(binary.LittleEndian.Uint32(b[4+4*i:]))
+		}
+		if end < start || end > len(values) || end%4 != 0 {
+			return nil, errMalformed
+		}
+		tag := binary.LittleEndian.Uint32(b[4*n+4*i:])
+		if i > 0 && tag <= prevTag {
+			return nil, errMalformed
+		}
+		msg[tag] = values[start:end:end]
+		prevTag, start = tag, end
+	}
+	return msg, nil
+}
@@ -0,0 +1,232 @@
+// Package roughtime provides a clock.Clock kept in sync with Roughtime
+// servers, for security-sensitive code, such as certificate validation
+// on devices whose real-time clocks have lost their time, that can't
+// trust the system clock and needs time it can authenticate.
+//
+// Query makes a single request to a server, in the protocol's original
+// version, and checks its response is signed by the server's long-term
+// public key, so that the time it returns, and the bound on how far off
+// that time may be, can be trusted as far as the server can. A Clock
+// makes requests periodically to a set of servers, and is a
+// clock.IntervalClock whose NowInterval is bounded by the time their
+// responses agree on.
+package roughtime
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+const (
+	nonceLen   = 64
+	requestLen = 1024
+	hashLen    = sha512.Size
+)
+
+// The prefixes signed along with the messages they sign, so that a
+// signature over one can't be passed off as one over the other.
+var (
+	responseContext   = []byte("RoughTime v1 response signature\x00")
+	delegationContext = []byte("RoughTime v1 delegation signature--\x00")
+)
+
+// Server is a Roughtime server.
+type Server struct {
+	// Address is the server's host and UDP port, such as
+	// "roughtime.example.com:2002".
+	Address string
+
+	// PublicKey is the server's long-term Ed25519 public key, which its
+	// responses must be signed by, through a delegated key.
+	PublicKey ed25519.PublicKey
+}
+
+// Response is the time a server replied to Query with, and what was
+// measured of it.
+type Response struct {
+	// Midpoint is the server's time, and Radius how far off it says
+	// that may be, when it signed the response.
+	Midpoint time.Time
+	Radius   time.Duration
+
+	// RTT is how long the request and response took to arrive, on the
+	// clock Query was given, including the time the server took
+	// between receiving one and sending the other.
+	RTT time.Duration
+
+	// Received is the time on the clock Query was given when the
+	// response arrived.
+	Received time.Time
+}
+
+// Interval returns the Interval the true time was within when the
+// response was received, assuming the server is honest: its Midpoint
+// plus or minus its Radius, plus up to its RTT, since the server signed
+// the response at some point during it.
+func (r Response) Interval() clock.Interval {
+	return clock.Interval{
+		Earliest: r.Midpoint.Add(-r.Radius),
+		Latest:   r.Midpoint.Add(r.Radius + r.RTT),
+	}
+}
+
+// Query sends a Roughtime request to server and waits for its response,
+// timing both on clk. The request's deadline is ctx's.
+//
+// Query returns an error if the server doesn't respond, or its reply
+// isn't a response to the request signed by a key that server's
+// PublicKey delegated to at the time in the response.
+func Query(ctx context.Context, clk clock.Clock, server Server) (Response, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", server.Address)
+	if err != nil {
+		return Response{}, fmt.Errorf("roughtime: querying %s: %w", server.Address, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	nonce := make([]byte, nonceLen)
+	rand.Read(nonce)
+	req := newRequest(nonce)
+
+	t1 := clk.Now()
+	if _, err := conn.Write(req); err != nil {
+		return Response{}, fmt.Errorf("roughtime: querying %s: %w", server.Address, err)
+	}
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return Response{}, fmt.Errorf("roughtime: querying %s: %w", server.Address, err)
+		}
+		t4 := clk.Now()
+		mid, radius, err := verify(buf[:n], nonce, server.PublicKey)
+		if errors.Is(err, errOtherNonce) {
+			// Not a reply to this request, such as a late one to an
+			// earlier request from the same port.
+			continue
+		}
+		if err != nil {
+			return Response{}, fmt.Errorf("roughtime: querying %s: %w", server.Address, err)
+		}
+		return Response{Midpoint: mid, Radius: radius, RTT: t4.Sub(t1), Received: t4}, nil
+	}
+}
+
+// newRequest returns a request for the time with nonce, padded to the
+// minimum request size so that the protocol can't be used to amplify
+// attacks.
+func newRequest(nonce []byte) []byte {
+	// The header of a message with two fields is 16 bytes.
+	pad := requestLen - 16 - len(nonce)
+	return encodeMessage(map[uint32][]byte{
+		tagNONC: nonce,
+		tagPAD:  make([]byte, pad),
+	})
+}
+
+var errOtherNonce = errors.New("response is to another request")
+
+// verify checks that b is a response to a request with nonce, signed by
+// a key that pub delegated to, and returns the time and radius in it.
+func verify(b, nonce []byte, pub ed25519.PublicKey) (time.Time, time.Duration, error) {
+	msg, err := parseMessage(b)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	sig, path, srepBytes, certBytes, indx := msg[tagSIG], msg[tagPATH], msg[tagSREP], msg[tagCERT], msg[tagINDX]
+	if len(sig) != ed25519.SignatureSize || len(path)%hashLen != 0 || srepBytes == nil || certBytes == nil || len(indx) != 4 {
+		return time.Time{}, 0, errors.New("response is missing fields")
+	}
+	srep, err := parseMessage(srepBytes)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("signed response: %w", err)
+	}
+	root, midp, radi := srep[tagROOT], srep[tagMIDP], srep[tagRADI]
+	if len(root) != hashLen || len(midp) != 8 || len(radi) != 4 {
+		return time.Time{}, 0, errors.New("signed response is missing fields")
+	}
+
+	// The nonce must be a leaf of the Merkle tree whose root the server
+	// signed.
+	hash := leafHash(nonce)
+	index := binary.LittleEndian.Uint32(indx)
+	for ; len(path) > 0; path = path[hashLen:] {
+		if index&1 == 0 {
+			hash = nodeHash(hash, path[:hashLen])
+		} else {
+			hash = nodeHash(path[:hashLen], hash)
+		}
+		index >>= 1
+	}
+	if index != 0 || !bytes.Equal(hash, root) {
+		return time.Time{}, 0, errOtherNonce
+	}
+
+	cert, err := parseMessage(certBytes)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("certificate: %w", err)
+	}
+	deleSig, deleBytes := cert[tagSIG], cert[tagDELE]
+	if len(deleSig) != ed25519.SignatureSize || deleBytes == nil {
+		return time.Time{}, 0, errors.New("certificate is missing fields")
+	}
+	if !ed25519.Verify(pub, append(bytes.Clone(delegationContext), deleBytes...), deleSig) {
+		return time.Time{}, 0, errors.New("certificate isn't signed by the server's public key")
+	}
+	dele, err := parseMessage(deleBytes)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("delegation: %w", err)
+	}
+	deleKey, mint, maxt := dele[tagPUBK], dele[tagMINT], dele[tagMAXT]
+	if len(deleKey) != ed25519.PublicKeySize || len(mint) != 8 || len(maxt) != 8 {
+		return time.Time{}, 0, errors.New("delegation is missing fields")
+	}
+	if !ed25519.Verify(deleKey, append(bytes.Clone(responseContext), srepBytes...), sig) {
+		return time.Time{}, 0, errors.New("response isn't signed by the delegated key")
+	}
+	m := binary.LittleEndian.Uint64(midp)
+	if m < binary.LittleEndian.Uint64(mint) || m > binary.LittleEndian.Uint64(maxt) {
+		return time.Time{}, 0, errors.New("response's time is outside its delegation's validity")
+	}
+	if m > math.MaxInt64 {
+		return time.Time{}, 0, errors.New("response's time is out of range")
+	}
+	radius := time.Duration(binary.LittleEndian.Uint32(radi)) * time.Microsecond
+	return time.UnixMicro(int64(m)).UTC(), radius, nil
+}
+
+func leafHash(nonce []byte) []byte {
+	h := sha512.New()
+	h.Write([]byte{0})
+	h.Write(nonce)
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha512.New()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
@@ -0,0 +1,233 @@
+package roughtime
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmhodges/clock"
+)
+
+// testServer is a Roughtime server on localhost.
+type testServer struct {
+	conn net.PacketConn
+	pub  ed25519.PublicKey
+}
+
+// signer signs responses as a server with a long-term key that
+// delegated to another key, valid from mint to maxt.
+type signer struct {
+	rootKey, deleKey ed25519.PrivateKey
+	mint, maxt       time.Time
+}
+
+func newSigner(t *testing.T) *signer {
+	t.Helper()
+	_, rootKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, deleKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &signer{
+		rootKey: rootKey,
+		deleKey: deleKey,
+		mint:    time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		maxt:    time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (s *signer) publicKey() ed25519.PublicKey {
+	return s.rootKey.Public().(ed25519.PublicKey)
+}
+
+func u32(v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, v)
+}
+
+func u64(v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, v)
+}
+
+// respond returns a response to a request with nonce, batched in a
+// Merkle tree with three other requests, at mid with radius.
+func (s *signer) respond(nonce []byte, mid time.Time, radius time.Duration) []byte {
+	// Put the nonce third of four leaves, so that the path goes both
+	// left and right.
+	const index = 2
+	leaves := make([][]byte, 4)
+	for i := range leaves {
+		leaves[i] = leafHash(bytes.Repeat([]byte{byte(i)}, nonceLen))
+	}
+	leaves[index] = leafHash(nonce)
+	left, right := nodeHash(leaves[0], leaves[1]), nodeHash(leaves[2], leaves[3])
+	root := nodeHash(left, right)
+	path := append(bytes.Clone(leaves[3]), left...)
+
+	srep := encodeMessage(map[uint32][]byte{
+		tagROOT: root,
+		tagMIDP: u64(uint64(mid.UnixMicro())),
+		tagRADI: u32(uint32(radius / time.Microsecond)),
+	})
+	dele := encodeMessage(map[uint32][]byte{
+		tagPUBK: s.deleKey.Public().(ed25519.PublicKey),
+		tagMINT: u64(uint64(s.mint.UnixMicro())),
+		tagMAXT: u64(uint64(s.maxt.UnixMicro())),
+	})
+	cert := encodeMessage(map[uint32][]byte{
+		tagSIG:  ed25519.Sign(s.rootKey, append(bytes.Clone(delegationContext), dele...)),
+		tagDELE: dele,
+	})
+	return encodeMessage(map[uint32][]byte{
+		tagSIG:  ed25519.Sign(s.deleKey, append(bytes.Clone(responseContext), srep...)),
+		tagPATH: path,
+		tagSREP: srep,
+		tagCERT: cert,
+		tagINDX: u32(index),
+	})
+}
+
+// newServer returns a testServer that sends reply's response to each
+// request's nonce, or nothing if it returns nil. reply is called from
+// the server's goroutine.
+func newServer(t *testing.T, pub ed25519.PublicKey, reply func(nonce []byte) []byte) *testServer {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 2048)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			req, err := parseMessage(buf[:n])
+			if err != nil || n < requestLen {
+				continue
+			}
+			if resp := reply(req[tagNONC]); resp != nil {
+				conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+	return &testServer{conn: conn, pub: pub}
+}
+
+func (s *testServer) server() Server {
+	return Server{Address: s.conn.LocalAddr().String(), PublicKey: s.pub}
+}
+
+// at returns a reply function for newServer that responds with s at
+// now, with radius.
+func at(s *signer, now func() time.Time, radius time.Duration) func([]byte) []byte {
+	return func(nonce []byte) []byte {
+		return s.respond(nonce, now(), radius)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	fc := clock.NewFake(clock.WithStart(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	s := newSigner(t)
+	mid := fc.Now().Add(time.Hour)
+	srv := newServer(t, s.publicKey(), at(s, func() time.Time { return mid }, time.Second))
+
+	r, err := Query(context.Background(), fc, srv.server())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Midpoint.Equal(mid) || r.Radius != time.Second || r.RTT != 0 || !r.Received.Equal(fc.Now()) {
+		t.Errorf("Query() = %+v, want %v with a radius of 1s", r, mid)
+	}
+	want := clock.Interval{Earliest: mid.Add(-time.Second), Latest: mid.Add(time.Second)}
+	if got := r.Interval(); got != want {
+		t.Errorf("Interval() = %v, want %v", got, want)
+	}
+}
+
+func TestQueryRejects(t *testing.T) {
+	fc := clock.NewFake(clock.WithStart(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	s := newSigner(t)
+	other := newSigner(t)
+	for _, tc := range []struct {
+		name  string
+		pub   ed25519.PublicKey
+		reply func(nonce []byte) []byte
+		error string
+	}{
+		{"wrong key", other.publicKey(), at(s, fc.Now, time.Second), "isn't signed by the server's public key"},
+		{"expired delegation", s.publicKey(), at(s, func() time.Time { return s.maxt.Add(time.Second) }, time.Second), "outside its delegation's validity"},
+		{"bad response signature", s.publicKey(), func(nonce []byte) []byte {
+			resp := s.respond(nonce, fc.Now(), time.Second)
+			msg, _ := parseMessage(resp)
+			msg[tagSIG][0] ^= 1
+			return resp
+		}, "isn't signed by the delegated key"},
+		{"missing fields", s.publicKey(), func([]byte) []byte {
+			return encodeMessage(map[uint32][]byte{tagSIG: make([]byte, 64)})
+		}, "missing fields"},
+		{"malformed", s.publicKey(), func([]byte) []byte { return []byte{1, 2, 3} }, "malformed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newServer(t, tc.pub, tc.reply)
+			_, err := Query(context.Background(), fc, srv.server())
+			if err == nil || !strings.Contains(err.Error(), tc.error) {
+				t.Errorf("Query error = %v, want one containing %q", err, tc.error)
+			}
+		})
+	}
+}
+
+func TestQueryIgnoresOtherNonces(t *testing.T) {
+	s := newSigner(t)
+	srv := newServer(t, s.publicKey(), func([]byte) []byte {
+		return s.respond(make([]byte, nonceLen), time.Now(), time.Second)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := Query(ctx, clock.NewFake(), srv.server()); err == nil {
+		t.Errorf("Query accepted a response to another nonce")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	msg := map[uint32][]byte{tagNONC: bytes.Repeat([]byte{1}, 8), tagPAD: {}, tagSIG: {2, 2, 2, 2}}
+	got, err := parseMessage(encodeMessage(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(msg) {
+		t.Fatalf("parsed %d fields, want %d", len(got), len(msg))
+	}
+	for tag, v := range msg {
+		if !bytes.Equal(got[tag], v) {
+			t.Errorf("field %#x = %x, want %x", tag, got[tag], v)
+		}
+	}
+	if got := len(newRequest(make([]byte, nonceLen))); got != requestLen {
+		t.Errorf("request is %d bytes, want %d", got, requestLen)
+	}
+
+	for _, b := range [][]byte{
+		{},
+		{1, 0, 0},
+		{9, 0, 0, 0},
+		// Two fields whose tags are out of order.
+		append(append(u32(2), u32(0)...), append(u32(tagPAD), u32(tagNONC)...)...),
+		// An offset past the end.
+		append(append(u32(2), u32(4)...), append(u32(tagNONC), u32(tagPAD)...)...),
+	} {
+		if _, err := parseMessage(b); err == nil {
+			t.Errorf("parseMessage(%x) didn't fail", b)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package clock
+
+// Scale returns a Clock that starts at base's current time and from then
+// on moves factor times as fast as base, such as for a soak test that
+// compresses a week of lease renewals into an hour. Its NowMonotonic
+// and the Timers, Tickers, AfterFunc functions and sleepers made with
+// it are scaled the same way, so that a Timer of one minute made with
+// Scale(Default(), 60) fires after a second of real time. Scale panics
+// if factor is not positive.
+func Scale(base Clock, factor float64) Clock {
+	if factor <= 0 {
+		panic("clock: non-positive factor for Scale")
+	}
+	return newDerived(base, 0, factor)
+}
@@ -0,0 +1,74 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScale(t *testing.T) {
+	fc := NewFake()
+	start := fc.Now()
+	fast := Scale(fc, 60)
+	slow := Scale(fc, 0.5)
+
+	fastTimer := fast.NewTimer(time.Minute)
+	slowTicker := slow.NewTicker(time.Second)
+	defer slowTicker.Stop()
+
+	fc.Add(time.Second)
+	if got, want := fast.Since(start), time.Minute; got != want {
+		t.Errorf("fast clock moved %v, want %v", got, want)
+	}
+	if got, want := slow.Since(start), 500*time.Millisecond; got != want {
+		t.Errorf("slow clock moved %v, want %v", got, want)
+	}
+	if got, want := fast.NowMonotonic(), time.Minute; got != want {
+		t.Errorf("fast clock's NowMonotonic() = %v, want %v", got, want)
+	}
+	if got, want := <-fastTimer.C(), start.Add(time.Minute); !got.Equal(want) {
+		t.Errorf("fast Timer sent %v, want %v", got, want)
+	}
+	select {
+	case <-slowTicker.C():
+		t.Errorf("slow Ticker ticked early")
+	default:
+	}
+	fc.Add(time.Second)
+	if got, want := <-slowTicker.C(), start.Add(time.Second); !got.Equal(want) {
+		t.Errorf("slow Ticker sent %v, want %v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Scale with a zero factor did not panic")
+		}
+	}()
+	Scale(fc, 0)
+}
+
+func TestScaleTimerStopMatchesFake(t *testing.T) {
+	// Stopping or resetting a Timer that fired but wasn't received
+	// reports true, as it does for the FakeClock's own Timers.
+	fc := NewFake(WithSynchronousAfterFunc())
+	for name, clk := range map[string]Clock{"FakeClock": fc, "Scale": Scale(fc, 2)} {
+		tm := clk.NewTimer(time.Second)
+		fc.Add(time.Second)
+		if !tm.Stop() {
+			t.Errorf("%s: Stop of a fired, unreceived Timer returned false", name)
+		}
+		if tm.Stop() {
+			t.Errorf("%s: second Stop returned true", name)
+		}
+		tm.Reset(time.Second)
+		fc.Add(time.Second)
+		if !tm.Reset(time.Second) {
+			t.Errorf("%s: Reset of a fired, unreceived Timer returned false", name)
+		}
+		select {
+		case <-tm.C():
+			t.Errorf("%s: Timer kept its time across Reset", name)
+		default:
+		}
+		tm.Stop()
+	}
+}
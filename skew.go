@@ -0,0 +1,204 @@
+package clock
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Skew is an estimate, by a SkewMeter, of how far one Clock's time is
+// from a reference Clock's and how quickly that is changing.
+type Skew struct {
+	// At is the reference clock's time at the latest sample.
+	At time.Time
+
+	// Offset is how far ahead of the reference clock the other clock's
+	// time is at At, and OffsetError how far either side of Offset it
+	// could be, with about 95% confidence.
+	Offset      time.Duration
+	OffsetError time.Duration
+
+	// Drift is how many microseconds the other clock gains on the
+	// reference clock for every second of the reference's, or loses if
+	// it is negative, as with the ppm given to Drift, and DriftError
+	// how far either side of Drift it could be, with about 95%
+	// confidence. Both are zero until there are two samples.
+	Drift      float64
+	DriftError float64
+
+	// Samples is the number of samples the estimate is from.
+	Samples int
+}
+
+// Bounds returns the smallest and largest offsets the Skew allows for,
+// Offset less and plus OffsetError.
+func (s Skew) Bounds() (lo, hi time.Duration) {
+	return s.Offset - s.OffsetError, s.Offset + s.OffsetError
+}
+
+// Interval returns the Interval that the reference clock's time is
+// within, according to s, when the other clock's time is t. Away from
+// At, Offset is corrected, and OffsetError widened, by Drift and
+// DriftError.
+func (s Skew) Interval(t time.Time) Interval {
+	elapsed := t.Round(0).Add(-s.Offset).Sub(s.At).Seconds()
+	offset := s.Offset + time.Duration(math.Round(s.Drift*elapsed*1e3))
+	uncertainty := s.OffsetError + time.Duration(math.Round(math.Abs(s.DriftError*elapsed*1e3)))
+	return IntervalAround(t.Add(-offset), uncertainty)
+}
+
+// SkewMeter estimates the offset and drift of one Clock against a
+// reference Clock from samples of both, such as to check that a clock
+// corrected by NTP converges on the source it follows, or to alert when
+// a service's time diverges from an authoritative one. It is safe for
+// concurrent use.
+type SkewMeter struct {
+	ref, clk Clock
+	window   int
+
+	mu      sync.Mutex
+	samples []skewSample
+}
+
+// skewSample is one reading of both clocks. at is the midpoint of the
+// reference clock's readings either side of the other clock's, and
+// width half the time between them, as the most the reading of the
+// other clock could be off from at by.
+type skewSample struct {
+	at     time.Time
+	offset time.Duration
+	width  time.Duration
+}
+
+// NewSkewMeter returns a SkewMeter that estimates how far clk is from
+// ref from, at most, the window latest samples. It panics if window is
+// less than 2.
+func NewSkewMeter(ref, clk Clock, window int) *SkewMeter {
+	if window < 2 {
+		panic("clock: SkewMeter window of less than 2 samples")
+	}
+	return &SkewMeter{ref: ref, clk: clk, window: window}
+}
+
+// Sample reads both clocks, reading the reference clock either side of
+// the other so as to bound the error from reading them at different
+// moments, and returns the estimate including the new sample.
+func (m *SkewMeter) Sample() Skew {
+	// Compare wall clock readings, since monotonic ones aren't
+	// comparable between clocks.
+	before := m.ref.Now().Round(0)
+	t := m.clk.Now().Round(0)
+	after := m.ref.Now().Round(0)
+	half := after.Sub(before) / 2
+	at := before.Add(half)
+	s := skewSample{at: at, offset: t.Sub(at), width: half}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.samples) == m.window {
+		copy(m.samples, m.samples[1:])
+		m.samples = m.samples[:len(m.samples)-1]
+	}
+	m.samples = append(m.samples, s)
+	return m.estimate()
+}
+
+// Estimate returns the estimate from the samples taken so far, or the
+// zero Skew if there are none.
+func (m *SkewMeter) Estimate() Skew {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.estimate()
+}
+
+// Reset discards the SkewMeter's samples, such as after either clock has
+// been stepped.
+func (m *SkewMeter) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.samples = nil
+}
+
+// Run calls Sample, and then again every interval on the reference
+// clock, calling report, if it isn't nil, with each estimate, until ctx
+// is done, when it returns ctx's error.
+func (m *SkewMeter) Run(ctx context.Context, interval time.Duration, report func(Skew)) error {
+	for {
+		s := m.Sample()
+		if report != nil {
+			report(s)
+		}
+		t := m.ref.NewTimer(interval)
+		select {
+		case <-t.C():
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// estimate fits a line to the samples' offsets by least squares. The
+// confidence bounds are two standard errors of the fit, and the
+// offset's also allows for the widest of the samples' own errors. It
+// must be called with m's lock held.
+func (m *SkewMeter) estimate() Skew {
+	n := len(m.samples)
+	if n == 0 {
+		return Skew{}
+	}
+	last := m.samples[n-1]
+	var width time.Duration
+	for _, s := range m.samples {
+		width = max(width, s.width)
+	}
+	if n == 1 {
+		return Skew{At: last.at, Offset: last.offset, OffsetError: width, Samples: 1}
+	}
+
+	// Work in seconds since the latest sample, so that the intercept
+	// is the offset at it.
+	var sx, sy float64
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	for i, s := range m.samples {
+		xs[i] = s.at.Sub(last.at).Seconds()
+		ys[i] = s.offset.Seconds()
+		sx += xs[i]
+		sy += ys[i]
+	}
+	mx, my := sx/float64(n), sy/float64(n)
+	var sxx, sxy float64
+	for i := range xs {
+		sxx += (xs[i] - mx) * (xs[i] - mx)
+		sxy += (xs[i] - mx) * (ys[i] - my)
+	}
+	if sxx == 0 {
+		// All the samples are at the same time, so there's no
+		// telling the drift.
+		return Skew{At: last.at, Offset: time.Duration(math.Round(my * 1e9)), OffsetError: width, Samples: n}
+	}
+	slope := sxy / sxx
+	intercept := my - slope*mx
+
+	var slopeErr, interceptErr float64
+	if n > 2 {
+		var ssr float64
+		for i := range xs {
+			r := ys[i] - (intercept + slope*xs[i])
+			ssr += r * r
+		}
+		sigma := math.Sqrt(ssr / float64(n-2))
+		slopeErr = 2 * sigma / math.Sqrt(sxx)
+		interceptErr = 2 * sigma * math.Sqrt(1/float64(n)+mx*mx/sxx)
+	}
+	return Skew{
+		At:          last.at,
+		Offset:      time.Duration(math.Round(intercept * 1e9)),
+		OffsetError: time.Duration(math.Round(interceptErr*1e9)) + width,
+		Drift:       slope * 1e6,
+		DriftError:  slopeErr * 1e6,
+		Samples:     n,
+	}
+}
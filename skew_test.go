@@ -0,0 +1,117 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSkewMeter(t *testing.T) {
+	fc := NewFakeAt(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := NewSkewMeter(fc, Offset(Drift(fc, 200), 2*time.Second), 10)
+	if got := m.Estimate(); got != (Skew{}) {
+		t.Errorf("Estimate with no samples = %+v, want the zero Skew", got)
+	}
+	s := m.Sample()
+	if s.Offset != 2*time.Second || s.OffsetError != 0 || s.Drift != 0 || s.Samples != 1 {
+		t.Errorf("first Sample = %+v, want an offset of 2s", s)
+	}
+	for i := 0; i < 20; i++ {
+		fc.Add(10 * time.Second)
+		s = m.Sample()
+	}
+	// 200 seconds at 200ppm is 40ms.
+	if want := 2*time.Second + 40*time.Millisecond; (s.Offset - want).Abs() > time.Microsecond {
+		t.Errorf("Offset = %v, want %v", s.Offset, want)
+	}
+	if math.Abs(s.Drift-200) > 0.01 {
+		t.Errorf("Drift = %v, want 200", s.Drift)
+	}
+	if s.OffsetError > time.Microsecond || s.DriftError > 0.01 {
+		t.Errorf("errors of %v and %vppm for exact clocks, want about none", s.OffsetError, s.DriftError)
+	}
+	if s.Samples != 10 {
+		t.Errorf("Samples = %d, want the window of 10", s.Samples)
+	}
+	if !s.At.Equal(fc.Now()) {
+		t.Errorf("At = %v, want %v", s.At, fc.Now())
+	}
+
+	m.Reset()
+	if got := m.Estimate(); got.Samples != 0 {
+		t.Errorf("Samples after Reset = %d, want 0", got.Samples)
+	}
+}
+
+func TestSkewMeterNoise(t *testing.T) {
+	ref := NewFakeAt(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	clk := NewFakeAt(ref.Now())
+	m := NewSkewMeter(ref, clk, 100)
+	var s Skew
+	for i := 0; i < 50; i++ {
+		// 1ms ahead, gaining 10ppm, give or take 100µs.
+		noise := time.Duration(i%5-2) * 50 * time.Microsecond
+		clk.Set(ref.Now().Add(time.Millisecond + time.Duration(i)*10*time.Microsecond + noise))
+		s = m.Sample()
+		ref.Add(time.Second)
+	}
+	want := time.Millisecond + 490*time.Microsecond
+	if lo, hi := s.Bounds(); want < lo || want > hi {
+		t.Errorf("Bounds = %v, %v, want them to include %v", lo, hi, want)
+	}
+	if s.OffsetError <= 0 || s.OffsetError > 100*time.Microsecond {
+		t.Errorf("OffsetError = %v, want a little more than none", s.OffsetError)
+	}
+	if math.Abs(s.Drift-10) > s.DriftError || s.DriftError <= 0 {
+		t.Errorf("Drift = %v ± %v, want it to include 10", s.Drift, s.DriftError)
+	}
+}
+
+func TestSkewMeterRun(t *testing.T) {
+	fc := NewFake()
+	m := NewSkewMeter(fc, Offset(fc, time.Second), 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	reports := make(chan Skew)
+	done := make(chan error)
+	go func() {
+		done <- m.Run(ctx, time.Minute, func(s Skew) { reports <- s })
+	}()
+	for i := 1; i <= 3; i++ {
+		if s := <-reports; s.Samples != i || s.Offset != time.Second {
+			t.Errorf("report %d = %+v, want %d samples with an offset of 1s", i, s, i)
+		}
+		fc.BlockUntil(1)
+		fc.Add(time.Minute)
+	}
+	<-reports
+	fc.BlockUntil(1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestNewSkewMeterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewSkewMeter with a window of 1 didn't panic")
+		}
+	}()
+	NewSkewMeter(NewFake(), NewFake(), 1)
+}
+
+func TestSkewInterval(t *testing.T) {
+	at := time.Date(2012, 9, 1, 0, 0, 0, 0, time.UTC)
+	s := Skew{At: at, Offset: time.Second, OffsetError: time.Millisecond, Drift: 100, DriftError: 10}
+	if got, want := s.Interval(at.Add(time.Second)), IntervalAround(at, time.Millisecond); got != want {
+		t.Errorf("Interval at At = %v, want %v", got, want)
+	}
+	// 100 seconds later, at 100±10ppm, the offset is 10±1ms more.
+	later := at.Add(101 * time.Second)
+	want := IntervalAround(at.Add(100*time.Second-10*time.Millisecond), 2*time.Millisecond)
+	if got := s.Interval(later); got != want {
+		t.Errorf("Interval 100s later = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package clock
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler returns an slog.Handler that stamps each record with
+// clk's time before passing it to h, so that logs written in tests
+// carry fake times and can be compared against golden files. Records
+// with a zero Time, which handlers leave unstamped, are passed on
+// unchanged.
+func SlogHandler(h slog.Handler, clk Clock) slog.Handler {
+	return &slogHandler{h: h, clk: clk}
+}
+
+type slogHandler struct {
+	h   slog.Handler
+	clk Clock
+}
+
+func (s *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return s.h.Enabled(ctx, level)
+}
+
+func (s *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !r.Time.IsZero() {
+		r.Time = s.clk.Now()
+	}
+	return s.h.Handle(ctx, r)
+}
+
+func (s *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &slogHandler{h: s.h.WithAttrs(attrs), clk: s.clk}
+}
+
+func (s *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{h: s.h.WithGroup(name), clk: s.clk}
+}
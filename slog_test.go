@@ -0,0 +1,29 @@
+package clock
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSlogHandler(t *testing.T) {
+	fc := NewFake()
+	fc.Set(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	var buf bytes.Buffer
+	log := slog.New(SlogHandler(slog.NewTextHandler(&buf, nil), fc))
+
+	log.With("a", 1).WithGroup("g").Info("hello", "b", 2)
+	fc.Add(time.Second)
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "unstamped", 0)
+	if err := log.Handler().Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "time=2020-01-01T00:00:00.000Z level=INFO msg=hello a=1 g.b=2\n" +
+		"level=INFO msg=unstamped\n"
+	if got := buf.String(); got != want {
+		t.Errorf("logged\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+package clock
+
+import "time"
+
+// smearWindow is how long a leap second is smeared over, as UTC would
+// count it: from noon UTC on the day of the leap second to noon the
+// day after.
+const smearWindow = 24 * time.Hour
+
+// Smear returns a TAIClock whose time is base's, but with each leap
+// second in table, or in LeapSeconds if table is nil, smeared the way
+// Google's and Amazon's public NTP servers do: rather than repeating or
+// skipping a second, the clock runs slow, or fast for a negative leap
+// second, by the same amount over the 24 hours from the noon UTC before
+// the leap second to the noon after, by about 11.6ppm, so that it is
+// never more than a second from UTC and never steps. Outside those
+// windows, its time is base's.
+//
+// Smear works out how much time has passed from base's time in TAI, so
+// base should either not step for leap seconds or, as a FakeClock made
+// with WithLeapSeconds does, be a TAIClock that tells the repeated
+// second apart. Its NowMonotonic and its Timers, Tickers and sleepers
+// are base's, and so are the times they send. AfterAt, NewTimerAt and
+// SleepUntil take deadlines in the Smear clock's time, and wait for as
+// long as there is until then in TAI.
+func Smear(base Clock, table *LeapTable) TAIClock {
+	if table == nil {
+		table = LeapSeconds
+	}
+	return &smeared{Clock: base, table: table}
+}
+
+type smeared struct {
+	Clock
+	table *LeapTable
+}
+
+func (s *smeared) NowTAI() TAI {
+	if tc, ok := s.Clock.(TAIClock); ok {
+		return tc.NowTAI()
+	}
+	return s.table.ToTAI(s.Clock.Now())
+}
+
+// window returns where the smear of the i'th entry of the table starts
+// in UTC and in TAI, and how long it lasts in TAI, which is a leap
+// second longer, or shorter, than in UTC.
+func (s *smeared) window(i int) (start time.Time, startTAI TAI, length time.Duration) {
+	l := s.table.leaps[i]
+	step := time.Duration(l.TAIOffset-s.table.leaps[i-1].TAIOffset) * time.Second
+	start = l.Time.Add(-smearWindow / 2)
+	startTAI = TAI{start.Add(time.Duration(s.table.leaps[i-1].TAIOffset) * time.Second)}
+	return start, startTAI, smearWindow + step
+}
+
+// smear returns the smeared UTC time of the TAI time a.
+func (s *smeared) smear(a TAI) time.Time {
+	for i := 1; i < len(s.table.leaps); i++ {
+		start, startTAI, length := s.window(i)
+		d := a.Sub(startTAI)
+		if d < 0 || d >= length {
+			continue
+		}
+		return start.Add(time.Duration(float64(d) * float64(smearWindow) / float64(length)))
+	}
+	t, _ := s.table.FromTAI(a)
+	return t
+}
+
+// until returns how long it is, in TAI, until the Smear clock's time
+// is t.
+func (s *smeared) until(t time.Time) time.Duration {
+	t = t.Round(0).UTC()
+	a := s.table.ToTAI(t)
+	for i := 1; i < len(s.table.leaps); i++ {
+		start, startTAI, length := s.window(i)
+		d := t.Sub(start)
+		if d < 0 || d >= smearWindow {
+			continue
+		}
+		a = startTAI.Add(time.Duration(float64(d) * float64(length) / float64(smearWindow)))
+		break
+	}
+	return a.Sub(s.NowTAI())
+}
+
+func (s *smeared) Now() time.Time {
+	loc := s.Clock.Now().Location()
+	return s.smear(s.NowTAI()).In(loc)
+}
+
+func (s *smeared) NowUnix() int64 {
+	return s.Now().Unix()
+}
+
+func (s *smeared) NowUnixMilli() int64 {
+	return s.Now().UnixMilli()
+}
+
+func (s *smeared) NowUnixNano() int64 {
+	return s.Now().UnixNano()
+}
+
+func (s *smeared) Since(t time.Time) time.Duration {
+	return s.Now().Sub(t)
+}
+
+func (s *smeared) Until(t time.Time) time.Duration {
+	return t.Sub(s.Now())
+}
+
+func (s *smeared) SleepUntil(t time.Time) {
+	s.Clock.Sleep(s.until(t))
+}
+
+func (s *smeared) AfterAt(t time.Time) <-chan time.Time {
+	return s.Clock.After(s.until(t))
+}
+
+func (s *smeared) NewTimerAt(t time.Time) Timer {
+	return s.Clock.NewTimer(s.until(t))
+}
+
+// FakeSmearClock is a FakeClock that steps for leap seconds, as
+// WithLeapSeconds makes it, along with a Clock that smears them, for
+// testing code during a smear and systems that mix smeared and
+// unsmeared clocks.
+type FakeSmearClock interface {
+	FakeClock
+
+	// Smeared returns the clock's time smeared, as by Smear.
+	Smeared() TAIClock
+}
+
+// NewFakeSmear returns a FakeSmearClock made by NewFake with opts and
+// WithLeapSeconds(table), whose Smeared clock smears the leap seconds
+// in table. If table is nil, it is LeapSeconds.
+func NewFakeSmear(table *LeapTable, opts ...Option) FakeSmearClock {
+	if table == nil {
+		table = LeapSeconds
+	}
+	fc := NewFake(append(opts[:len(opts):len(opts)], WithLeapSeconds(table))...)
+	return fakeSmearClock{fc, Smear(fc, table)}
+}
+
+type fakeSmearClock struct {
+	FakeClock
+	smeared TAIClock
+}
+
+func (f fakeSmearClock) Smeared() TAIClock {
+	return f.smeared
+}
@@ -0,0 +1,93 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSmear(t *testing.T) {
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := leap.Add(-12 * time.Hour)
+	fc := NewFakeSmear(nil, WithStart(start.Add(-time.Hour)))
+	sc := fc.Smeared()
+
+	for _, tc := range []struct {
+		add  time.Duration
+		want time.Time
+	}{
+		{0, start.Add(-time.Hour)},
+		{time.Hour, start},
+		// Halfway through the smear, half the leap second is smeared.
+		{12*time.Hour + 500*time.Millisecond, leap},
+		{12*time.Hour + 500*time.Millisecond, leap.Add(12 * time.Hour)},
+		{time.Hour, leap.Add(13 * time.Hour)},
+	} {
+		fc.Add(tc.add)
+		if got := sc.Now(); !got.Equal(tc.want) {
+			t.Errorf("Now at %v = %v, want %v", fc.Now(), got, tc.want)
+		}
+	}
+}
+
+func TestSmearSteady(t *testing.T) {
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := NewFakeSmear(nil, WithStart(leap.Add(-10*time.Second)))
+	sc := fc.Smeared()
+	last := sc.Now()
+	for i := 0; i < 200; i++ {
+		fc.Add(100 * time.Millisecond)
+		now := sc.Now()
+		if d := now.Sub(last); d <= 99*time.Millisecond || d >= 100*time.Millisecond {
+			t.Fatalf("Smear moved by %v at %v for 100ms, want a little less", d, fc.Now())
+		}
+		if off := now.Sub(fc.Now()); off < -time.Second || off > time.Second {
+			t.Fatalf("Smear is %v from %v, more than the leap second", off, fc.Now())
+		}
+		last = now
+	}
+}
+
+func TestSmearNegative(t *testing.T) {
+	leap := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	lt, err := NewLeapTable([]LeapSecond{{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37}, {leap, 36}}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc := NewFakeSmear(lt, WithStart(leap.Add(-12*time.Hour)))
+	fc.Add(12*time.Hour - 500*time.Millisecond)
+	if got := fc.Smeared().Now(); !got.Equal(leap) {
+		t.Errorf("Now halfway through a negative smear = %v, want %v", got, leap)
+	}
+}
+
+func TestSmearAfterAt(t *testing.T) {
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := NewFakeSmear(nil, WithStart(leap.Add(-12*time.Hour)))
+	sc := fc.Smeared()
+	ch := sc.AfterAt(leap)
+	fc.Add(12 * time.Hour)
+	select {
+	case <-ch:
+		t.Fatal("AfterAt fired before the Smear clock reached its deadline")
+	default:
+	}
+	fc.Add(500 * time.Millisecond)
+	select {
+	case <-ch:
+	default:
+		t.Fatalf("AfterAt(%v) didn't fire at %v", leap, sc.Now())
+	}
+}
+
+func TestSmearBase(t *testing.T) {
+	// A base that isn't a TAIClock is converted with the table.
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := NewFakeAt(leap.Add(13 * time.Hour))
+	sc := Smear(ReadOnly(fc), nil)
+	if got := sc.Now(); !got.Equal(fc.Now()) {
+		t.Errorf("Now after the smear = %v, want %v", got, fc.Now())
+	}
+	if got, want := sc.NowTAI(), LeapSeconds.ToTAI(fc.Now()); !got.Equal(want) {
+		t.Errorf("NowTAI = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,31 @@
+package clock
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SourceDateEpoch returns a Clock fixed, as by Fixed, at the time given
+// by the SOURCE_DATE_EPOCH environment variable, in seconds since the
+// Unix epoch, if it is set, and Default otherwise. Build tools that
+// stamp what they make with the time can use it to make reproducible
+// builds, as described at https://reproducible-builds.org/specs/source-date-epoch/.
+// It returns an error if the variable is set but isn't a whole number
+// of seconds.
+func SourceDateEpoch() (Clock, error) {
+	return sourceDateEpoch(os.Getenv)
+}
+
+func sourceDateEpoch(getenv func(string) string) (Clock, error) {
+	v := getenv("SOURCE_DATE_EPOCH")
+	if v == "" {
+		return Default(), nil
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("clock: invalid SOURCE_DATE_EPOCH %q: must be a whole number of seconds", v)
+	}
+	return Fixed(time.Unix(secs, 0).UTC()), nil
+}
@@ -0,0 +1,28 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSourceDateEpoch(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+	if clk, err := sourceDateEpoch(getenv); err != nil || clk != Default() {
+		t.Errorf("sourceDateEpoch without the variable = %v, %v, want Default(), nil", clk, err)
+	}
+
+	env["SOURCE_DATE_EPOCH"] = "1700000000"
+	clk, err := sourceDateEpoch(getenv)
+	if err != nil {
+		t.Fatalf("sourceDateEpoch: %v", err)
+	}
+	if got, want := clk.Now(), time.Unix(1700000000, 0).UTC(); got != want {
+		t.Errorf("Now() = %v, want %v", got, want)
+	}
+
+	env["SOURCE_DATE_EPOCH"] = "2023-11-14"
+	if _, err := sourceDateEpoch(getenv); err == nil {
+		t.Errorf("sourceDateEpoch with a malformed variable returned no error")
+	}
+}
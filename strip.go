@@ -0,0 +1,33 @@
+package clock
+
+import "time"
+
+// StripMonotonic returns a Clock whose Now returns the same times as
+// clk's with any monotonic clock reading removed, as if each had been
+// marshaled and unmarshaled. Since and Until then compare times by
+// their wall clock readings alone.
+//
+// Comparisons of times that do and don't carry a monotonic reading
+// can disagree with comparisons of times that all lack one, so code
+// that round-trips times through storage or the network can behave
+// differently in production than in tests that never do. Using
+// StripMonotonic(Default()) in those tests brings that out.
+func StripMonotonic(clk Clock) Clock {
+	return stripMonotonic{clk}
+}
+
+type stripMonotonic struct {
+	Clock
+}
+
+func (s stripMonotonic) Now() time.Time {
+	return s.Clock.Now().Round(0)
+}
+
+func (s stripMonotonic) Since(t time.Time) time.Duration {
+	return s.Now().Sub(t)
+}
+
+func (s stripMonotonic) Until(t time.Time) time.Duration {
+	return t.Sub(s.Now())
+}
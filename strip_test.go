@@ -0,0 +1,27 @@
+package clock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStripMonotonic(t *testing.T) {
+	clk := StripMonotonic(Default())
+	now := clk.Now()
+	// A time's String only includes "m=" when it has a monotonic
+	// clock reading.
+	if strings.Contains(now.String(), "m=") {
+		t.Errorf("Now returned a time with a monotonic clock reading: %s", now)
+	}
+	if !strings.Contains(time.Now().String(), "m=") {
+		t.Fatalf("time.Now should have a monotonic clock reading")
+	}
+
+	fc := NewFake()
+	clk = StripMonotonic(fc)
+	fc.Add(time.Hour)
+	if got := clk.Since(time.Unix(0, 0)); got != time.Hour {
+		t.Errorf("Since: got %v, want %v", got, time.Hour)
+	}
+}
@@ -0,0 +1,65 @@
+//go:build go1.25
+
+package clock
+
+import (
+	"testing/synctest"
+	"time"
+)
+
+// BubbleClock is a Clock for tests run in a testing/synctest bubble. Its
+// time is the bubble's, which the time package, and so Default, already
+// uses inside one, and moving it moves the bubble's time. Tests moving
+// from a FakeClock to synctest can use it in place of the FakeClock's
+// Add and Set, so that there is only one fake time in the test, instead
+// of a FakeClock that goroutines sleeping on the bubble's time never see
+// move.
+type BubbleClock interface {
+	Clock
+
+	// Add moves the bubble's time forward by d, as Add on a FakeClock
+	// does: everything waiting on it up to then fires in order, and Add
+	// returns once every other goroutine in the bubble is durably
+	// blocked. Add panics if d is negative, since the bubble's time
+	// can't go backwards, or if not called from inside a bubble.
+	Add(d time.Duration)
+
+	// Set moves the bubble's time forward to t, as Add does. It panics
+	// if t is before the bubble's time.
+	Set(t time.Time)
+
+	// Wait waits until every other goroutine in the bubble is durably
+	// blocked. It is synctest.Wait.
+	Wait()
+}
+
+// NewBubble returns a BubbleClock. Its Clock methods are those of the
+// system clock, so it must only be used inside a bubble. Like
+// testing/synctest, it needs Go 1.25 or later.
+func NewBubble() BubbleClock {
+	return bubble{sysClock{}}
+}
+
+type bubble struct {
+	sysClock
+}
+
+func (b bubble) Add(d time.Duration) {
+	if d < 0 {
+		panic("clock: negative duration for BubbleClock.Add")
+	}
+	time.Sleep(d)
+	synctest.Wait()
+}
+
+func (b bubble) Set(t time.Time) {
+	d := time.Until(t)
+	if d < 0 {
+		panic("clock: time before the bubble's for BubbleClock.Set")
+	}
+	b.Add(d)
+}
+
+func (b bubble) Wait() {
+	synctest.Wait()
+}
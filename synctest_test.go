@@ -0,0 +1,46 @@
+//go:build go1.25
+
+package clock
+
+import (
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestBubble(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		clk := NewBubble()
+		start := clk.Now()
+		if !start.Equal(time.Now()) {
+			t.Errorf("Now() = %v, want the bubble's time %v", start, time.Now())
+		}
+		tm := clk.NewTimer(time.Second)
+		var woke time.Time
+		go func() {
+			Default().Sleep(2 * time.Second)
+			woke = clk.Now()
+		}()
+
+		clk.Add(time.Second)
+		select {
+		case at := <-tm.C():
+			if want := start.Add(time.Second); !at.Equal(want) {
+				t.Errorf("Timer sent %v, want %v", at, want)
+			}
+		default:
+			t.Errorf("Timer had not fired once Add returned")
+		}
+		clk.Set(start.Add(2 * time.Second))
+		if want := start.Add(2 * time.Second); !woke.Equal(want) {
+			t.Errorf("sleeper on Default woke at %v, want %v", woke, want)
+		}
+
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Set to an earlier time did not panic")
+			}
+		}()
+		clk.Set(start)
+	})
+}
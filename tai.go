@@ -0,0 +1,115 @@
+package clock
+
+import "time"
+
+// TAI is a time in International Atomic Time, a time scale with no leap
+// seconds, which is ahead of UTC by the number of leap seconds since
+// 1972, plus ten. Unlike times in UTC, TAI times are exactly as far
+// apart as the SI seconds between them, even across leap seconds, which
+// suits scientific and telemetry data. The zero TAI is January 1, year
+// 1, 00:00:00 TAI. LeapTables convert TAIs to and from UTC time.Times.
+type TAI struct {
+	// t's reading, in UTC, is the TAI time's, and it has no monotonic
+	// clock reading.
+	t time.Time
+}
+
+// TAIFromReading returns the TAI time whose date and time of day are
+// t's in UTC. It is for TAI times read from sources, such as
+// CLOCK_TAI or serialized data, that hold them as if they were UTC
+// times; TAIs of UTC times come from a LeapTable's ToTAI.
+func TAIFromReading(t time.Time) TAI {
+	return TAI{t.Round(0).UTC()}
+}
+
+// Reading returns a time.Time in UTC whose date and time of day are
+// a's, for storing a or formatting it with layouts TAI's Format can't.
+// It isn't the UTC time of a, which is a LeapTable's FromTAI.
+func (a TAI) Reading() time.Time {
+	return a.t
+}
+
+// Add returns a plus d.
+func (a TAI) Add(d time.Duration) TAI {
+	return TAI{a.t.Add(d)}
+}
+
+// Sub returns a-b, the time elapsed from b to a.
+func (a TAI) Sub(b TAI) time.Duration {
+	return a.t.Sub(b.t)
+}
+
+// Before reports whether a is before b.
+func (a TAI) Before(b TAI) bool {
+	return a.t.Before(b.t)
+}
+
+// After reports whether a is after b.
+func (a TAI) After(b TAI) bool {
+	return a.t.After(b.t)
+}
+
+// Equal reports whether a and b are the same time.
+func (a TAI) Equal(b TAI) bool {
+	return a.t.Equal(b.t)
+}
+
+// Compare returns -1 if a is before b, +1 if a is after b, and 0 if
+// they are the same time.
+func (a TAI) Compare(b TAI) int {
+	return a.t.Compare(b.t)
+}
+
+// IsZero reports whether a is the zero TAI.
+func (a TAI) IsZero() bool {
+	return a.t.IsZero()
+}
+
+// Unix returns a as the number of seconds since January 1, 1970
+// 00:00:00 TAI.
+func (a TAI) Unix() int64 {
+	return a.t.Unix()
+}
+
+// UnixNano returns a as the number of nanoseconds since January 1, 1970
+// 00:00:00 TAI.
+func (a TAI) UnixNano() int64 {
+	return a.t.UnixNano()
+}
+
+// Format formats a's reading as time.Time's Format does. Layouts with
+// time zones format it as if it were UTC.
+func (a TAI) Format(layout string) string {
+	return a.t.Format(layout)
+}
+
+// String returns a formatted as "2006-01-02 15:04:05.999999999 TAI".
+func (a TAI) String() string {
+	return a.t.Format("2006-01-02 15:04:05.999999999") + " TAI"
+}
+
+// TAIClock is a Clock that can also tell the time in TAI.
+type TAIClock interface {
+	Clock
+
+	// NowTAI returns the Clock's current time in TAI.
+	NowTAI() TAI
+}
+
+// NewTAIClock returns a TAIClock whose time is base's, converted to TAI
+// by NowTAI with table, or with LeapSeconds if table is nil.
+func NewTAIClock(base Clock, table *LeapTable) TAIClock {
+	if table == nil {
+		table = LeapSeconds
+	}
+	return taiClock{base, table}
+}
+
+type taiClock struct {
+	Clock
+	table *LeapTable
+}
+
+func (c taiClock) NowTAI() TAI {
+	return c.table.ToTAI(c.Now())
+}
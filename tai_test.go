@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTAI(t *testing.T) {
+	utc := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	a := LeapSeconds.ToTAI(utc)
+	if got, want := a.String(), "2020-05-01 12:00:37 TAI"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := a.Reading(), utc.Add(37*time.Second); !got.Equal(want) {
+		t.Errorf("Reading() = %v, want %v", got, want)
+	}
+	if got := TAIFromReading(a.Reading()); !got.Equal(a) {
+		t.Errorf("TAIFromReading(Reading()) = %v, want %v", got, a)
+	}
+	if got, want := a.Unix(), utc.Unix()+37; got != want {
+		t.Errorf("Unix() = %d, want %d", got, want)
+	}
+	if got, want := a.UnixNano(), utc.UnixNano()+37e9; got != want {
+		t.Errorf("UnixNano() = %d, want %d", got, want)
+	}
+	if got, want := a.Format(time.Kitchen), "12:00PM"; got != want {
+		t.Errorf("Format(Kitchen) = %q, want %q", got, want)
+	}
+
+	b := a.Add(time.Second)
+	if b.Sub(a) != time.Second || !a.Before(b) || !b.After(a) || a.Compare(b) != -1 || a.Equal(b) {
+		t.Errorf("%v and %v compare wrongly", a, b)
+	}
+	if a.IsZero() || !(TAI{}).IsZero() {
+		t.Errorf("IsZero is wrong")
+	}
+}
+
+func TestTAIClock(t *testing.T) {
+	fc := NewFakeAt(time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC))
+	clk := NewTAIClock(fc, nil)
+	start := clk.NowTAI()
+	if got, want := start.String(), "2017-01-01 00:00:35 TAI"; got != want {
+		t.Errorf("NowTAI() = %v, want %v", got, want)
+	}
+	fc.Add(time.Second)
+	// The base clock, like most, skips the leap second, so the TAI
+	// time jumps over it too.
+	if got := clk.NowTAI().Sub(start); got != 2*time.Second {
+		t.Errorf("NowTAI() moved %v over the leap second, want 2s", got)
+	}
+	if !clk.Now().Equal(fc.Now()) {
+		t.Errorf("Now() = %v, want the base clock's %v", clk.Now(), fc.Now())
+	}
+}
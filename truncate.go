@@ -0,0 +1,54 @@
+package clock
+
+import "time"
+
+// Truncate returns a Clock whose Now returns base's time rounded down to
+// a multiple of d since the zero time, as time.Time's Truncate does,
+// such as for coarse timestamps in privacy-sensitive logs or to resist
+// timing analysis. NowUnix, Since and Until are computed from those
+// coarse times too, but NowMonotonic and the times sent by the Clock's
+// Timers and Tickers are base's. If d is not positive, Now returns
+// base's times with only their monotonic clock readings removed.
+func Truncate(base Clock, d time.Duration) Clock {
+	return &coarsened{Clock: base, d: d}
+}
+
+// Round is like Truncate, but rounds base's times to the nearest
+// multiple of d, as time.Time's Round does, so that Now may be up to
+// d/2 ahead of base.
+func Round(base Clock, d time.Duration) Clock {
+	return &coarsened{Clock: base, d: d, round: true}
+}
+
+type coarsened struct {
+	Clock
+	d     time.Duration
+	round bool
+}
+
+func (c *coarsened) Now() time.Time {
+	if c.round {
+		return c.Clock.Now().Round(c.d)
+	}
+	return c.Clock.Now().Truncate(c.d)
+}
+
+func (c *coarsened) NowUnix() int64 {
+	return c.Now().Unix()
+}
+
+func (c *coarsened) NowUnixMilli() int64 {
+	return c.Now().UnixMilli()
+}
+
+func (c *coarsened) NowUnixNano() int64 {
+	return c.Now().UnixNano()
+}
+
+func (c *coarsened) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *coarsened) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
@@ -0,0 +1,36 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTruncate(t *testing.T) {
+	fc := NewFakeAt(time.Date(2024, time.March, 1, 10, 29, 45, 500, time.UTC))
+	tests := []struct {
+		clk  Clock
+		want time.Time
+	}{
+		{Truncate(fc, time.Second), time.Date(2024, time.March, 1, 10, 29, 45, 0, time.UTC)},
+		{Truncate(fc, time.Minute), time.Date(2024, time.March, 1, 10, 29, 0, 0, time.UTC)},
+		{Truncate(fc, time.Hour), time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)},
+		{Round(fc, time.Minute), time.Date(2024, time.March, 1, 10, 30, 0, 0, time.UTC)},
+		{Round(fc, time.Hour), time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)},
+		{Truncate(fc, 0), fc.Now()},
+	}
+	for i, tc := range tests {
+		if got := tc.clk.Now(); !got.Equal(tc.want) {
+			t.Errorf("#%d: Now() = %v, want %v", i, got, tc.want)
+		}
+		if got := tc.clk.NowUnixNano(); got != tc.want.UnixNano() {
+			t.Errorf("#%d: NowUnixNano() = %d, want %d", i, got, tc.want.UnixNano())
+		}
+		if got, want := tc.clk.Since(fc.Now()), tc.want.Sub(fc.Now()); got != want {
+			t.Errorf("#%d: Since(base's Now) = %v, want %v", i, got, want)
+		}
+	}
+
+	if got := Truncate(Default(), time.Nanosecond).Now(); got != got.Round(0) {
+		t.Errorf("Truncate left a monotonic clock reading on %v", got)
+	}
+}